@@ -8,6 +8,9 @@ import (
 
 	"context"
 	"database/sql"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
@@ -48,6 +51,12 @@ func main() {
 		slog.Error("error while getting logger", "err", err)
 	}
 
+	// SIGINT or SIGTERM drains requests and workers before exiting
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	server := server.NewServer(logger, db, cfg)
-	server.Run()
+	if err := server.Run(ctx); err != nil {
+		logger.Fatal(err)
+	}
 }
@@ -7,18 +7,31 @@ import (
 )
 
 type Config struct {
-	Server Server
-	Db     Database
-	JWT    JWT
-	/* SMS        SMS */
-	/* Redis      RedisConfig */
+	Server  Server
+	Db      Database
+	JWT     JWT
+	SMS     SMS
+	OTP     OTP
+	Phone   Phone
+	RBAC    RBAC
+	Account Account
+	Abuse   Abuse
+	Voice   Voice
+
 	Bun        BunConfig
+	Redis      RedisConfig
 	LoggerMode LoggerMode
 }
 
 type Server struct {
 	Port        string
 	Environment string
+	// TrustedProxies are the CIDR ranges of load balancers whose
+	// X-Forwarded-For is believed. Without any, the peer address is used.
+	TrustedProxies []string
+	// ShutdownTimeout bounds how long requests in flight may take to
+	// finish once the server is asked to stop, in seconds.
+	ShutdownTimeout int
 }
 
 type Database struct {
@@ -29,22 +42,212 @@ type Database struct {
 	Name     string
 	SSLMode  string
 }
+
+// JWT configures token signing. Tokens are signed with the key named by
+// SigningKeyID and verified against every key in Keys, so a new key can be
+// rolled out before the old one is retired. Without keys, tokens are signed
+// with the HS256 Secret.
 type JWT struct {
-	Secret    string
-	ExpiresIn int
+	Secret           string
+	ExpiresIn        int // access token lifetime in seconds
+	RefreshExpiresIn int // refresh token lifetime in seconds
+	SigningKeyID     string
+	Keys             []JWTKey
+	LegacyHS256      bool // still accept HS256 tokens signed with Secret
+}
+
+// JWTKey is an RSA or Ed25519 key in a PEM file. A private key can sign and
+// verify; a public key only verifies tokens issued before it was retired.
+type JWTKey struct {
+	ID   string // published as the token "kid"
+	Path string
 }
+
+// SMS lists the gateways used to deliver text messages. Providers are tried
+// in order; the next one is used once a provider exhausts its retries.
 type SMS struct {
-	Provider string
-	APIKey   string
+	Providers       []SMSProvider
+	DLT             SMSDLT
+	Outbox          SMSOutbox
+	DefaultLanguage string
+	CallbackToken   string // shared secret expected on delivery-report callbacks
+}
+
+// SMSDLT holds the TRAI DLT registration used on every transactional SMS.
+type SMSDLT struct {
+	EntityID    string
+	SenderID    string            // registered 6-character header
+	TemplateIDs map[string]string // keyed "<kind>_<language>", e.g. "otp_hi"
+}
+
+type SMSOutbox struct {
+	PollInterval int // in seconds
+	BatchSize    int
+	MaxAttempts  int
+	RetryBackoff int // in seconds, doubled after every failed attempt
+}
+
+type SMSProvider struct {
+	Name         string
+	Type         string // "http", "file" or "fake"
+	URL          string
+	Format       string // "json" or "form" request body, http only
+	APIKey       string
+	AuthHeader   string // header carrying APIKey, e.g. "authkey" for MSG91
+	SenderID     string
+	Path         string // file sink target, empty writes to stdout
+	Timeout      int    // in seconds
+	MaxRetries   int
+	RetryBackoff int // in milliseconds, doubled on every retry
+}
+
+// OTP is the one-time password policy for phone login.
+type OTP struct {
+	Length          int
+	TTL             int // in seconds
+	MaxAttempts     int // verification attempts per code
+	ResendCooldown  int // in seconds
+	MaxSendsPerHour int
+	LockoutDuration int    // in seconds, after MaxAttempts wrong codes
+	RegisterWindow  int    // in seconds, for registering with a verified code
+	DevCode         string // fixed code, honoured only in the development environment
+	Channels        OTPChannels
+}
+
+// OTPChannels are the ways a code can reach the user. SMS goes through the
+// SMS providers; voice calls and WhatsApp each use one HTTP gateway.
+type OTPChannels struct {
+	Default  string   // used when the client asks for none
+	Enabled  []string // "sms", "voice" and/or "whatsapp"
+	Voice    OTPVoice
+	WhatsApp OTPWhatsApp
+}
+
+// OTPVoice is a gateway that calls the user and reads the code out with
+// text-to-speech, e.g. Exotel or Plivo.
+type OTPVoice struct {
+	Name       string
+	URL        string
+	APIKey     string
+	AuthHeader string // header carrying APIKey, defaults to Authorization
+	CallerID   string // number the call comes from
+	Repeat     int    // times the code is read out
+	Timeout    int    // in seconds
+}
+
+// OTPWhatsApp sends codes as a WhatsApp Business authentication template.
+type OTPWhatsApp struct {
+	Name     string
+	URL      string // Cloud API messages endpoint of the sending phone number
+	Token    string // bearer access token
+	Template string // approved authentication template, one translation per language
+	Timeout  int    // in seconds
+}
+
+// Phone sets which countries' mobile numbers are accepted. Numbers typed
+// without a country code are read as DefaultRegion.
+type Phone struct {
+	DefaultRegion  string   // ISO 3166 code, e.g. "IN"
+	AllowedRegions []string // further regions for cross-border deployments
+}
+
+// RBAC seeds role assignment. Phones listed in BootstrapAdmins are made
+// admins when they log in, so the first admin needs no database access.
+type RBAC struct {
+	BootstrapAdmins []string
+}
+
+// Account configures self-service account deletion. A deleted account can be
+// restored during the grace period; after it, the purge worker erases it.
+type Account struct {
+	DeletionGracePeriod int // in seconds
+	PurgeInterval       int // in seconds
+	PurgeBatchSize      int
+}
+
+// Abuse configures the guard against SMS pumping. OTP sends are counted per
+// IP, device, number prefix and globally in fixed windows. As a count passes
+// each threshold the response escalates from a delay to a proof-of-work
+// challenge to a temporary block.
+type Abuse struct {
+	Store               string // counter backend, "memory" or "postgres"
+	Window              int    // in seconds
+	PrefixDigits        int    // leading digits of a number counted as one prefix, country code included
+	IP                  AbuseLimits
+	Device              AbuseLimits
+	Prefix              AbuseLimits
+	Global              AbuseLimits
+	DelayStep           int // in milliseconds, added for every send over the delay threshold
+	MaxDelay            int // in milliseconds
+	ChallengeDifficulty int // leading zero bits the solution hash must have
+	ChallengeTTL        int // in seconds
+	BlockDuration       int // in seconds
+	CleanupInterval     int // in seconds
+}
+
+// Voice configures live voice sessions, which are relayed between the
+// client's WebSocket and one opened to the AI service.
+type Voice struct {
+	PublicWSURL    string   // base URL clients reconnect to, e.g. wss://api.arogyasahayak.com/api/v1
+	AllowedOrigins []string // browser origins allowed to open a session socket
+
+	QueueSize      int    // frames queued per connection before Overflow applies
+	Overflow       string // "drop_oldest" drops queued audio, "disconnect" ends the session
+	PingInterval   int    // in seconds
+	PongTimeout    int    // in seconds; a connection silent for longer is dropped
+	WriteTimeout   int    // in seconds
+	MaxMessageSize int64  // in bytes
+
+	MaxDuration        int    // in seconds
+	IdleTimeout        int    // in seconds without audio or messages either way
+	ReapInterval       int    // in seconds
+	MaxSessionsPerUser int    // open sessions per user; 0 is unlimited
+	OnLimit            string // "reject" new sessions or "takeover" by ending the oldest
+
+	Store  string // session backend, "memory", "postgres" or "redis"
+	NodeID string // name of this instance in shared session stores, defaults to the hostname
+
+	ResumeWindow int // in seconds a dropped client has to reconnect; 0 ends the session at once
+	ReplayBuffer int // server messages kept for a client that reconnects; less than QueueSize
+
+	AI VoiceAI
+}
+
+// VoiceAI configures the client of the AI service. Sessions are spread
+// over Endpoints; one that fails BreakerThreshold times in a row, or its
+// health check, is skipped until it recovers.
+type VoiceAI struct {
+	Endpoints        []string // base URLs, e.g. http://ai-service:8000
+	Timeout          int      // in seconds, for the handshake and for the dial
+	MaxAttempts      int
+	BackoffBase      int // in milliseconds, doubled per attempt and jittered
+	BackoffMax       int // in milliseconds
+	BreakerThreshold int // consecutive failures that open an endpoint's breaker
+	BreakerCooldown  int // in seconds before an open breaker lets a trial through
+	HealthInterval   int // in seconds; 0 turns health checks off
+	HealthPath       string
+}
+
+// AbuseLimits are the sends per window after which each response starts.
+// Zero turns a response off.
+type AbuseLimits struct {
+	Delay     int
+	Challenge int
+	Block     int
 }
 
 type BunConfig struct {
 	DSN string
 }
 
+// RedisConfig points at the Redis server.
 type RedisConfig struct {
 	Addr     string
 	Password string
+	DB       int
+	Timeout  int  // in seconds
+	PoolSize int  // connections per instance; 0 is ten per CPU
+	TLS      bool // connect over TLS
 }
 
 type LoggerMode struct {
@@ -61,6 +264,7 @@ func LoadConfig(filename string) (*viper.Viper, error) {
 	v.SetConfigType("yaml")
 	v.AddConfigPath("config")
 	v.AutomaticEnv()
+	setDefaults(v)
 	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			return nil, errors.New("config file not found")
@@ -71,6 +275,65 @@ func LoadConfig(filename string) (*viper.Viper, error) {
 	return v, nil
 }
 
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("jwt.expiresin", 900)
+	v.SetDefault("jwt.refreshexpiresin", 604800)
+	v.SetDefault("otp.length", 6)
+	v.SetDefault("otp.ttl", 300)
+	v.SetDefault("otp.maxattempts", 3)
+	v.SetDefault("otp.resendcooldown", 60)
+	v.SetDefault("otp.maxsendsperhour", 3)
+	v.SetDefault("otp.lockoutduration", 900)
+	v.SetDefault("otp.registerwindow", 900)
+	v.SetDefault("otp.channels.default", "sms")
+	v.SetDefault("otp.channels.enabled", []string{"sms"})
+	v.SetDefault("otp.channels.voice.name", "voice")
+	v.SetDefault("otp.channels.voice.repeat", 2)
+	v.SetDefault("otp.channels.voice.timeout", 10)
+	v.SetDefault("otp.channels.whatsapp.name", "whatsapp")
+	v.SetDefault("otp.channels.whatsapp.timeout", 10)
+	v.SetDefault("phone.defaultregion", "IN")
+	v.SetDefault("account.deletiongraceperiod", 2592000)
+	v.SetDefault("account.purgeinterval", 3600)
+	v.SetDefault("account.purgebatchsize", 50)
+	v.SetDefault("abuse.store", "memory")
+	v.SetDefault("abuse.window", 3600)
+	v.SetDefault("abuse.prefixdigits", 7)
+	v.SetDefault("abuse.delaystep", 500)
+	v.SetDefault("abuse.maxdelay", 5000)
+	v.SetDefault("abuse.challengedifficulty", 18)
+	v.SetDefault("abuse.challengettl", 300)
+	v.SetDefault("abuse.blockduration", 3600)
+	v.SetDefault("abuse.cleanupinterval", 600)
+	v.SetDefault("voice.publicwsurl", "ws://localhost:8080/api/v1")
+	v.SetDefault("voice.queuesize", 256)
+	v.SetDefault("voice.overflow", "drop_oldest")
+	v.SetDefault("voice.pinginterval", 20)
+	v.SetDefault("voice.pongtimeout", 45)
+	v.SetDefault("voice.writetimeout", 10)
+	v.SetDefault("voice.maxmessagesize", 1<<20)
+	v.SetDefault("voice.maxduration", 1800)
+	v.SetDefault("voice.idletimeout", 120)
+	v.SetDefault("voice.reapinterval", 10)
+	v.SetDefault("voice.maxsessionsperuser", 1)
+	v.SetDefault("voice.onlimit", "takeover")
+	v.SetDefault("voice.store", "memory")
+	v.SetDefault("voice.resumewindow", 30)
+	v.SetDefault("voice.replaybuffer", 255)
+	v.SetDefault("voice.ai.endpoints", []string{"http://localhost:8000"})
+	v.SetDefault("voice.ai.timeout", 5)
+	v.SetDefault("voice.ai.maxattempts", 3)
+	v.SetDefault("voice.ai.backoffbase", 200)
+	v.SetDefault("voice.ai.backoffmax", 2000)
+	v.SetDefault("voice.ai.breakerthreshold", 5)
+	v.SetDefault("voice.ai.breakercooldown", 30)
+	v.SetDefault("voice.ai.healthinterval", 15)
+	v.SetDefault("voice.ai.healthpath", "/health")
+	v.SetDefault("server.shutdowntimeout", 30)
+	v.SetDefault("redis.addr", "localhost:6379")
+	v.SetDefault("redis.timeout", 5)
+}
+
 // Parse config file
 func ParseConfig(v *viper.Viper) (*Config, error) {
 	var c Config
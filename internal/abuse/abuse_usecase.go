@@ -0,0 +1,18 @@
+package abuse
+
+import (
+	"context"
+	"swasthAI/internal/abuse/models"
+
+	"github.com/google/uuid"
+)
+
+type AbuseUsecase interface {
+	// CheckOTPSend counts an OTP send to phone against the caller's IP,
+	// device, number prefix and the global budget. It may delay, demand a
+	// solved challenge or refuse the send.
+	CheckOTPSend(ctx context.Context, phone string) error
+	ListBlocks(ctx context.Context, dimension models.Dimension, limit, offset int) ([]*models.Block, error)
+	Block(ctx context.Context, input *models.BlockInput) (*models.Block, error)
+	Unblock(ctx context.Context, id uuid.UUID) (*models.Block, error)
+}
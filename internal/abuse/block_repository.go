@@ -0,0 +1,18 @@
+package abuse
+
+import (
+	"context"
+	"swasthAI/internal/abuse/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BlockRepository interface {
+	Create(ctx context.Context, block *models.Block) error
+	// FindActive returns the longest-running active block on any of subjects,
+	// or ErrAbuseBlockNotFound.
+	FindActive(ctx context.Context, subjects []models.Subject, now time.Time) (*models.Block, error)
+	ListActive(ctx context.Context, dimension models.Dimension, limit, offset int, now time.Time) ([]*models.Block, error)
+	Lift(ctx context.Context, id, liftedBy uuid.UUID, now time.Time) (*models.Block, error)
+}
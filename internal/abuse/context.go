@@ -0,0 +1,19 @@
+package abuse
+
+import (
+	"context"
+	"swasthAI/internal/abuse/models"
+)
+
+type solutionKey struct{}
+
+// WithSolution returns a copy of ctx carrying the client's challenge solution.
+func WithSolution(ctx context.Context, solution models.Solution) context.Context {
+	return context.WithValue(ctx, solutionKey{}, solution)
+}
+
+// SolutionFromContext returns the challenge solution sent with the request.
+func SolutionFromContext(ctx context.Context) (models.Solution, bool) {
+	solution, ok := ctx.Value(solutionKey{}).(models.Solution)
+	return solution, ok && solution.Token != ""
+}
@@ -0,0 +1,15 @@
+package abuse
+
+import (
+	"context"
+	"time"
+)
+
+// CounterStore keeps fixed-window counters. Windows are aligned to multiples
+// of their length, so every instance of the service counts into the same one.
+type CounterStore interface {
+	// Incr adds one to key in the window containing now and returns the new count.
+	Incr(ctx context.Context, key string, window time.Duration, now time.Time) (int64, error)
+	// DeleteExpired drops counters whose window ended before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) (int, error)
+}
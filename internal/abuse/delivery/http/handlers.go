@@ -0,0 +1,86 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"swasthAI/config"
+	"swasthAI/internal/abuse"
+	"swasthAI/internal/abuse/models"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/http_errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	uc     abuse.AbuseUsecase
+	logger *logger.Logger
+	Cfg    *config.Config
+}
+
+func NewHandler(uc abuse.AbuseUsecase, logger *logger.Logger, cfg *config.Config) *Handler {
+	return &Handler{uc: uc, logger: logger, Cfg: cfg}
+}
+
+func (h *Handler) ListBlocks(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+
+	blocks, err := h.uc.ListBlocks(c.Request().Context(), models.Dimension(c.QueryParam("dimension")), limit, offset)
+	if err != nil {
+		h.logger.Error("failed to list blocks", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"blocks": blocks,
+	})
+}
+
+func (h *Handler) CreateBlock(c echo.Context) error {
+	var input models.BlockInput
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error("failed to read input", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+
+	block, err := h.uc.Block(c.Request().Context(), &input)
+	if err != nil {
+		h.logger.Error("failed to create block", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusCreated, block)
+}
+
+func (h *Handler) DeleteBlock(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return http_errors.Send(c, domain_errors.ErrAbuseBlockNotFound)
+	}
+
+	block, err := h.uc.Unblock(c.Request().Context(), id)
+	if err != nil {
+		h.logger.Error("failed to lift block", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, block)
+}
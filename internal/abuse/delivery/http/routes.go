@@ -0,0 +1,16 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"swasthAI/internal/auth/models"
+	"swasthAI/internal/middleware"
+)
+
+func (h *Handler) MapAbuseRoutes(blocks *echo.Group, mw middleware.MiddlewareManager) {
+	blocks.Use(mw.RequireAuth)
+
+	blocks.GET("", h.ListBlocks, mw.Require(models.PermAbuseRead))
+	blocks.POST("", h.CreateBlock, mw.Require(models.PermAbuseManage))
+	blocks.DELETE("/:id", h.DeleteBlock, mw.Require(models.PermAbuseManage))
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Dimension is what OTP sends are counted by.
+type Dimension string
+
+const (
+	DimensionIP     Dimension = "ip"
+	DimensionDevice Dimension = "device"
+	DimensionPrefix Dimension = "prefix" // leading digits of the destination number
+	DimensionGlobal Dimension = "global"
+)
+
+// AllDimensions lists every dimension, checked in this order.
+var AllDimensions = []Dimension{DimensionIP, DimensionDevice, DimensionPrefix, DimensionGlobal}
+
+func (d Dimension) IsValid() bool {
+	for _, known := range AllDimensions {
+		if d == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Subject is one counted entity, e.g. the IP 203.0.113.7.
+type Subject struct {
+	Dimension Dimension
+	Value     string
+}
+
+// Key names the subject's counter.
+func (s Subject) Key() string {
+	return string(s.Dimension) + ":" + s.Value
+}
+
+// Level is how hard a subject is pushed back, from none to a block.
+type Level int
+
+const (
+	LevelNone Level = iota
+	LevelDelay
+	LevelChallenge
+	LevelBlock
+)
+
+// Block refuses OTP sends for a subject until it expires or an admin lifts
+// it. Blocks are placed automatically or by an admin (CreatedBy set).
+type Block struct {
+	bun.BaseModel `bun:"table:abuse_blocks"`
+
+	ID        uuid.UUID  `bun:",pk,type:uuid,default:gen_random_uuid()" json:"id"`
+	Dimension Dimension  `bun:",notnull" json:"dimension"`
+	Value     string     `bun:",notnull" json:"value"`
+	Reason    string     `bun:",notnull" json:"reason"`
+	Count     int64      `bun:",notnull,default:0" json:"count"` // sends counted when the block was placed
+	ExpiresAt time.Time  `bun:",notnull" json:"expires_at"`
+	CreatedBy *uuid.UUID `bun:",type:uuid" json:"created_by,omitempty"`
+	CreatedAt time.Time  `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+	LiftedAt  *time.Time `bun:",nullzero" json:"lifted_at,omitempty"`
+	LiftedBy  *uuid.UUID `bun:",type:uuid" json:"lifted_by,omitempty"`
+}
+
+// IsActive reports whether the block still refuses sends at now.
+func (b *Block) IsActive(now time.Time) bool {
+	return b.LiftedAt == nil && now.Before(b.ExpiresAt)
+}
+
+// Challenge is a proof of work the client must solve before the next send:
+// find a nonce so that SHA-256(token + nonce) starts with Difficulty zero bits.
+type Challenge struct {
+	Token      string    `json:"token"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Solution is a client's answer to a challenge, sent in the
+// X-Challenge-Token and X-Challenge-Nonce headers.
+type Solution struct {
+	Token string
+	Nonce string
+}
+
+// ChallengeClaims bind a challenge to the phone and IP it was issued for.
+type ChallengeClaims struct {
+	Phone      string `json:"phone"`
+	IP         string `json:"ip"`
+	Difficulty int    `json:"difficulty"`
+	jwt.RegisteredClaims
+}
+
+type BlockInput struct {
+	Dimension Dimension `json:"dimension" validate:"required"`
+	Value     string    `json:"value" validate:"required,max=64"`
+	Reason    string    `json:"reason" validate:"required,max=200"`
+	Duration  int       `json:"duration" validate:"required,gt=0"` // in seconds
+}
+
+// Counter is a fixed-window counter row of the Postgres counter store.
+type Counter struct {
+	bun.BaseModel `bun:"table:abuse_counters"`
+
+	Key         string    `bun:",pk"`
+	WindowStart time.Time `bun:",pk"`
+	Count       int64     `bun:",notnull"`
+	ExpiresAt   time.Time `bun:",notnull"`
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"swasthAI/internal/abuse/models"
+	"swasthAI/pkg/domain_errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type BlockRepository struct {
+	db *bun.DB
+}
+
+func NewBlockRepository(db *bun.DB) *BlockRepository {
+	return &BlockRepository{db: db}
+}
+
+func (r *BlockRepository) Create(ctx context.Context, block *models.Block) error {
+	_, err := r.db.NewInsert().Model(block).Returning("*").Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "blockRepo.Create.Insert")
+	}
+	return nil
+}
+
+func (r *BlockRepository) FindActive(ctx context.Context, subjects []models.Subject, now time.Time) (*models.Block, error) {
+	if len(subjects) == 0 {
+		return nil, domain_errors.ErrAbuseBlockNotFound
+	}
+	block := new(models.Block)
+	err := r.db.NewSelect().
+		Model(block).
+		Where("lifted_at IS NULL").
+		Where("expires_at > ?", now).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			for _, s := range subjects {
+				q = q.WhereOr("(dimension = ? AND value = ?)", s.Dimension, s.Value)
+			}
+			return q
+		}).
+		Order("expires_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain_errors.ErrAbuseBlockNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "blockRepo.FindActive.Select")
+	}
+	return block, nil
+}
+
+func (r *BlockRepository) ListActive(ctx context.Context, dimension models.Dimension, limit, offset int, now time.Time) ([]*models.Block, error) {
+	var blocks []*models.Block
+	q := r.db.NewSelect().
+		Model(&blocks).
+		Where("lifted_at IS NULL").
+		Where("expires_at > ?", now)
+	if dimension != "" {
+		q = q.Where("dimension = ?", dimension)
+	}
+	err := q.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "blockRepo.ListActive.Select")
+	}
+	return blocks, nil
+}
+
+// Lift ends an active block early.
+func (r *BlockRepository) Lift(ctx context.Context, id, liftedBy uuid.UUID, now time.Time) (*models.Block, error) {
+	block := new(models.Block)
+	err := r.db.NewUpdate().
+		Model(block).
+		Set("lifted_at = ?", now).
+		Set("lifted_by = ?", liftedBy).
+		Where("id = ?", id).
+		Where("lifted_at IS NULL").
+		Where("expires_at > ?", now).
+		Returning("*").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain_errors.ErrAbuseBlockNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "blockRepo.Lift.Update")
+	}
+	return block, nil
+}
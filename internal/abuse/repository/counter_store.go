@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"fmt"
+	"swasthAI/config"
+	"swasthAI/internal/abuse"
+
+	"github.com/uptrace/bun"
+)
+
+// NewCounterStore returns the counter backend named in cfg.Store.
+func NewCounterStore(cfg config.Abuse, db *bun.DB) (abuse.CounterStore, error) {
+	switch cfg.Store {
+	case "", "memory":
+		return NewMemoryCounterStore(), nil
+	case "postgres":
+		return NewPostgresCounterStore(db), nil
+	default:
+		return nil, fmt.Errorf("abuse: unknown counter store %q", cfg.Store)
+	}
+}
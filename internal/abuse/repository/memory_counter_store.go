@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryCounter struct {
+	windowStart time.Time
+	expiresAt   time.Time
+	count       int64
+}
+
+// MemoryCounterStore keeps counters in process memory. Counts are lost on
+// restart and not shared between instances, so it suits a single instance
+// or local development.
+type MemoryCounterStore struct {
+	mu       sync.Mutex
+	counters map[string]*memoryCounter
+}
+
+func NewMemoryCounterStore() *MemoryCounterStore {
+	return &MemoryCounterStore{counters: make(map[string]*memoryCounter)}
+}
+
+func (s *MemoryCounterStore) Incr(ctx context.Context, key string, window time.Duration, now time.Time) (int64, error) {
+	start := now.Truncate(window)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counters[key]
+	if !ok || !c.windowStart.Equal(start) {
+		c = &memoryCounter{windowStart: start, expiresAt: start.Add(window)}
+		s.counters[key] = c
+	}
+	c.count++
+	return c.count, nil
+}
+
+func (s *MemoryCounterStore) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for key, c := range s.counters {
+		if c.expiresAt.Before(before) {
+			delete(s.counters, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"swasthAI/internal/abuse/models"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+// PostgresCounterStore keeps counters in the abuse_counters table, shared by
+// every instance of the service.
+type PostgresCounterStore struct {
+	db *bun.DB
+}
+
+func NewPostgresCounterStore(db *bun.DB) *PostgresCounterStore {
+	return &PostgresCounterStore{db: db}
+}
+
+func (s *PostgresCounterStore) Incr(ctx context.Context, key string, window time.Duration, now time.Time) (int64, error) {
+	start := now.Truncate(window)
+	counter := &models.Counter{
+		Key:         key,
+		WindowStart: start,
+		Count:       1,
+		ExpiresAt:   start.Add(window),
+	}
+	err := s.db.NewInsert().
+		Model(counter).
+		On("CONFLICT (key, window_start) DO UPDATE").
+		Set("count = abuse_counters.count + 1").
+		Returning("count").
+		Scan(ctx, &counter.Count)
+	if err != nil {
+		return 0, errors.Wrap(err, "counterStore.Incr.Upsert")
+	}
+	return counter.Count, nil
+}
+
+func (s *PostgresCounterStore) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	res, err := s.db.NewDelete().
+		Model((*models.Counter)(nil)).
+		Where("expires_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "counterStore.DeleteExpired.Delete")
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "counterStore.DeleteExpired.RowsAffected")
+	}
+	return int(n), nil
+}
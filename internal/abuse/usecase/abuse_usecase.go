@@ -0,0 +1,371 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"math/bits"
+	"strings"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/abuse"
+	"swasthAI/internal/abuse/models"
+	"swasthAI/internal/auth"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/jwtkeys"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	maxListLimit = 100
+
+	// challengeAudience keeps challenge tokens from being accepted anywhere else.
+	challengeAudience = "otp-challenge"
+)
+
+type AbuseUsecase struct {
+	counters  abuse.CounterStore
+	blockRepo abuse.BlockRepository
+	keys      *jwtkeys.KeySet
+	cfg       config.Abuse
+	logger    logger.Logger
+
+	// sleep waits out a delay; replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func NewAbuseUsecase(counters abuse.CounterStore, blockRepo abuse.BlockRepository, keys *jwtkeys.KeySet, cfg config.Abuse, logger logger.Logger) *AbuseUsecase {
+	return &AbuseUsecase{counters: counters, blockRepo: blockRepo, keys: keys, cfg: cfg, logger: logger, sleep: sleepContext}
+}
+
+func (uc *AbuseUsecase) CheckOTPSend(ctx context.Context, phone string) error {
+	now := time.Now().UTC()
+	info := utils.ClientInfoFromContext(ctx)
+	subjects := uc.subjects(info, phone)
+
+	block, err := uc.blockRepo.FindActive(ctx, subjects, now)
+	if err == nil {
+		return domain_errors.ErrAbuseBlocked.WithRetryAfter(block.ExpiresAt.Sub(now))
+	}
+	if !errors.Is(err, domain_errors.ErrAbuseBlockNotFound) {
+		uc.logger.Error("failed to find block (abuseUC.CheckOTPSend.blockRepo.FindActive)", "error", err)
+		return appErrors.ErrDatabase
+	}
+
+	// every subject is counted, then the strictest response wins
+	level := models.LevelNone
+	var delay time.Duration
+	for _, s := range subjects {
+		limits := uc.limits(s.Dimension)
+		count, err := uc.counters.Incr(ctx, s.Key(), uc.window(), now)
+		if err != nil {
+			uc.logger.Error("failed to count send (abuseUC.CheckOTPSend.counters.Incr)", "error", err)
+			return appErrors.ErrDatabase
+		}
+
+		switch l := levelFor(count, limits); l {
+		case models.LevelBlock:
+			return uc.placeBlock(ctx, s, count, now)
+		case models.LevelDelay:
+			if d := uc.delayFor(count - int64(limits.Delay)); d > delay {
+				delay = d
+			}
+			fallthrough
+		default:
+			if l > level {
+				level = l
+			}
+		}
+	}
+
+	switch level {
+	case models.LevelChallenge:
+		if solution, ok := abuse.SolutionFromContext(ctx); ok {
+			err := uc.verifySolution(ctx, solution, phone, info.IP)
+			if err == nil {
+				return nil
+			}
+			uc.logger.Warn("rejected challenge solution", "ip", info.IP, "error", err)
+		}
+		return uc.challenge(phone, info.IP, now)
+	case models.LevelDelay:
+		return uc.sleep(ctx, delay)
+	}
+	return nil
+}
+
+// subjects lists what a send is counted against. Requests without a device
+// ID are only counted by IP.
+func (uc *AbuseUsecase) subjects(info utils.ClientInfo, phone string) []models.Subject {
+	subjects := make([]models.Subject, 0, len(models.AllDimensions))
+	if info.IP != "" {
+		subjects = append(subjects, models.Subject{Dimension: models.DimensionIP, Value: info.IP})
+	}
+	if info.DeviceID != "" {
+		subjects = append(subjects, models.Subject{Dimension: models.DimensionDevice, Value: info.DeviceID})
+	}
+	subjects = append(subjects,
+		models.Subject{Dimension: models.DimensionPrefix, Value: uc.prefix(phone)},
+		models.Subject{Dimension: models.DimensionGlobal, Value: "all"},
+	)
+	return subjects
+}
+
+// prefix returns the leading digits of an E.164 number, so that pumping a
+// range of premium numbers is counted together.
+func (uc *AbuseUsecase) prefix(phone string) string {
+	digits := strings.TrimPrefix(phone, "+")
+	n := uc.cfg.PrefixDigits
+	if n <= 0 {
+		n = 7
+	}
+	if len(digits) > n {
+		digits = digits[:n]
+	}
+	return "+" + digits
+}
+
+func (uc *AbuseUsecase) limits(d models.Dimension) config.AbuseLimits {
+	switch d {
+	case models.DimensionIP:
+		return uc.cfg.IP
+	case models.DimensionDevice:
+		return uc.cfg.Device
+	case models.DimensionPrefix:
+		return uc.cfg.Prefix
+	default:
+		return uc.cfg.Global
+	}
+}
+
+// levelFor returns the response once count sends were made in the window. A
+// zero threshold turns that step off.
+func levelFor(count int64, limits config.AbuseLimits) models.Level {
+	switch {
+	case limits.Block > 0 && count > int64(limits.Block):
+		return models.LevelBlock
+	case limits.Challenge > 0 && count > int64(limits.Challenge):
+		return models.LevelChallenge
+	case limits.Delay > 0 && count > int64(limits.Delay):
+		return models.LevelDelay
+	}
+	return models.LevelNone
+}
+
+// delayFor grows the delay by one step for every send over the threshold.
+func (uc *AbuseUsecase) delayFor(over int64) time.Duration {
+	delay := time.Duration(over) * time.Duration(uc.cfg.DelayStep) * time.Millisecond
+	if maxDelay := time.Duration(uc.cfg.MaxDelay) * time.Millisecond; maxDelay > 0 && delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+func (uc *AbuseUsecase) placeBlock(ctx context.Context, s models.Subject, count int64, now time.Time) error {
+	block := &models.Block{
+		Dimension: s.Dimension,
+		Value:     s.Value,
+		Reason:    "otp send limit exceeded",
+		Count:     count,
+		ExpiresAt: now.Add(uc.blockDuration()),
+	}
+	if err := uc.blockRepo.Create(ctx, block); err != nil {
+		uc.logger.Error("failed to create block (abuseUC.placeBlock.blockRepo.Create)", "error", err)
+		return appErrors.ErrDatabase
+	}
+	uc.logger.Warn("blocked otp sends", "dimension", s.Dimension, "value", s.Value, "count", count)
+	return domain_errors.ErrAbuseBlocked.WithRetryAfter(block.ExpiresAt.Sub(now))
+}
+
+func (uc *AbuseUsecase) blockDuration() time.Duration {
+	if uc.cfg.BlockDuration <= 0 {
+		return time.Hour
+	}
+	return time.Duration(uc.cfg.BlockDuration) * time.Second
+}
+
+func (uc *AbuseUsecase) window() time.Duration {
+	if uc.cfg.Window <= 0 {
+		return time.Hour
+	}
+	return time.Duration(uc.cfg.Window) * time.Second
+}
+
+func (uc *AbuseUsecase) challengeTTL() time.Duration {
+	if uc.cfg.ChallengeTTL <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(uc.cfg.ChallengeTTL) * time.Second
+}
+
+// challenge issues a proof-of-work challenge bound to the phone and IP.
+func (uc *AbuseUsecase) challenge(phone, ip string, now time.Time) error {
+	expiresAt := now.Add(uc.challengeTTL())
+	token, err := uc.keys.Sign(&models.ChallengeClaims{
+		Phone:      phone,
+		IP:         ip,
+		Difficulty: uc.cfg.ChallengeDifficulty,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Audience:  jwt.ClaimStrings{challengeAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	if err != nil {
+		uc.logger.Error("failed to sign challenge (abuseUC.challenge.keys.Sign)", "error", err)
+		return appErrors.ErrInternal
+	}
+	return domain_errors.ErrChallengeRequired.WithDetails(map[string]any{
+		"challenge": &models.Challenge{
+			Token:      token,
+			Difficulty: uc.cfg.ChallengeDifficulty,
+			ExpiresAt:  expiresAt,
+		},
+	})
+}
+
+// verifySolution accepts a solved challenge once, for the phone and IP it
+// was issued to.
+func (uc *AbuseUsecase) verifySolution(ctx context.Context, solution models.Solution, phone, ip string) error {
+	claims := &models.ChallengeClaims{}
+	if _, err := uc.keys.Parse(solution.Token, claims, jwt.WithAudience(challengeAudience), jwt.WithExpirationRequired()); err != nil {
+		return err
+	}
+	if claims.Phone != phone || claims.IP != ip {
+		return errors.New("challenge issued for another request")
+	}
+	if !SolvesChallenge(solution.Token, solution.Nonce, claims.Difficulty) {
+		return errors.New("nonce does not solve the challenge")
+	}
+
+	// the window ends after the token expires, so the use is remembered for
+	// as long as the token could be replayed
+	uses, err := uc.counters.Incr(ctx, "challenge:"+claims.ID, uc.challengeTTL(), claims.ExpiresAt.Time)
+	if err != nil {
+		return err
+	}
+	if uses > 1 {
+		return errors.New("challenge already used")
+	}
+	return nil
+}
+
+// SolvesChallenge reports whether SHA-256(token + nonce) starts with at
+// least difficulty zero bits.
+func SolvesChallenge(token, nonce string, difficulty int) bool {
+	sum := sha256.Sum256([]byte(token + nonce))
+	zeros := 0
+	for _, b := range sum {
+		zeros += bits.LeadingZeros8(b)
+		if b != 0 {
+			break
+		}
+	}
+	return zeros >= difficulty
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (uc *AbuseUsecase) ListBlocks(ctx context.Context, dimension models.Dimension, limit, offset int) ([]*models.Block, error) {
+	if dimension != "" && !dimension.IsValid() {
+		return nil, domain_errors.ErrInvalidAbuseDimension
+	}
+	if limit <= 0 || limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	blocks, err := uc.blockRepo.ListActive(ctx, dimension, limit, offset, time.Now().UTC())
+	if err != nil {
+		uc.logger.Error("failed to list blocks (abuseUC.ListBlocks.blockRepo.ListActive)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return blocks, nil
+}
+
+// Block lets an admin refuse OTP sends for a subject by hand.
+func (uc *AbuseUsecase) Block(ctx context.Context, input *models.BlockInput) (*models.Block, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		uc.logger.Error("missing identity in context")
+		return nil, appErrors.ErrUnauthorized
+	}
+	if !input.Dimension.IsValid() {
+		return nil, domain_errors.ErrInvalidAbuseDimension
+	}
+
+	block := &models.Block{
+		Dimension: input.Dimension,
+		Value:     input.Value,
+		Reason:    input.Reason,
+		ExpiresAt: time.Now().UTC().Add(time.Duration(input.Duration) * time.Second),
+		CreatedBy: &identity.UserID,
+	}
+	if err := uc.blockRepo.Create(ctx, block); err != nil {
+		uc.logger.Error("failed to create block (abuseUC.Block.blockRepo.Create)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return block, nil
+}
+
+// Unblock lifts an active block before it expires. Counters are left as they
+// are, so a subject still over its limit is blocked again on its next send.
+func (uc *AbuseUsecase) Unblock(ctx context.Context, id uuid.UUID) (*models.Block, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		uc.logger.Error("missing identity in context")
+		return nil, appErrors.ErrUnauthorized
+	}
+
+	block, err := uc.blockRepo.Lift(ctx, id, identity.UserID, time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrAbuseBlockNotFound) {
+			return nil, domain_errors.ErrAbuseBlockNotFound
+		}
+		uc.logger.Error("failed to lift block (abuseUC.Unblock.blockRepo.Lift)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return block, nil
+}
+
+// Run drops expired counters every cleanup interval until ctx is cancelled.
+func (uc *AbuseUsecase) Run(ctx context.Context) {
+	interval := time.Duration(uc.cfg.CleanupInterval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := uc.counters.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+				uc.logger.Error("failed to delete expired counters (abuseUC.Run.counters.DeleteExpired)", "error", err)
+			}
+		}
+	}
+}
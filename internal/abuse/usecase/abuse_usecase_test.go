@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/abuse"
+	mocks "swasthAI/internal/abuse/mocks"
+	"swasthAI/internal/abuse/models"
+	"swasthAI/internal/abuse/repository"
+	"swasthAI/internal/auth"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/jwtkeys"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTest(t *testing.T, cfg config.Abuse) (*AbuseUsecase, *mocks.MockBlockRepository, *[]time.Duration, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	blockRepo := mocks.NewMockBlockRepository(ctrl)
+	keys, err := jwtkeys.New(config.JWT{Secret: "test-secret"})
+	require.NoError(t, err)
+	log, _ := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+
+	uc := NewAbuseUsecase(repository.NewMemoryCounterStore(), blockRepo, keys, cfg, *log)
+	var slept []time.Duration
+	uc.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return uc, blockRepo, &slept, ctrl
+}
+
+func clientContext(ip string) context.Context {
+	return utils.WithClientInfo(context.Background(), utils.ClientInfo{IP: ip})
+}
+
+func solve(t *testing.T, challenge *models.Challenge) string {
+	for i := 0; i < 1<<20; i++ {
+		nonce := strconv.Itoa(i)
+		if SolvesChallenge(challenge.Token, nonce, challenge.Difficulty) {
+			return nonce
+		}
+	}
+	t.Fatal("no nonce found")
+	return ""
+}
+
+func TestAbuseUsecase_CheckOTPSend_Escalates(t *testing.T) {
+	cfg := config.Abuse{
+		IP:                  config.AbuseLimits{Delay: 1, Challenge: 3, Block: 5},
+		DelayStep:           100,
+		MaxDelay:            150,
+		ChallengeDifficulty: 4,
+		BlockDuration:       600,
+	}
+	uc, blockRepo, slept, ctrl := setupTest(t, cfg)
+	defer ctrl.Finish()
+
+	ctx := clientContext("203.0.113.7")
+	blockRepo.EXPECT().FindActive(ctx, gomock.Any(), gomock.Any()).Return(nil, domain_errors.ErrAbuseBlockNotFound).Times(5)
+
+	// first send is free, the next two are delayed up to MaxDelay
+	for i := 0; i < 3; i++ {
+		require.NoError(t, uc.CheckOTPSend(ctx, "+919876543210"))
+	}
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 150 * time.Millisecond}, *slept)
+
+	err := uc.CheckOTPSend(ctx, "+919876543210")
+	require.ErrorIs(t, err, domain_errors.ErrChallengeRequired)
+	challenge := err.(*appErrors.AppError).Details["challenge"].(*models.Challenge)
+	assert.Equal(t, 4, challenge.Difficulty)
+
+	solved := abuse.WithSolution(ctx, models.Solution{Token: challenge.Token, Nonce: solve(t, challenge)})
+	blockRepo.EXPECT().FindActive(solved, gomock.Any(), gomock.Any()).Return(nil, domain_errors.ErrAbuseBlockNotFound)
+	assert.NoError(t, uc.CheckOTPSend(solved, "+919876543210"))
+
+	blockRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, block *models.Block) error {
+		assert.Equal(t, models.DimensionIP, block.Dimension)
+		assert.Equal(t, "203.0.113.7", block.Value)
+		assert.EqualValues(t, 6, block.Count)
+		return nil
+	})
+	err = uc.CheckOTPSend(ctx, "+919876543210")
+	require.ErrorIs(t, err, domain_errors.ErrAbuseBlocked)
+	assert.Equal(t, 600, err.(*appErrors.AppError).RetryAfter)
+}
+
+func TestAbuseUsecase_CheckOTPSend_ChallengeSingleUse(t *testing.T) {
+	uc, blockRepo, _, ctrl := setupTest(t, config.Abuse{
+		IP:                  config.AbuseLimits{Challenge: 0},
+		Prefix:              config.AbuseLimits{Challenge: 1},
+		ChallengeDifficulty: 4,
+	})
+	defer ctrl.Finish()
+
+	blockRepo.EXPECT().FindActive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain_errors.ErrAbuseBlockNotFound).AnyTimes()
+
+	ctx := clientContext("203.0.113.7")
+	require.NoError(t, uc.CheckOTPSend(ctx, "+919876543210"))
+	err := uc.CheckOTPSend(ctx, "+919876543210")
+	require.ErrorIs(t, err, domain_errors.ErrChallengeRequired)
+	challenge := err.(*appErrors.AppError).Details["challenge"].(*models.Challenge)
+	solution := models.Solution{Token: challenge.Token, Nonce: solve(t, challenge)}
+
+	// a neighbouring number shares the prefix but not the challenge
+	err = uc.CheckOTPSend(abuse.WithSolution(ctx, solution), "+919876543211")
+	assert.ErrorIs(t, err, domain_errors.ErrChallengeRequired)
+
+	assert.NoError(t, uc.CheckOTPSend(abuse.WithSolution(ctx, solution), "+919876543210"))
+	err = uc.CheckOTPSend(abuse.WithSolution(ctx, solution), "+919876543210")
+	assert.ErrorIs(t, err, domain_errors.ErrChallengeRequired)
+}
+
+func TestAbuseUsecase_CheckOTPSend_ActiveBlock(t *testing.T) {
+	uc, blockRepo, _, ctrl := setupTest(t, config.Abuse{})
+	defer ctrl.Finish()
+
+	ctx := utils.WithClientInfo(context.Background(), utils.ClientInfo{IP: "203.0.113.7", DeviceID: "device-1"})
+	blockRepo.EXPECT().FindActive(ctx, []models.Subject{
+		{Dimension: models.DimensionIP, Value: "203.0.113.7"},
+		{Dimension: models.DimensionDevice, Value: "device-1"},
+		{Dimension: models.DimensionPrefix, Value: "+9198765"},
+		{Dimension: models.DimensionGlobal, Value: "all"},
+	}, gomock.Any()).Return(&models.Block{ExpiresAt: time.Now().Add(time.Minute)}, nil)
+
+	err := uc.CheckOTPSend(ctx, "+919876543210")
+	require.ErrorIs(t, err, domain_errors.ErrAbuseBlocked)
+	assert.InDelta(t, 60, err.(*appErrors.AppError).RetryAfter, 2)
+}
+
+func TestAbuseUsecase_Unblock(t *testing.T) {
+	uc, blockRepo, _, ctrl := setupTest(t, config.Abuse{})
+	defer ctrl.Finish()
+
+	adminID, blockID := uuid.New(), uuid.New()
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{UserID: adminID})
+
+	blockRepo.EXPECT().Lift(ctx, blockID, adminID, gomock.Any()).Return(&models.Block{ID: blockID}, nil)
+	block, err := uc.Unblock(ctx, blockID)
+	require.NoError(t, err)
+	assert.Equal(t, blockID, block.ID)
+
+	blockRepo.EXPECT().Lift(ctx, blockID, adminID, gomock.Any()).Return(nil, domain_errors.ErrAbuseBlockNotFound)
+	_, err = uc.Unblock(ctx, blockID)
+	assert.ErrorIs(t, err, domain_errors.ErrAbuseBlockNotFound)
+}
+
+func TestAbuseUsecase_Block_InvalidDimension(t *testing.T) {
+	uc, _, _, ctrl := setupTest(t, config.Abuse{})
+	defer ctrl.Finish()
+
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{UserID: uuid.New()})
+	_, err := uc.Block(ctx, &models.BlockInput{Dimension: "country", Value: "IN", Reason: "test", Duration: 60})
+	assert.ErrorIs(t, err, domain_errors.ErrInvalidAbuseDimension)
+}
@@ -0,0 +1,16 @@
+package admin
+
+import (
+	"context"
+	"swasthAI/internal/auth/models"
+
+	"github.com/google/uuid"
+)
+
+type AdminUsecase interface {
+	ListRoles(ctx context.Context) []models.RoleInfo
+	ListUsers(ctx context.Context, role models.Role, limit, offset int) ([]*models.User, error)
+	GetUserRoles(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	AssignRoles(ctx context.Context, userID uuid.UUID, input *models.AssignRolesInput) (*models.User, error)
+	ListRoleChanges(ctx context.Context, userID uuid.UUID) ([]*models.RoleChange, error)
+}
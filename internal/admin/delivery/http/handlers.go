@@ -0,0 +1,127 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"swasthAI/config"
+	"swasthAI/internal/admin"
+	"swasthAI/internal/auth/models"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/http_errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	uc     admin.AdminUsecase
+	logger *logger.Logger
+	Cfg    *config.Config
+}
+
+func NewHandler(uc admin.AdminUsecase, logger *logger.Logger, cfg *config.Config) *Handler {
+	return &Handler{uc: uc, logger: logger, Cfg: cfg}
+}
+
+func (h *Handler) ListRoles(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]any{
+		"roles": h.uc.ListRoles(c.Request().Context()),
+	})
+}
+
+func (h *Handler) ListUsers(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+
+	users, err := h.uc.ListUsers(c.Request().Context(), models.Role(c.QueryParam("role")), limit, offset)
+	if err != nil {
+		h.logger.Error("failed to list users", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"users": users,
+	})
+}
+
+func (h *Handler) GetUserRoles(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return http_errors.Send(c, domain_errors.ErrUserNotFound)
+	}
+
+	user, err := h.uc.GetUserRoles(c.Request().Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to get user roles", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"user_id":     user.ID,
+		"roles":       user.Roles,
+		"permissions": user.Permissions,
+		"effective":   models.EffectivePermissions(user.Roles, user.Permissions),
+	})
+}
+
+func (h *Handler) AssignRoles(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return http_errors.Send(c, domain_errors.ErrUserNotFound)
+	}
+
+	var input models.AssignRolesInput
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error("failed to read input", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+
+	user, err := h.uc.AssignRoles(c.Request().Context(), userID, &input)
+	if err != nil {
+		h.logger.Error("failed to assign roles", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"user_id":     user.ID,
+		"roles":       user.Roles,
+		"permissions": user.Permissions,
+		"effective":   models.EffectivePermissions(user.Roles, user.Permissions),
+	})
+}
+
+func (h *Handler) ListRoleChanges(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return http_errors.Send(c, domain_errors.ErrUserNotFound)
+	}
+
+	changes, err := h.uc.ListRoleChanges(c.Request().Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to list role changes", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"changes": changes,
+	})
+}
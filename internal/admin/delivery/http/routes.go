@@ -0,0 +1,18 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"swasthAI/internal/auth/models"
+	"swasthAI/internal/middleware"
+)
+
+func (h *Handler) MapAdminRoutes(admin *echo.Group, mw middleware.MiddlewareManager) {
+	admin.Use(mw.RequireAuth)
+
+	admin.GET("/roles", h.ListRoles, mw.Require(models.PermRoleRead))
+	admin.GET("/users", h.ListUsers, mw.Require(models.PermUserRead))
+	admin.GET("/users/:id/roles", h.GetUserRoles, mw.Require(models.PermRoleRead))
+	admin.PUT("/users/:id/roles", h.AssignRoles, mw.Require(models.PermRoleAssign))
+	admin.GET("/users/:id/role-changes", h.ListRoleChanges, mw.Require(models.PermRoleRead))
+}
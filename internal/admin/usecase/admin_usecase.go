@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+
+	"swasthAI/internal/audit"
+	auditModels "swasthAI/internal/audit/models"
+	"swasthAI/internal/auth"
+	"swasthAI/internal/auth/models"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+const maxListLimit = 100
+
+type AdminUsecase struct {
+	userRepo    auth.UserRepository
+	refreshRepo auth.RefreshTokenRepository
+	sessionRepo auth.LoginSessionRepository
+	auditUC     audit.AuditUsecase
+	logger      logger.Logger
+}
+
+func NewAdminUsecase(userRepo auth.UserRepository, refreshRepo auth.RefreshTokenRepository, sessionRepo auth.LoginSessionRepository, auditUC audit.AuditUsecase, logger logger.Logger) *AdminUsecase {
+	return &AdminUsecase{userRepo: userRepo, refreshRepo: refreshRepo, sessionRepo: sessionRepo, auditUC: auditUC, logger: logger}
+}
+
+// ListRoles describes every role and the permissions it grants.
+func (uc *AdminUsecase) ListRoles(ctx context.Context) []models.RoleInfo {
+	roles := make([]models.RoleInfo, 0, len(models.RolePermissions))
+	for role := range models.RolePermissions {
+		roles = append(roles, models.RoleInfo{
+			Role:        role,
+			Permissions: models.EffectivePermissions([]models.Role{role}, nil),
+		})
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Role < roles[j].Role })
+	return roles
+}
+
+func (uc *AdminUsecase) ListUsers(ctx context.Context, role models.Role, limit, offset int) ([]*models.User, error) {
+	if role != "" && !role.IsValid() {
+		return nil, domain_errors.ErrInvalidRole
+	}
+	if limit <= 0 || limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	users, err := uc.userRepo.ListByRole(ctx, role, limit, offset)
+	if err != nil {
+		uc.logger.Error("failed to list users (adminUC.ListUsers.userRepo.ListByRole)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return users, nil
+}
+
+func (uc *AdminUsecase) GetUserRoles(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrUserNotFound) {
+			return nil, domain_errors.ErrUserNotFound
+		}
+		uc.logger.Error("failed to find user (adminUC.GetUserRoles.userRepo.FindByID)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return user, nil
+}
+
+// AssignRoles replaces the user's roles and extra permissions and records
+// who changed them and why. Tokens carry the permissions they were issued
+// with, so the user is logged out everywhere and picks up the new ones at
+// the next login.
+func (uc *AdminUsecase) AssignRoles(ctx context.Context, userID uuid.UUID, input *models.AssignRolesInput) (_ *models.User, err error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		uc.logger.Error("missing identity in context")
+		return nil, appErrors.ErrUnauthorized
+	}
+	defer func() {
+		uc.auditUC.Record(ctx, &auditModels.Event{
+			Action:    auditModels.ActionRoleAssign,
+			SubjectID: &userID,
+			Metadata:  map[string]any{"roles": input.Roles, "permissions": input.Permissions, "reason": input.Reason},
+			Err:       err,
+		})
+	}()
+	if identity.UserID == userID {
+		return nil, domain_errors.ErrSelfRoleChange
+	}
+
+	roles := uniqueSorted(input.Roles)
+	for _, role := range roles {
+		if !role.IsValid() {
+			return nil, domain_errors.ErrInvalidRole
+		}
+	}
+	perms := uniqueSorted(input.Permissions)
+	for _, perm := range perms {
+		if !perm.IsValid() {
+			return nil, domain_errors.ErrInvalidPermission
+		}
+	}
+
+	user, err := uc.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &models.RoleChange{
+		UserID:         user.ID,
+		ChangedBy:      identity.UserID,
+		OldRoles:       user.Roles,
+		NewRoles:       roles,
+		OldPermissions: user.Permissions,
+		NewPermissions: perms,
+		Reason:         input.Reason,
+	}
+	user.Roles = roles
+	user.Permissions = perms
+
+	if err := uc.userRepo.UpdateRoles(ctx, user, change); err != nil {
+		if errors.Is(err, domain_errors.ErrUserNotFound) {
+			return nil, domain_errors.ErrUserNotFound
+		}
+		uc.logger.Error("failed to update roles (adminUC.AssignRoles.userRepo.UpdateRoles)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	if err := uc.refreshRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		uc.logger.Error("failed to revoke user tokens (adminUC.AssignRoles.refreshRepo.RevokeAllForUser)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	if err := uc.sessionRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		uc.logger.Error("failed to revoke user sessions (adminUC.AssignRoles.sessionRepo.RevokeAllForUser)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+
+	uc.logger.Info("roles changed", "user_id", user.ID, "changed_by", identity.UserID, "roles", roles, "permissions", perms)
+	return user, nil
+}
+
+func (uc *AdminUsecase) ListRoleChanges(ctx context.Context, userID uuid.UUID) ([]*models.RoleChange, error) {
+	changes, err := uc.userRepo.ListRoleChanges(ctx, userID)
+	if err != nil {
+		uc.logger.Error("failed to list role changes (adminUC.ListRoleChanges.userRepo.ListRoleChanges)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return changes, nil
+}
+
+func uniqueSorted[T ~string](values []T) []T {
+	out := slices.Clone(values)
+	slices.Sort(out)
+	out = slices.Compact(out)
+	if out == nil {
+		out = []T{}
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"swasthAI/config"
+	auditMocks "swasthAI/internal/audit/mocks"
+	"swasthAI/internal/auth"
+	mocks "swasthAI/internal/auth/mocks"
+	"swasthAI/internal/auth/models"
+	"swasthAI/pkg/domain_errors"
+	"swasthAI/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDeps struct {
+	userRepo    *mocks.MockUserRepository
+	refreshRepo *mocks.MockRefreshTokenRepository
+	sessionRepo *mocks.MockLoginSessionRepository
+}
+
+func setupTest(t *testing.T) (*AdminUsecase, *testDeps, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	deps := &testDeps{
+		userRepo:    mocks.NewMockUserRepository(ctrl),
+		refreshRepo: mocks.NewMockRefreshTokenRepository(ctrl),
+		sessionRepo: mocks.NewMockLoginSessionRepository(ctrl),
+	}
+	auditUC := auditMocks.NewMockAuditUsecase(ctrl)
+	auditUC.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+	log, _ := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	return NewAdminUsecase(deps.userRepo, deps.refreshRepo, deps.sessionRepo, auditUC, *log), deps, ctrl
+}
+
+func adminContext() (context.Context, uuid.UUID) {
+	adminID := uuid.New()
+	return auth.WithIdentity(context.Background(), &auth.Identity{
+		UserID:      adminID,
+		Roles:       []models.Role{models.RoleAdmin},
+		Permissions: []models.Permission{models.PermRoleAssign},
+	}), adminID
+}
+
+func TestAdminUsecase_AssignRoles_RecordsChange(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, adminID := adminContext()
+	user := &models.User{ID: uuid.New(), Roles: []models.Role{models.RolePatient}, Permissions: []models.Permission{}}
+
+	deps.userRepo.EXPECT().FindByID(ctx, user.ID).Return(user, nil)
+	deps.userRepo.EXPECT().UpdateRoles(ctx, user, gomock.Any()).DoAndReturn(func(ctx context.Context, u *models.User, change *models.RoleChange) error {
+		assert.Equal(t, adminID, change.ChangedBy)
+		assert.Equal(t, []models.Role{models.RolePatient}, change.OldRoles)
+		assert.Equal(t, []models.Role{models.RoleASHAWorker, models.RolePatient}, change.NewRoles)
+		assert.Equal(t, []models.Permission{models.PermConsultationRead}, change.NewPermissions)
+		assert.Equal(t, "joined ASHA programme", change.Reason)
+		return nil
+	})
+	// tokens issued with the old permissions stop working
+	deps.refreshRepo.EXPECT().RevokeAllForUser(ctx, user.ID).Return(nil)
+	deps.sessionRepo.EXPECT().RevokeAllForUser(ctx, user.ID).Return(nil)
+
+	updated, err := uc.AssignRoles(ctx, user.ID, &models.AssignRolesInput{
+		Roles:       []models.Role{models.RolePatient, models.RoleASHAWorker, models.RolePatient},
+		Permissions: []models.Permission{models.PermConsultationRead},
+		Reason:      "joined ASHA programme",
+	})
+	assert.NoError(t, err)
+	assert.True(t, updated.HasRole(models.RoleASHAWorker))
+}
+
+func TestAdminUsecase_AssignRoles_RejectsSelf(t *testing.T) {
+	uc, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, adminID := adminContext()
+	_, err := uc.AssignRoles(ctx, adminID, &models.AssignRolesInput{Roles: []models.Role{models.RolePatient}, Reason: "oops"})
+	assert.ErrorIs(t, err, domain_errors.ErrSelfRoleChange)
+}
+
+func TestAdminUsecase_AssignRoles_UnknownRole(t *testing.T) {
+	uc, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, _ := adminContext()
+	_, err := uc.AssignRoles(ctx, uuid.New(), &models.AssignRolesInput{Roles: []models.Role{"superuser"}, Reason: "test"})
+	assert.ErrorIs(t, err, domain_errors.ErrInvalidRole)
+
+	_, err = uc.AssignRoles(ctx, uuid.New(), &models.AssignRolesInput{
+		Roles:       []models.Role{models.RoleDoctor},
+		Permissions: []models.Permission{"everything:all"},
+		Reason:      "test",
+	})
+	assert.ErrorIs(t, err, domain_errors.ErrInvalidPermission)
+}
+
+func TestAdminUsecase_ListUsers(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, _ := adminContext()
+	deps.userRepo.EXPECT().ListByRole(ctx, models.RoleDoctor, maxListLimit, 0).Return([]*models.User{{ID: uuid.New()}}, nil)
+
+	users, err := uc.ListUsers(ctx, models.RoleDoctor, 0, -5)
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	_, err = uc.ListUsers(ctx, "superuser", 10, 0)
+	assert.ErrorIs(t, err, domain_errors.ErrInvalidRole)
+}
+
+func TestAdminUsecase_ListRoles(t *testing.T) {
+	uc, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	roles := uc.ListRoles(context.Background())
+	assert.Len(t, roles, 4)
+	assert.Equal(t, models.RoleAdmin, roles[0].Role)
+	assert.Contains(t, roles[0].Permissions, models.PermRoleAssign)
+}
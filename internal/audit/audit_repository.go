@@ -0,0 +1,14 @@
+package audit
+
+import (
+	"context"
+	"swasthAI/internal/audit/models"
+)
+
+type AuditRepository interface {
+	// Append chains events, in order, to the last one and inserts them.
+	Append(ctx context.Context, events []*models.Event) error
+	List(ctx context.Context, filter *models.Filter) ([]*models.Event, error)
+	// ListAfter returns up to limit events with Seq above afterSeq, oldest first.
+	ListAfter(ctx context.Context, afterSeq int64, limit int) ([]*models.Event, error)
+}
@@ -0,0 +1,17 @@
+package audit
+
+import (
+	"context"
+	"swasthAI/internal/audit/models"
+)
+
+type AuditUsecase interface {
+	// Record queues event for the log, filling in the caller and client from
+	// ctx. Failures are logged, never returned, so auditing cannot break the
+	// action being audited.
+	Record(ctx context.Context, event *models.Event)
+	// Run appends queued events in batches until ctx is cancelled.
+	Run(ctx context.Context)
+	List(ctx context.Context, filter *models.Filter) (*models.Page, error)
+	Verify(ctx context.Context) (*models.Verification, error)
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/audit"
+	"swasthAI/internal/audit/models"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/http_errors"
+	"swasthAI/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	uc     audit.AuditUsecase
+	logger *logger.Logger
+	Cfg    *config.Config
+}
+
+func NewHandler(uc audit.AuditUsecase, logger *logger.Logger, cfg *config.Config) *Handler {
+	return &Handler{uc: uc, logger: logger, Cfg: cfg}
+}
+
+func (h *Handler) ListEvents(c echo.Context) error {
+	filter, err := readFilter(c)
+	if err != nil {
+		return http_errors.Send(c, appErrors.ErrInvalidInput.WithCause(err))
+	}
+
+	page, err := h.uc.List(c.Request().Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to list audit events", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+func (h *Handler) VerifyChain(c echo.Context) error {
+	result, err := h.uc.Verify(c.Request().Context())
+	if err != nil {
+		h.logger.Error("failed to verify audit chain", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// readFilter parses the query string of GET /admin/audit. Times are RFC 3339.
+func readFilter(c echo.Context) (*models.Filter, error) {
+	filter := &models.Filter{
+		Action:  models.Action(c.QueryParam("action")),
+		Outcome: models.Outcome(c.QueryParam("outcome")),
+	}
+	for param, dst := range map[string]**uuid.UUID{"actor_id": &filter.ActorID, "subject_id": &filter.SubjectID} {
+		if v := c.QueryParam(param); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				return nil, err
+			}
+			*dst = &id
+		}
+	}
+	for param, dst := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := c.QueryParam(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, err
+			}
+			*dst = t
+		}
+	}
+	if v := c.QueryParam("cursor"); v != "" {
+		cursor, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		filter.Cursor = cursor
+	}
+	filter.Limit, _ = strconv.Atoi(c.QueryParam("limit"))
+	return filter, nil
+}
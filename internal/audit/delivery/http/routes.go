@@ -0,0 +1,15 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"swasthAI/internal/auth/models"
+	"swasthAI/internal/middleware"
+)
+
+func (h *Handler) MapAuditRoutes(audit *echo.Group, mw middleware.MiddlewareManager) {
+	audit.Use(mw.RequireAuth, mw.Require(models.PermAuditRead))
+
+	audit.GET("", h.ListEvents)
+	audit.GET("/verify", h.VerifyChain)
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Action is what an audited request did, written "<resource>.<verb>".
+type Action string
+
+const (
+	ActionOTPSend           Action = "otp.send"
+	ActionOTPResend         Action = "otp.resend"
+	ActionOTPVerify         Action = "otp.verify"
+	ActionLogin             Action = "auth.login"
+	ActionRegister          Action = "auth.register"
+	ActionTokenRefresh      Action = "token.refresh"
+	ActionLogout            Action = "auth.logout"
+	ActionLogoutAll         Action = "auth.logout_all"
+	ActionSessionRevoke     Action = "session.revoke"
+	ActionProfileUpdate     Action = "profile.update"
+	ActionDeletionRequest   Action = "account.deletion_request"
+	ActionAccountDelete     Action = "account.delete"
+	ActionAccountRestore    Action = "account.restore"
+	ActionAccountPurge      Action = "account.purge"
+	ActionRoleAssign        Action = "role.assign"
+	ActionHealthUpdate      Action = "health_profile.update"
+	ActionAccessDenied      Action = "access.denied"
+	ActionAccessTokenReject Action = "token.reject"
+)
+
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+	OutcomeDenied  Outcome = "denied" // refused for lack of authentication or permission
+)
+
+// Event is one row of the audit log. Rows are only ever appended; each one
+// carries the hash of the row before it, so editing or deleting a row breaks
+// the chain from that point on.
+type Event struct {
+	bun.BaseModel `bun:"table:audit_events"`
+
+	Seq       int64          `bun:",pk,autoincrement" json:"seq"`
+	ActorID   *uuid.UUID     `bun:",type:uuid" json:"actor_id,omitempty"`   // who made the request
+	SubjectID *uuid.UUID     `bun:",type:uuid" json:"subject_id,omitempty"` // whose account was acted on
+	Action    Action         `bun:",notnull" json:"action"`
+	Outcome   Outcome        `bun:",notnull" json:"outcome"`
+	Reason    string         `bun:",nullzero" json:"reason,omitempty"` // error code when not successful
+	IP        string         `bun:",nullzero" json:"ip,omitempty"`
+	UserAgent string         `bun:",nullzero" json:"user_agent,omitempty"`
+	RequestID string         `bun:",nullzero" json:"request_id,omitempty"`
+	Metadata  map[string]any `bun:",type:jsonb,nullzero" json:"metadata,omitempty"`
+	CreatedAt time.Time      `bun:",notnull" json:"created_at"`
+	PrevHash  string         `bun:",notnull" json:"prev_hash"`
+	Hash      string         `bun:",notnull,unique" json:"hash"`
+
+	Err error `bun:"-" json:"-"` // result of the action, turned into Outcome and Reason
+}
+
+// ComputeHash returns the hash of the event chained to prevHash. It covers
+// every recorded field except Seq, which the database assigns.
+func (e *Event) ComputeHash(prevHash string) (string, error) {
+	body, err := json.Marshal(struct {
+		PrevHash  string         `json:"prev_hash"`
+		ActorID   *uuid.UUID     `json:"actor_id"`
+		SubjectID *uuid.UUID     `json:"subject_id"`
+		Action    Action         `json:"action"`
+		Outcome   Outcome        `json:"outcome"`
+		Reason    string         `json:"reason"`
+		IP        string         `json:"ip"`
+		UserAgent string         `json:"user_agent"`
+		RequestID string         `json:"request_id"`
+		Metadata  map[string]any `json:"metadata"`
+		CreatedAt int64          `json:"created_at"`
+	}{prevHash, e.ActorID, e.SubjectID, e.Action, e.Outcome, e.Reason, e.IP, e.UserAgent, e.RequestID, e.Metadata, e.CreatedAt.UnixMicro()})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Filter narrows an audit log query. Cursor is the Seq of the last event of
+// the previous page; results are newest first.
+type Filter struct {
+	ActorID   *uuid.UUID
+	SubjectID *uuid.UUID
+	Action    Action
+	Outcome   Outcome
+	From      time.Time
+	To        time.Time
+	Cursor    int64
+	Limit     int
+}
+
+type Page struct {
+	Events     []*Event `json:"events"`
+	NextCursor int64    `json:"next_cursor,omitempty"` // 0 on the last page
+}
+
+// Verification is the result of walking the hash chain.
+type Verification struct {
+	Checked  int64 `json:"checked"`
+	Valid    bool  `json:"valid"`
+	BrokenAt int64 `json:"broken_at,omitempty"` // Seq of the first event that does not match
+}
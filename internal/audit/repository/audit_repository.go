@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"swasthAI/internal/audit/models"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+// appendLock is the advisory lock key serialising appends, so two events can
+// never chain to the same predecessor. It is taken once per batch, by the
+// writer of each instance.
+const appendLock = 0x61756469 // "audi"
+
+type AuditRepository struct {
+	db *bun.DB
+}
+
+func NewAuditRepository(db *bun.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Append(ctx context.Context, events []*models.Event) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(?)", appendLock); err != nil {
+			return errors.Wrap(err, "auditRepo.Append.Lock")
+		}
+
+		var prevHash string
+		err := tx.NewSelect().
+			Model((*models.Event)(nil)).
+			Column("hash").
+			Order("seq DESC").
+			Limit(1).
+			Scan(ctx, &prevHash)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrap(err, "auditRepo.Append.SelectLast")
+		}
+
+		// one insert per event, so that seq follows the chain
+		for _, event := range events {
+			hash, err := event.ComputeHash(prevHash)
+			if err != nil {
+				return errors.Wrap(err, "auditRepo.Append.ComputeHash")
+			}
+			event.PrevHash = prevHash
+			event.Hash = hash
+
+			if _, err := tx.NewInsert().Model(event).Returning("seq").Exec(ctx); err != nil {
+				return errors.Wrap(err, "auditRepo.Append.Insert")
+			}
+			prevHash = hash
+		}
+		return nil
+	})
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter *models.Filter) ([]*models.Event, error) {
+	var events []*models.Event
+	q := r.db.NewSelect().Model(&events)
+	if filter.ActorID != nil {
+		q = q.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.SubjectID != nil {
+		q = q.Where("subject_id = ?", *filter.SubjectID)
+	}
+	if filter.Action != "" {
+		q = q.Where("action = ?", filter.Action)
+	}
+	if filter.Outcome != "" {
+		q = q.Where("outcome = ?", filter.Outcome)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("created_at < ?", filter.To)
+	}
+	if filter.Cursor > 0 {
+		q = q.Where("seq < ?", filter.Cursor)
+	}
+	if err := q.Order("seq DESC").Limit(filter.Limit).Scan(ctx); err != nil {
+		return nil, errors.Wrap(err, "auditRepo.List.Select")
+	}
+	return events, nil
+}
+
+func (r *AuditRepository) ListAfter(ctx context.Context, afterSeq int64, limit int) ([]*models.Event, error) {
+	var events []*models.Event
+	err := r.db.NewSelect().
+		Model(&events).
+		Where("seq > ?", afterSeq).
+		Order("seq ASC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "auditRepo.ListAfter.Select")
+	}
+	return events, nil
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"swasthAI/internal/audit"
+	"swasthAI/internal/audit/models"
+	"swasthAI/internal/auth"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+	verifyBatchSize  = 500
+	// events waiting for the writer
+	queueSize       = 1024
+	appendBatchSize = 100
+	// how long Record waits for room in a full queue before dropping
+	enqueueWait = 100 * time.Millisecond
+	// a failed append is retried after these, doubling in between
+	appendBackoffMin = 100 * time.Millisecond
+	appendBackoffMax = 10 * time.Second
+	// how long the writer keeps trying to append what is left on shutdown
+	drainTimeout = 10 * time.Second
+)
+
+type AuditUsecase struct {
+	repo   audit.AuditRepository
+	logger logger.Logger
+	queue  chan *models.Event
+}
+
+func NewAuditUsecase(repo audit.AuditRepository, logger logger.Logger) *AuditUsecase {
+	return &AuditUsecase{repo: repo, logger: logger, queue: make(chan *models.Event, queueSize)}
+}
+
+func (uc *AuditUsecase) Record(ctx context.Context, event *models.Event) {
+	if event.ActorID == nil {
+		if identity, ok := auth.FromContext(ctx); ok {
+			actorID := identity.UserID
+			event.ActorID = &actorID
+		}
+	}
+	info := utils.ClientInfoFromContext(ctx)
+	event.IP = info.IP
+	event.UserAgent = info.UserAgent
+	event.RequestID = info.RequestID
+	if event.Outcome == "" {
+		event.Outcome, event.Reason = outcomeOf(event.Err)
+	}
+	// Postgres keeps microseconds; anything finer would not hash the same
+	// when the chain is verified
+	event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	// appended by Run, off the request path. When the writer has fallen
+	// behind by a whole queue, the request is held up for at most
+	// enqueueWait or until ctx ends; the event is then dropped, and only
+	// kept in the application log.
+	select {
+	case uc.queue <- event:
+		return
+	default:
+	}
+	timer := time.NewTimer(enqueueWait)
+	defer timer.Stop()
+	select {
+	case uc.queue <- event:
+	case <-ctx.Done():
+		uc.drop("request ended while the audit queue was full", event)
+	case <-timer.C:
+		uc.drop("audit queue full", event)
+	}
+}
+
+// Run appends queued events until ctx is cancelled, then for up to
+// drainTimeout the ones still queued. Events queued while an append runs go
+// in the next one together, so the chain lock is taken once per batch
+// rather than per request.
+func (uc *AuditUsecase) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			uc.drain(nil)
+			return
+		case event := <-uc.queue:
+			batch := uc.take([]*models.Event{event})
+			if !uc.append(ctx, batch) {
+				uc.drain(batch)
+				return
+			}
+		}
+	}
+}
+
+// drain appends batch and the events still queued, dropping what cannot be
+// appended within drainTimeout.
+func (uc *AuditUsecase) drain(batch []*models.Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	for batch = uc.take(batch); len(batch) > 0; batch = uc.take(nil) {
+		if !uc.append(ctx, batch) {
+			uc.drop("audit writer stopped", batch...)
+		}
+	}
+}
+
+// take adds the events already queued to batch, up to a full batch.
+func (uc *AuditUsecase) take(batch []*models.Event) []*models.Event {
+	for len(batch) < appendBatchSize {
+		select {
+		case event := <-uc.queue:
+			batch = append(batch, event)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// append appends batch, retrying with backoff while it fails. It reports
+// false, with batch not appended, if ctx ends first.
+func (uc *AuditUsecase) append(ctx context.Context, batch []*models.Event) bool {
+	backoff := appendBackoffMin
+	for {
+		err := uc.repo.Append(ctx, batch)
+		if err == nil {
+			return true
+		}
+		uc.logger.Error("failed to append audit events (auditUC.append.repo.Append)", "events", len(batch), "retry_in", backoff, "error", err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+		backoff = min(2*backoff, appendBackoffMax)
+	}
+}
+
+// drop logs events that will not make it into the audit log, so that the
+// application log at least keeps a trace of them.
+func (uc *AuditUsecase) drop(reason string, events ...*models.Event) {
+	for _, e := range events {
+		uc.logger.Error("audit event dropped (auditUC.drop)", "reason", reason, "action", e.Action, "actor_id", e.ActorID, "subject_id", e.SubjectID, "outcome", e.Outcome, "request_id", e.RequestID, "created_at", e.CreatedAt)
+	}
+}
+
+// outcomeOf classifies the result of an action. Authentication and
+// permission errors count as denied, anything else as a failure with the
+// error code as reason.
+func outcomeOf(err error) (models.Outcome, string) {
+	if err == nil {
+		return models.OutcomeSuccess, ""
+	}
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) {
+		return models.OutcomeFailure, appErrors.ErrInternal.Code
+	}
+	if appErr.Status == http.StatusUnauthorized || appErr.Status == http.StatusForbidden {
+		return models.OutcomeDenied, appErr.Code
+	}
+	return models.OutcomeFailure, appErr.Code
+}
+
+func (uc *AuditUsecase) List(ctx context.Context, filter *models.Filter) (*models.Page, error) {
+	switch filter.Outcome {
+	case "", models.OutcomeSuccess, models.OutcomeFailure, models.OutcomeDenied:
+	default:
+		return nil, domain_errors.ErrInvalidAuditOutcome
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+
+	events, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		uc.logger.Error("failed to list audit events (auditUC.List.repo.List)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+
+	page := &models.Page{Events: events}
+	if len(events) == filter.Limit {
+		page.NextCursor = events[len(events)-1].Seq
+	}
+	return page, nil
+}
+
+// Verify walks the whole chain from the first event and reports the first
+// one whose hash or link does not match.
+func (uc *AuditUsecase) Verify(ctx context.Context) (*models.Verification, error) {
+	result := &models.Verification{Valid: true}
+	var afterSeq int64
+	prevHash := ""
+	for {
+		events, err := uc.repo.ListAfter(ctx, afterSeq, verifyBatchSize)
+		if err != nil {
+			uc.logger.Error("failed to list audit events (auditUC.Verify.repo.ListAfter)", "error", err)
+			return nil, appErrors.ErrDatabase
+		}
+
+		for _, e := range events {
+			hash, err := e.ComputeHash(prevHash)
+			if err != nil || e.PrevHash != prevHash || e.Hash != hash {
+				result.Valid = false
+				result.BrokenAt = e.Seq
+				return result, nil
+			}
+			result.Checked++
+			prevHash = e.Hash
+			afterSeq = e.Seq
+		}
+		if len(events) < verifyBatchSize {
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+	mocks "swasthAI/internal/audit/mocks"
+	"swasthAI/internal/audit/models"
+	"swasthAI/internal/auth"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runWriter runs the event writer of uc until the test ends.
+func runWriter(t *testing.T, uc *AuditUsecase) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		uc.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func setupTest(t *testing.T) (*AuditUsecase, *mocks.MockAuditRepository, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAuditRepository(ctrl)
+	log, _ := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	return NewAuditUsecase(repo, *log), repo, ctrl
+}
+
+// chain builds n events linked the way the repository appends them.
+func chain(t *testing.T, n int) []*models.Event {
+	events := make([]*models.Event, n)
+	prev := ""
+	for i := range events {
+		e := &models.Event{Seq: int64(i + 1), Action: models.ActionLogin, Outcome: models.OutcomeSuccess}
+		hash, err := e.ComputeHash(prev)
+		require.NoError(t, err)
+		e.PrevHash, e.Hash = prev, hash
+		events[i] = e
+		prev = hash
+	}
+	return events
+}
+
+func TestAuditUsecase_Record_FillsRequestContext(t *testing.T) {
+	uc, repo, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{UserID: userID})
+	ctx = utils.WithClientInfo(ctx, utils.ClientInfo{IP: "10.0.0.7", UserAgent: "okhttp/4.12", RequestID: "req-1"})
+
+	appended := make(chan struct{})
+	repo.EXPECT().Append(gomock.Any(), gomock.Len(1)).DoAndReturn(func(ctx context.Context, events []*models.Event) error {
+		defer close(appended)
+		e := events[0]
+		assert.Equal(t, userID, *e.ActorID)
+		assert.Equal(t, "10.0.0.7", e.IP)
+		assert.Equal(t, "okhttp/4.12", e.UserAgent)
+		assert.Equal(t, "req-1", e.RequestID)
+		assert.Equal(t, models.OutcomeDenied, e.Outcome)
+		assert.Equal(t, appErrors.ErrForbidden.Code, e.Reason)
+		assert.False(t, e.CreatedAt.IsZero())
+		return nil
+	})
+	uc.Record(ctx, &models.Event{Action: models.ActionAccessDenied, Err: appErrors.ErrForbidden})
+	runWriter(t, uc)
+	<-appended
+}
+
+func TestAuditUsecase_Record_Outcomes(t *testing.T) {
+	tests := []struct {
+		err     error
+		outcome models.Outcome
+		reason  string
+	}{
+		{nil, models.OutcomeSuccess, ""},
+		{domain_errors.ErrInvalidOTP, models.OutcomeFailure, domain_errors.ErrInvalidOTP.Code},
+		{appErrors.ErrUnauthorized, models.OutcomeDenied, appErrors.ErrUnauthorized.Code},
+		{errors.New("boom"), models.OutcomeFailure, appErrors.ErrInternal.Code},
+	}
+	for _, tt := range tests {
+		outcome, reason := outcomeOf(tt.err)
+		assert.Equal(t, tt.outcome, outcome)
+		assert.Equal(t, tt.reason, reason)
+	}
+}
+
+func TestAuditUsecase_Run_RetriesFailedAppend(t *testing.T) {
+	uc, repo, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	runWriter(t, uc)
+	appended := make(chan []*models.Event, 1)
+	gomock.InOrder(
+		repo.EXPECT().Append(gomock.Any(), gomock.Len(1)).Return(errors.New("connection refused")),
+		repo.EXPECT().Append(gomock.Any(), gomock.Len(1)).DoAndReturn(func(_ context.Context, events []*models.Event) error {
+			appended <- events
+			return nil
+		}),
+	)
+	uc.Record(context.Background(), &models.Event{Action: models.ActionOTPSend})
+	// the same batch, not a later one
+	assert.Equal(t, models.ActionOTPSend, (<-appended)[0].Action)
+}
+
+func TestAuditUsecase_Record_QueueFull(t *testing.T) {
+	uc, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+	uc.queue = make(chan *models.Event, 1)
+
+	// no writer runs; the second event finds the queue full
+	uc.Record(context.Background(), &models.Event{Action: models.ActionLogin})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	uc.Record(ctx, &models.Event{Action: models.ActionLogout})
+	uc.Record(context.Background(), &models.Event{Action: models.ActionLogout})
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Len(t, uc.queue, 1)
+}
+
+func TestAuditUsecase_Run_BatchesQueuedEvents(t *testing.T) {
+	uc, repo, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	// the first append holds the writer while more events are recorded
+	started, release := make(chan struct{}), make(chan struct{})
+	var batches [][]models.Action
+	actions := func(events []*models.Event) []models.Action {
+		var a []models.Action
+		for _, e := range events {
+			a = append(a, e.Action)
+		}
+		return a
+	}
+	gomock.InOrder(
+		repo.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, events []*models.Event) error {
+			batches = append(batches, actions(events))
+			close(started)
+			<-release
+			return nil
+		}),
+		repo.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, events []*models.Event) error {
+			batches = append(batches, actions(events))
+			return nil
+		}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		uc.Run(ctx)
+		close(done)
+	}()
+
+	uc.Record(context.Background(), &models.Event{Action: models.ActionLogin})
+	<-started
+	for _, action := range []models.Action{models.ActionOTPSend, models.ActionOTPVerify, models.ActionLogout} {
+		uc.Record(context.Background(), &models.Event{Action: action})
+	}
+	close(release)
+	// the rest is appended at once, in the order recorded, at the latest
+	// when the writer stops
+	cancel()
+	<-done
+
+	assert.Equal(t, [][]models.Action{
+		{models.ActionLogin},
+		{models.ActionOTPSend, models.ActionOTPVerify, models.ActionLogout},
+	}, batches)
+}
+
+func TestAuditUsecase_List_NextCursor(t *testing.T) {
+	uc, repo, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	events := chain(t, 2)
+	repo.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, f *models.Filter) ([]*models.Event, error) {
+		assert.Equal(t, 2, f.Limit)
+		return []*models.Event{events[1], events[0]}, nil
+	})
+	page, err := uc.List(context.Background(), &models.Filter{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.NextCursor)
+
+	_, err = uc.List(context.Background(), &models.Filter{Outcome: "maybe"})
+	assert.ErrorIs(t, err, domain_errors.ErrInvalidAuditOutcome)
+}
+
+func TestAuditUsecase_Verify(t *testing.T) {
+	uc, repo, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	events := chain(t, 3)
+	repo.EXPECT().ListAfter(gomock.Any(), int64(0), verifyBatchSize).Return(events, nil)
+	result, err := uc.Verify(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, int64(3), result.Checked)
+
+	// an edited row no longer matches its hash
+	events[1].Outcome = models.OutcomeFailure
+	repo.EXPECT().ListAfter(gomock.Any(), int64(0), verifyBatchSize).Return(events, nil)
+	result, err = uc.Verify(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(2), result.BrokenAt)
+
+	// a deleted row breaks the link of the next one
+	events = chain(t, 3)
+	repo.EXPECT().ListAfter(gomock.Any(), int64(0), verifyBatchSize).Return([]*models.Event{events[0], events[2]}, nil)
+	result, err = uc.Verify(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(3), result.BrokenAt)
+}
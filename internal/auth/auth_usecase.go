@@ -3,14 +3,26 @@ package auth
 import (
 	"context"
 	"swasthAI/internal/auth/models"
+	otpModels "swasthAI/internal/otpchannel/models"
+
+	"github.com/google/uuid"
 )
 
 type AuthUsecase interface {
-	SendOTP(ctx context.Context, phone string) error
+	SendOTP(ctx context.Context, phone string, channel otpModels.Channel) (otpModels.Channel, error)
 	VerifyOTP(ctx context.Context, phone, otp string) (*models.UserWithToken, bool, error)
 	RegisterUser(ctx context.Context, input *models.RegisterUserInput) (*models.UserWithToken, error)
 	GetUserByID(ctx context.Context, token string) (*models.User, error)
 	UpdateProfile(ctx context.Context, input *models.UpdateProfileInput) (*models.User, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*models.Tokens, error)
-	ResendOTP(ctx context.Context, phone string) error
+	ResendOTP(ctx context.Context, phone string, channel otpModels.Channel) (otpModels.Channel, error)
+	Logout(ctx context.Context) error
+	LogoutAll(ctx context.Context) error
+	ListSessions(ctx context.Context) ([]*models.LoginSession, error)
+	RevokeSession(ctx context.Context, sessionID uuid.UUID) error
+	CheckSession(ctx context.Context, familyID uuid.UUID) error
+	RequestAccountDeletion(ctx context.Context) error
+	DeleteAccount(ctx context.Context, otp string) (*models.AccountDeletion, error)
+	RestoreAccount(ctx context.Context, phone, otp string) (*models.UserWithToken, error)
+	PurgeDue(ctx context.Context) (int, error)
 }
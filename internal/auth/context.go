@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"swasthAI/internal/auth/models"
+
+	"github.com/google/uuid"
+)
+
+// Identity is the authenticated caller of a request, taken from a verified
+// access token.
+type Identity struct {
+	UserID      uuid.UUID
+	FamilyID    uuid.UUID // refresh-token family of the login the token belongs to
+	TokenID     string    // jti of the access token
+	Roles       []models.Role
+	Permissions []models.Permission // effective permissions, roles included
+}
+
+// HasPermission reports whether the caller was granted perm.
+func (id *Identity) HasPermission(perm models.Permission) bool {
+	for _, p := range id.Permissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole reports whether the caller holds role.
+func (id *Identity) HasRole(role models.Role) bool {
+	for _, r := range id.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying id.
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the caller's identity, if the request was authenticated.
+func FromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(*Identity)
+	return id, ok && id != nil
+}
+
+// UserIDFromContext returns the authenticated user's ID.
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return uuid.Nil, false
+	}
+	return id.UserID, true
+}
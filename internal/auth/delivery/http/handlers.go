@@ -5,11 +5,14 @@ import (
 	"swasthAI/internal/auth"
 	"swasthAI/internal/auth/models"
 	"swasthAI/internal/auth/usecase"
+	"swasthAI/pkg/domain_errors"
 	appErrors "swasthAI/pkg/errors"
 	"swasthAI/pkg/http_errors"
+	"swasthAI/pkg/jwtkeys"
 	"swasthAI/pkg/logger"
 	"swasthAI/pkg/utils"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"net/http"
 	"strings"
@@ -17,12 +20,13 @@ import (
 
 type Handler struct {
 	uc     auth.AuthUsecase
+	keys   *jwtkeys.KeySet
 	logger *logger.Logger
 	Cfg    *config.Config
 }
 
-func NewHandler(uc *usecase.AuthUsecase, logger *logger.Logger, cfg *config.Config) *Handler {
-	return &Handler{uc: uc, logger: logger, Cfg: cfg}
+func NewHandler(uc *usecase.AuthUsecase, keys *jwtkeys.KeySet, logger *logger.Logger, cfg *config.Config) *Handler {
+	return &Handler{uc: uc, keys: keys, logger: logger, Cfg: cfg}
 }
 
 func (h *Handler) SendOTP(c echo.Context) error {
@@ -31,7 +35,7 @@ func (h *Handler) SendOTP(c echo.Context) error {
 		h.logger.Error(err)
 		return http_errors.Send(c, appErrors.ErrInvalidInput)
 	}
-	err := h.uc.SendOTP(c.Request().Context(), user.Phone)
+	channel, err := h.uc.SendOTP(c.Request().Context(), user.Phone, user.Channel)
 	if err != nil {
 		h.logger.Error("failed to send otp", "error", err)
 
@@ -40,9 +44,32 @@ func (h *Handler) SendOTP(c echo.Context) error {
 		}
 		return http_errors.Send(c, appErrors.ErrInternal)
 	}
-	return c.JSON(http.StatusOK, map[string]string{
+	return c.JSON(http.StatusOK, map[string]any{
 		"message":     "OTP sent to " + user.Phone,
-		"retry_after": "60",
+		"channel":     channel,
+		"retry_after": h.Cfg.OTP.ResendCooldown,
+	})
+}
+
+func (h *Handler) ResendOTP(c echo.Context) error {
+	var input models.ResendOTPInput
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error(err)
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+	channel, err := h.uc.ResendOTP(c.Request().Context(), input.Phone, input.Channel)
+	if err != nil {
+		h.logger.Error("failed to resend otp", "error", err)
+
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"message":     "OTP resent to " + input.Phone,
+		"channel":     channel,
+		"retry_after": h.Cfg.OTP.ResendCooldown,
 	})
 }
 
@@ -70,7 +97,6 @@ func (h *Handler) Register(c echo.Context) error {
 	return c.JSON(http.StatusOK, userWithToken)
 }
 
-// NOTE: Using mock otp verification for now
 func (h *Handler) VerifyOTP(c echo.Context) error {
 	var input models.VerifyOTPInput
 	if err := utils.ReadRequest(c, &input); err != nil {
@@ -149,7 +175,69 @@ func (h *Handler) RefreshToken(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]any{
 		"token":         token.Token,
 		"refresh_token": token.RefreshToken,
-		"expires_in":    86400,
+		"expires_in":    token.ExpiresIn,
+	})
+}
+
+func (h *Handler) Logout(c echo.Context) error {
+	if err := h.uc.Logout(c.Request().Context()); err != nil {
+		h.logger.Error("failed to logout", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Logged out successfully",
+	})
+}
+
+func (h *Handler) LogoutAll(c echo.Context) error {
+	if err := h.uc.LogoutAll(c.Request().Context()); err != nil {
+		h.logger.Error("failed to logout all sessions", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Logged out from all devices",
+	})
+}
+
+func (h *Handler) ListSessions(c echo.Context) error {
+	sessions, err := h.uc.ListSessions(c.Request().Context())
+	if err != nil {
+		h.logger.Error("failed to list sessions", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"sessions": sessions,
+	})
+}
+
+func (h *Handler) RevokeSession(c echo.Context) error {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return http_errors.Send(c, domain_errors.ErrLoginSessionNotFound)
+	}
+
+	if err := h.uc.RevokeSession(c.Request().Context(), sessionID); err != nil {
+		h.logger.Error("failed to revoke session", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Device logged out",
 	})
 }
 
@@ -190,3 +278,68 @@ func (h *Handler) UpdateProfile(c echo.Context) error {
 		"message": "Profile updated successfully",
 	})
 }
+
+func (h *Handler) RequestAccountDeletion(c echo.Context) error {
+	if err := h.uc.RequestAccountDeletion(c.Request().Context()); err != nil {
+		h.logger.Error("failed to send deletion otp", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "OTP sent. Confirm it to delete your account",
+	})
+}
+
+func (h *Handler) DeleteAccount(c echo.Context) error {
+	var input models.DeleteAccountInput
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error("failed to read input", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+
+	deletion, err := h.uc.DeleteAccount(c.Request().Context(), input.OTP)
+	if err != nil {
+		h.logger.Error("failed to delete account", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusAccepted, deletion)
+}
+
+func (h *Handler) RestoreAccount(c echo.Context) error {
+	var input models.VerifyOTPInput
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error("failed to read input", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+
+	userWithToken, err := h.uc.RestoreAccount(c.Request().Context(), input.Phone, input.OTP)
+	if err != nil {
+		h.logger.Error("failed to restore account", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, userWithToken)
+}
+
+// JWKS publishes the public token keys so other services can verify our
+// tokens without holding a shared secret.
+func (h *Handler) JWKS(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, h.keys.JWKS())
+}
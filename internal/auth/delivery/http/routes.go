@@ -7,13 +7,28 @@ import (
 )
 
 func (h *Handler) MapAuthRoutes(auth *echo.Group, mw middleware.MiddlewareManager) {
-	auth.POST("/send-otp", h.SendOTP)
+	auth.POST("/send-otp", h.SendOTP, mw.ChallengeSolution)
+	auth.POST("/resend-otp", h.ResendOTP, mw.ChallengeSolution)
 	auth.POST("/verify-otp", h.VerifyOTP)
 	auth.POST("/register", h.Register)
 	auth.POST("/refresh", h.RefreshToken)
+	auth.POST("/restore", h.RestoreAccount)
+	auth.POST("/logout", h.Logout, mw.RequireAuth)
+	auth.POST("/logout-all", h.LogoutAll, mw.RequireAuth)
+
+	sessionsGroup := auth.Group("/sessions")
+	sessionsGroup.Use(mw.RequireAuth)
+	sessionsGroup.GET("", h.ListSessions)
+	sessionsGroup.DELETE("/:id", h.RevokeSession)
 
 	profileGroup := auth.Group("/profile")
-	profileGroup.Use(mw.AuthJWTMiddleware)
+	profileGroup.Use(mw.RequireAuth)
 	profileGroup.GET("", h.GetProfile)
 	profileGroup.PUT("", h.UpdateProfile)
+	profileGroup.POST("/deletion-otp", h.RequestAccountDeletion, mw.ChallengeSolution)
+	profileGroup.DELETE("", h.DeleteAccount)
+}
+
+func (h *Handler) MapWellKnownRoutes(e *echo.Echo) {
+	e.GET("/.well-known/jwks.json", h.JWKS)
 }
@@ -0,0 +1,15 @@
+package auth
+
+import (
+	"context"
+	"swasthAI/internal/auth/models"
+	"time"
+)
+
+// SealErasure builds the receipt for a purge from the rows deleted per table.
+type SealErasure func(erased map[string]int64) (*models.ErasureReceipt, error)
+
+type ErasureRepository interface {
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*models.User, error)
+	Purge(ctx context.Context, user *models.User, seal SealErasure) error
+}
@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"context"
+	"swasthAI/internal/auth/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoginSessionRepository interface {
+	Create(ctx context.Context, session *models.LoginSession) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.LoginSession, error)
+	FindByFamily(ctx context.Context, familyID uuid.UUID) (*models.LoginSession, error)
+	ListActive(ctx context.Context, userID uuid.UUID) ([]*models.LoginSession, error)
+	Touch(ctx context.Context, familyID uuid.UUID, ip string, seenAt time.Time) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+}
@@ -11,6 +11,7 @@ import (
 	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 )
 
 // MockOTPRepository is a mock of OTPRepository interface.
@@ -36,19 +37,33 @@ func (m *MockOTPRepository) EXPECT() *MockOTPRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ConsumeVerified mocks base method.
+func (m *MockOTPRepository) ConsumeVerified(ctx context.Context, phone string, since time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeVerified", ctx, phone, since)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeVerified indicates an expected call of ConsumeVerified.
+func (mr *MockOTPRepositoryMockRecorder) ConsumeVerified(ctx, phone, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeVerified", reflect.TypeOf((*MockOTPRepository)(nil).ConsumeVerified), ctx, phone, since)
+}
+
 // CountRecent mocks base method.
-func (m *MockOTPRepository) CountRecent(ctx context.Context, phone string, duration time.Time) (int, error) {
+func (m *MockOTPRepository) CountRecent(ctx context.Context, phone string, since time.Time) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CountRecent", ctx, phone, duration)
+	ret := m.ctrl.Call(m, "CountRecent", ctx, phone, since)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CountRecent indicates an expected call of CountRecent.
-func (mr *MockOTPRepositoryMockRecorder) CountRecent(ctx, phone, duration interface{}) *gomock.Call {
+func (mr *MockOTPRepositoryMockRecorder) CountRecent(ctx, phone, since interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecent", reflect.TypeOf((*MockOTPRepository)(nil).CountRecent), ctx, phone, duration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecent", reflect.TypeOf((*MockOTPRepository)(nil).CountRecent), ctx, phone, since)
 }
 
 // Create mocks base method.
@@ -94,16 +109,59 @@ func (mr *MockOTPRepositoryMockRecorder) FindByPhone(ctx, phone interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockOTPRepository)(nil).FindByPhone), ctx, phone)
 }
 
+// FirstRecent mocks base method.
+func (m *MockOTPRepository) FirstRecent(ctx context.Context, phone string, since time.Time) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FirstRecent", ctx, phone, since)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FirstRecent indicates an expected call of FirstRecent.
+func (mr *MockOTPRepositoryMockRecorder) FirstRecent(ctx, phone, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FirstRecent", reflect.TypeOf((*MockOTPRepository)(nil).FirstRecent), ctx, phone, since)
+}
+
 // IncrementAttempts mocks base method.
-func (m *MockOTPRepository) IncrementAttempts(ctx context.Context, phone string) error {
+func (m *MockOTPRepository) IncrementAttempts(ctx context.Context, id uuid.UUID, maxAttempts int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IncrementAttempts", ctx, phone)
+	ret := m.ctrl.Call(m, "IncrementAttempts", ctx, id, maxAttempts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // IncrementAttempts indicates an expected call of IncrementAttempts.
-func (mr *MockOTPRepositoryMockRecorder) IncrementAttempts(ctx, phone interface{}) *gomock.Call {
+func (mr *MockOTPRepositoryMockRecorder) IncrementAttempts(ctx, id, maxAttempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAttempts", reflect.TypeOf((*MockOTPRepository)(nil).IncrementAttempts), ctx, id, maxAttempts)
+}
+
+// Lock mocks base method.
+func (m *MockOTPRepository) Lock(ctx context.Context, id uuid.UUID, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, id, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockOTPRepositoryMockRecorder) Lock(ctx, id, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockOTPRepository)(nil).Lock), ctx, id, until)
+}
+
+// MarkVerified mocks base method.
+func (m *MockOTPRepository) MarkVerified(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkVerified", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkVerified indicates an expected call of MarkVerified.
+func (mr *MockOTPRepositoryMockRecorder) MarkVerified(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAttempts", reflect.TypeOf((*MockOTPRepository)(nil).IncrementAttempts), ctx, phone)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkVerified", reflect.TypeOf((*MockOTPRepository)(nil).MarkVerified), ctx, id)
 }
@@ -36,6 +36,20 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CancelDeletion mocks base method.
+func (m *MockUserRepository) CancelDeletion(ctx context.Context, user *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelDeletion", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelDeletion indicates an expected call of CancelDeletion.
+func (mr *MockUserRepositoryMockRecorder) CancelDeletion(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelDeletion", reflect.TypeOf((*MockUserRepository)(nil).CancelDeletion), ctx, user)
+}
+
 // Create mocks base method.
 func (m *MockUserRepository) Create(ctx context.Context, user *models.User) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +95,50 @@ func (mr *MockUserRepositoryMockRecorder) FindByPhone(ctx, phone interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockUserRepository)(nil).FindByPhone), ctx, phone)
 }
 
+// ListByRole mocks base method.
+func (m *MockUserRepository) ListByRole(ctx context.Context, role models.Role, limit int, offset int) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByRole", ctx, role, limit, offset)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByRole indicates an expected call of ListByRole.
+func (mr *MockUserRepositoryMockRecorder) ListByRole(ctx, role, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByRole", reflect.TypeOf((*MockUserRepository)(nil).ListByRole), ctx, role, limit, offset)
+}
+
+// ListRoleChanges mocks base method.
+func (m *MockUserRepository) ListRoleChanges(ctx context.Context, userID uuid.UUID) ([]*models.RoleChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoleChanges", ctx, userID)
+	ret0, _ := ret[0].([]*models.RoleChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoleChanges indicates an expected call of ListRoleChanges.
+func (mr *MockUserRepositoryMockRecorder) ListRoleChanges(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoleChanges", reflect.TypeOf((*MockUserRepository)(nil).ListRoleChanges), ctx, userID)
+}
+
+// ScheduleDeletion mocks base method.
+func (m *MockUserRepository) ScheduleDeletion(ctx context.Context, user *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleDeletion", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScheduleDeletion indicates an expected call of ScheduleDeletion.
+func (mr *MockUserRepositoryMockRecorder) ScheduleDeletion(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleDeletion", reflect.TypeOf((*MockUserRepository)(nil).ScheduleDeletion), ctx, user)
+}
+
 // Update mocks base method.
 func (m *MockUserRepository) Update(ctx context.Context, user *models.User) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -95,3 +153,17 @@ func (mr *MockUserRepositoryMockRecorder) Update(ctx, user interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), ctx, user)
 }
+
+// UpdateRoles mocks base method.
+func (m *MockUserRepository) UpdateRoles(ctx context.Context, user *models.User, change *models.RoleChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRoles", ctx, user, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRoles indicates an expected call of UpdateRoles.
+func (mr *MockUserRepositoryMockRecorder) UpdateRoles(ctx, user, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRoles", reflect.TypeOf((*MockUserRepository)(nil).UpdateRoles), ctx, user, change)
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ErasureReceipt records that a user's personal data was purged. Receipt is
+// the same record signed with the token keys, so it can be checked against
+// the JWKS without trusting this table.
+type ErasureReceipt struct {
+	bun.BaseModel `bun:"table:erasure_receipts"`
+
+	ID          uuid.UUID        `bun:",pk,type:uuid,default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID        `bun:",notnull,type:uuid" json:"user_id"` // no longer resolvable after the purge
+	RequestedAt time.Time        `bun:",notnull" json:"requested_at"`
+	PurgedAt    time.Time        `bun:",notnull" json:"purged_at"`
+	Erased      map[string]int64 `bun:",type:jsonb,notnull" json:"erased"` // rows deleted per table
+	Receipt     string           `bun:",notnull" json:"receipt"`
+}
+
+// ErasureClaims is the signed payload of an erasure receipt. The subject is
+// the purged user's id.
+type ErasureClaims struct {
+	RequestedAt int64            `json:"requested_at"`
+	Erased      map[string]int64 `json:"erased"`
+	jwt.RegisteredClaims
+}
+
+type DeleteAccountInput struct {
+	OTP string `json:"otp" validate:"required"`
+}
+
+// AccountDeletion tells the user until when the account can be restored.
+type AccountDeletion struct {
+	RequestedAt time.Time `json:"requested_at"`
+	PurgeAt     time.Time `json:"purge_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// LoginSession is a device the user is logged in on. It is created at login
+// and bound to the refresh-token family issued for that login, so revoking
+// the session revokes exactly that device's tokens.
+type LoginSession struct {
+	bun.BaseModel `bun:"table:user_sessions"`
+
+	ID         uuid.UUID `bun:",pk,type:uuid,default:gen_random_uuid()" json:"id"`
+	UserID     uuid.UUID `bun:",notnull,type:uuid" json:"-"`
+	FamilyID   uuid.UUID `bun:",notnull,unique,type:uuid" json:"-"`
+	DeviceID   string    `bun:",nullzero" json:"device_id,omitempty"`
+	DeviceName string    `bun:",nullzero" json:"device_name,omitempty"`
+	Platform   string    `bun:",nullzero" json:"platform,omitempty"`
+	AppVersion string    `bun:",nullzero" json:"app_version,omitempty"`
+	IP         string    `bun:",nullzero" json:"ip,omitempty"`
+	UserAgent  string    `bun:",nullzero" json:"user_agent,omitempty"`
+	LastSeenAt time.Time `bun:",notnull" json:"last_seen_at"`
+	RevokedAt  time.Time `bun:",nullzero" json:"-"`
+	CreatedAt  time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+
+	// Current marks the session the request was made from.
+	Current bool `bun:"-" json:"current"`
+}
+
+func (s *LoginSession) IsRevoked() bool {
+	return !s.RevokedAt.IsZero()
+}
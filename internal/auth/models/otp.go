@@ -3,16 +3,46 @@ package models
 import (
 	"time"
 
+	otpModels "swasthAI/internal/otpchannel/models"
+
 	"github.com/google/uuid"
+	"github.com/uptrace/bun"
 )
 
 type OTP struct {
-	ID        uuid.UUID `bun:",pk,type:uuid,default:uuid_generate_v4()" json:"id" validate:"omitempty,numeric"`
-	Phone     string    `bun:",notnull" json:"phone" validate:"required,e164"`            // e.g. +919876543210
-	OTP       string    `bun:",notnull" json:"otp" validate:"required,len=6,numeric"`     // must be 6 digits
-	Attempts  int       `bun:",notnull,default:0" json:"attempts" validate:"gte=0,lte=3"` // 0–3 attempts
-	ExpiresAt time.Time `bun:",notnull" json:"expires_at" validate:"required"`            // must be valid time
-	Verified  bool      `bun:",notnull,default:false" json:"verified" validate:"boolean"` // true if verified
+	bun.BaseModel `bun:"table:otps"`
+
+	ID          uuid.UUID `bun:",pk,type:uuid,default:uuid_generate_v4()" json:"id" validate:"omitempty,numeric"`
+	Phone       string    `bun:",notnull" json:"phone" validate:"required,e164"`            // e.g. +919876543210
+	OTP         string    `bun:"-" json:"-" validate:"required,numeric,min=4,max=10"`       // plain code, never persisted
+	CodeHash    string    `bun:",notnull" json:"-"`                                         // bcrypt hash of OTP
+	Attempts    int       `bun:",notnull,default:0" json:"attempts" validate:"gte=0"`       // bounded by the OTP policy
+	ExpiresAt   time.Time `bun:",notnull" json:"expires_at" validate:"required"`            // must be valid time
+	Verified    bool      `bun:",notnull,default:false" json:"verified" validate:"boolean"` // true if verified
+	LockedUntil time.Time `bun:",nullzero" json:"locked_until,omitempty"`                   // set once attempts run out
+	VerifiedAt  time.Time `bun:",nullzero" json:"verified_at,omitempty"`
+	ConsumedAt  time.Time `bun:",nullzero" json:"-"` // set when a verified code is used to register
+	CreatedAt   time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+
+	Channel otpModels.Channel `bun:",nullzero" json:"channel,omitempty"` // how the code was delivered
+}
+
+// IsLocked reports whether new codes are blocked for the phone because this
+// one ran out of attempts.
+func (o *OTP) IsLocked(now time.Time) bool {
+	return !o.Verified && now.Before(o.LockedUntil)
+}
+
+// IsExpired reports whether the OTP is past its expiry at the given time.
+func (o *OTP) IsExpired(now time.Time) bool {
+	return !now.Before(o.ExpiresAt)
+}
+
+// Channel may name another channel than the last code's, e.g. a voice
+// call when the SMS could not be read.
+type ResendOTPInput struct {
+	Phone   string            `json:"phone" validate:"required"`
+	Channel otpModels.Channel `json:"channel"`
 }
 
 type VerifyOTPInput struct {
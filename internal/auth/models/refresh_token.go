@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// RefreshToken records an issued refresh token by its jti. Tokens issued
+// from one login share a FamilyID; every refresh rotates to a new token in
+// the same family.
+type RefreshToken struct {
+	bun.BaseModel `bun:"table:refresh_tokens"`
+
+	ID         uuid.UUID `bun:",pk,type:uuid" json:"id"`
+	UserID     uuid.UUID `bun:",notnull,type:uuid" json:"user_id"`
+	FamilyID   uuid.UUID `bun:",notnull,type:uuid" json:"family_id"`
+	DeviceID   string    `bun:",nullzero" json:"device_id,omitempty"`
+	UserAgent  string    `bun:",nullzero" json:"user_agent,omitempty"`
+	IP         string    `bun:",nullzero" json:"ip,omitempty"`
+	ExpiresAt  time.Time `bun:",notnull" json:"expires_at"`
+	RotatedAt  time.Time `bun:",nullzero" json:"rotated_at,omitempty"`
+	ReplacedBy uuid.UUID `bun:",nullzero,type:uuid" json:"replaced_by,omitempty"`
+	RevokedAt  time.Time `bun:",nullzero" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return !t.RevokedAt.IsZero()
+}
+
+func (t *RefreshToken) IsRotated() bool {
+	return !t.RotatedAt.IsZero()
+}
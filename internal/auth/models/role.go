@@ -0,0 +1,117 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Role is a product surface a user can act on.
+type Role string
+
+const (
+	RolePatient    Role = "patient"
+	RoleASHAWorker Role = "asha_worker"
+	RoleDoctor     Role = "doctor"
+	RoleAdmin      Role = "admin"
+)
+
+// Permission is a single action, written "<resource>:<action>".
+type Permission string
+
+const (
+	PermProfileRead       Permission = "profile:read"
+	PermProfileWrite      Permission = "profile:write"
+	PermVoiceUse          Permission = "voice:use"
+	PermConsultationRead  Permission = "consultation:read"
+	PermConsultationWrite Permission = "consultation:write"
+	PermPatientRead       Permission = "patient:read"
+	PermPatientWrite      Permission = "patient:write"
+	PermUserRead          Permission = "user:read"
+	PermRoleRead          Permission = "role:read"
+	PermRoleAssign        Permission = "role:assign"
+	PermAbuseRead         Permission = "abuse:read"
+	PermAbuseManage       Permission = "abuse:manage"
+	PermAuditRead         Permission = "audit:read"
+)
+
+var patientPermissions = []Permission{
+	PermProfileRead, PermProfileWrite, PermVoiceUse, PermConsultationRead, PermConsultationWrite,
+}
+
+// RolePermissions lists what each role may do. Users can be granted extra
+// permissions on top of their roles.
+var RolePermissions = map[Role][]Permission{
+	RolePatient:    patientPermissions,
+	RoleASHAWorker: append(append([]Permission{}, patientPermissions...), PermPatientRead, PermPatientWrite),
+	RoleDoctor:     {PermProfileRead, PermProfileWrite, PermConsultationRead, PermConsultationWrite, PermPatientRead},
+	RoleAdmin:      append(append([]Permission{}, patientPermissions...), PermUserRead, PermRoleRead, PermRoleAssign, PermAbuseRead, PermAbuseManage, PermAuditRead),
+}
+
+func (r Role) IsValid() bool {
+	_, ok := RolePermissions[r]
+	return ok
+}
+
+func (p Permission) IsValid() bool {
+	for _, perms := range RolePermissions {
+		for _, known := range perms {
+			if p == known {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EffectivePermissions merges the permissions of roles with the extra
+// grants, sorted and without duplicates.
+func EffectivePermissions(roles []Role, extra []Permission) []Permission {
+	seen := make(map[Permission]bool)
+	var perms []Permission
+	add := func(p Permission) {
+		if !seen[p] {
+			seen[p] = true
+			perms = append(perms, p)
+		}
+	}
+	for _, role := range roles {
+		for _, p := range RolePermissions[role] {
+			add(p)
+		}
+	}
+	for _, p := range extra {
+		add(p)
+	}
+	sort.Slice(perms, func(i, j int) bool { return perms[i] < perms[j] })
+	return perms
+}
+
+// RoleChange records one change to a user's roles or permissions.
+type RoleChange struct {
+	bun.BaseModel `bun:"table:role_changes"`
+
+	ID             uuid.UUID    `bun:",pk,type:uuid,default:gen_random_uuid()" json:"id"`
+	UserID         uuid.UUID    `bun:",notnull,type:uuid" json:"user_id"`
+	ChangedBy      uuid.UUID    `bun:",nullzero,type:uuid" json:"changed_by,omitempty"` // empty for config bootstrap
+	OldRoles       []Role       `bun:",array" json:"old_roles"`
+	NewRoles       []Role       `bun:",array" json:"new_roles"`
+	OldPermissions []Permission `bun:",array" json:"old_permissions"`
+	NewPermissions []Permission `bun:",array" json:"new_permissions"`
+	Reason         string       `bun:",nullzero" json:"reason,omitempty"`
+	CreatedAt      time.Time    `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+}
+
+type AssignRolesInput struct {
+	Roles       []Role       `json:"roles" validate:"required,min=1,dive,required"`
+	Permissions []Permission `json:"permissions" validate:"omitempty,dive,required"`
+	Reason      string       `json:"reason" validate:"required,min=3,max=200"`
+}
+
+// RoleInfo describes a role for the admin API.
+type RoleInfo struct {
+	Role        Role         `json:"role"`
+	Permissions []Permission `json:"permissions"`
+}
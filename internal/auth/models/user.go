@@ -4,6 +4,9 @@ import (
 	"strings"
 	"time"
 
+	otpModels "swasthAI/internal/otpchannel/models"
+	"swasthAI/pkg/names"
+
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 )
@@ -12,20 +15,63 @@ import (
 type User struct {
 	bun.BaseModel `bun:"table:users"`
 
-	ID        uuid.UUID `bun:",pk,type:uuid,default:uuid_generate_v4()" json:"id" validate:"required,uuid4"`
-	Phone     string    `bun:",unique,notnull" json:"phone" validate:"required,e164"` // e164 = +919876543210 format
-	FirstName string    `bun:",notnull" json:"first_name" validate:"required,alpha,min=2,max=50"`
-	LastName  string    `bun:",notnull" json:"last_name" validate:"required,alpha,min=2,max=50"`
-	FullName  string    `bun:",notnull" json:"full_name" validate:"required"`
-	Language  string    `bun:",notnull" json:"language" validate:"required,alpha,len=2"` // e.g. 'en', 'hi'
-	CreatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+	ID          uuid.UUID    `bun:",pk,type:uuid,default:uuid_generate_v4()" json:"id" validate:"required,uuid4"`
+	Phone       string       `bun:",unique,notnull" json:"phone" validate:"required,e164"` // e164 = +919876543210 format
+	FirstName   string       `bun:",notnull" json:"first_name" validate:"required,name,min=2,max=50"`
+	LastName    string       `bun:",notnull" json:"last_name" validate:"required,name,min=2,max=50"`
+	FullName    string       `bun:",notnull" json:"full_name" validate:"required"`
+	Language    string       `bun:",notnull" json:"language" validate:"required,alpha,len=2"` // e.g. 'en', 'hi'
+	Roles       []Role       `bun:",array,notnull,default:'{patient}'" json:"roles"`
+	Permissions []Permission `bun:",array,notnull,default:'{}'" json:"permissions,omitempty"` // granted on top of Roles
+	CreatedAt   time.Time    `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+
+	// Latin forms of the names, which are kept in the script they were
+	// written in; empty when that script cannot be romanised
+	FirstNameLatin string `bun:",nullzero" json:"first_name_latin,omitempty"`
+	LastNameLatin  string `bun:",nullzero" json:"last_name_latin,omitempty"`
+	FullNameLatin  string `bun:",nullzero" json:"full_name_latin,omitempty"`
+
+	// set while the account waits out the deletion grace period
+	DeletionRequestedAt time.Time `bun:",nullzero" json:"deletion_requested_at,omitempty"`
+	PurgeAt             time.Time `bun:",nullzero" json:"purge_at,omitempty"`
 }
 
 func (u *User) PrepareCreate() {
-	u.FullName = u.FirstName + " " + u.LastName
 	u.Phone = strings.TrimSpace(u.Phone)
+	u.PrepareNames()
+	if len(u.Roles) == 0 {
+		u.Roles = []Role{RolePatient}
+	}
+	if u.Permissions == nil {
+		u.Permissions = []Permission{}
+	}
+}
+
+// PrepareNames trims the names and derives the full name and the Latin
+// forms. Latin forms left empty are transliterated from the names.
+func (u *User) PrepareNames() {
 	u.FirstName = strings.TrimSpace(u.FirstName)
 	u.LastName = strings.TrimSpace(u.LastName)
+	u.FullName = strings.TrimSpace(u.FirstName + " " + u.LastName)
+	u.FirstNameLatin = names.LatinForm(u.FirstName, u.FirstNameLatin)
+	u.LastNameLatin = names.LatinForm(u.LastName, u.LastNameLatin)
+	u.FullNameLatin = strings.TrimSpace(u.FirstNameLatin + " " + u.LastNameLatin)
+}
+
+// HasRole reports whether the user holds role.
+func (u *User) HasRole(role Role) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPendingDeletion reports whether the user asked for their account to be
+// deleted and it has not been restored.
+func (u *User) IsPendingDeletion() bool {
+	return !u.PurgeAt.IsZero()
 }
 
 type UserWithToken struct {
@@ -40,19 +86,25 @@ type Tokens struct {
 	ExpiresIn    int    `json:"expires_in" validate:"required,gt=0"`
 }
 
+// Latin forms are optional; when omitted they are transliterated.
 type UpdateProfileInput struct {
-	FirstName string `json:"first_name" validate:"required,min=2,max=50"`
-	LastName  string `json:"last_name" validate:"omitempty,min=2,max=50"`
-	Language  string `json:"language" validate:"required,alpha,len=2"`
+	FirstName      string `json:"first_name" validate:"required,name,min=2,max=50"`
+	LastName       string `json:"last_name" validate:"omitempty,name,min=2,max=50"`
+	FirstNameLatin string `json:"first_name_latin" validate:"omitempty,latinname,min=2,max=50"`
+	LastNameLatin  string `json:"last_name_latin" validate:"omitempty,latinname,min=2,max=50"`
+	Language       string `json:"language" validate:"required,alpha,len=2"`
 }
 
 type RegisterUserInput struct {
-	FirstName string `json:"first_name" validate:"required,min=2,max=50"`
-	LastName  string `json:"last_name" validate:"required,min=2,max=50"`
-	Language  string `json:"language" validate:"required,alpha,len=2"`
-	Phone     string `json:"phone" validate:"required,e164"`
+	FirstName      string `json:"first_name" validate:"required,name,min=2,max=50"`
+	LastName       string `json:"last_name" validate:"required,name,min=2,max=50"`
+	FirstNameLatin string `json:"first_name_latin" validate:"omitempty,latinname,min=2,max=50"`
+	LastNameLatin  string `json:"last_name_latin" validate:"omitempty,latinname,min=2,max=50"`
+	Language       string `json:"language" validate:"required,alpha,len=2"`
+	Phone          string `json:"phone" validate:"required"` // normalized to E.164 by the usecase
 }
 
 type SendOTPInput struct {
-	Phone string `json:"phone" validate:"required,e164"`
+	Phone   string            `json:"phone" validate:"required"`
+	Channel otpModels.Channel `json:"channel"` // "sms", "voice" or "whatsapp"; empty uses the default
 }
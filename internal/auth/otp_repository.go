@@ -4,12 +4,18 @@ import (
 	"context"
 	"swasthAI/internal/auth/models"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type OTPRepository interface {
 	Create(ctx context.Context, otp *models.OTP) error
 	FindByPhone(ctx context.Context, phone string) (models.OTP, error)
-	IncrementAttempts(ctx context.Context, phone string) error
+	IncrementAttempts(ctx context.Context, id uuid.UUID, maxAttempts int) error
+	Lock(ctx context.Context, id uuid.UUID, until time.Time) error
+	MarkVerified(ctx context.Context, id uuid.UUID) error
+	ConsumeVerified(ctx context.Context, phone string, since time.Time) error
 	Delete(ctx context.Context, phone string) error
-	CountRecent(ctx context.Context, phone string, duration time.Time) (int, error)
+	CountRecent(ctx context.Context, phone string, since time.Time) (int, error)
+	FirstRecent(ctx context.Context, phone string, since time.Time) (time.Time, error)
 }
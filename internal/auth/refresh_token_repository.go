@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"context"
+	"swasthAI/internal/auth/models"
+
+	"github.com/google/uuid"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.RefreshToken, error)
+	Rotate(ctx context.Context, oldID uuid.UUID, next *models.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+}
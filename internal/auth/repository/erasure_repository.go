@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"swasthAI/internal/auth"
+	"swasthAI/internal/auth/models"
+	"swasthAI/pkg/domain_errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+// erasureTarget is a table holding personal data and the column linking it
+// to a user, either by id or by phone.
+type erasureTarget struct {
+	table   string
+	column  string
+	byPhone bool
+}
+
+// erasureTargets lists every table with a user's personal data. Tables
+// holding conversation logs, reports or consultations must be added here
+// when they are introduced. users goes last as the others refer to it.
+// Voice sessions depend on voice.store and are erased through their own
+// repository before the purge.
+var erasureTargets = []erasureTarget{
+	{table: "otps", column: "phone", byPhone: true},
+	{table: "sms_outbox", column: "recipient", byPhone: true},
+	{table: "refresh_tokens", column: "user_id"},
+	{table: "user_sessions", column: "user_id"},
+	{table: "role_changes", column: "user_id"},
+	{table: "patient_delegations", column: "user_id"},
+	{table: "consent_records", column: "subject_id"},
+	{table: "health_profiles", column: "subject_id"},
+	{table: "users", column: "id"},
+}
+
+// patientTargets lists every table with a managed patient's data and the
+// column holding the patient's id. They are erased with the last account
+// holding a delegation for the patient, as nobody could reach them after.
+// The patient's voice sessions were started by that account and go with
+// its own. patient_profiles goes last.
+var patientTargets = []erasureTarget{
+	{table: "consent_records", column: "subject_id"},
+	{table: "health_profiles", column: "subject_id"},
+	{table: "patient_delegations", column: "patient_id"},
+	{table: "patient_profiles", column: "id"},
+}
+
+type ErasureRepository struct {
+	db *bun.DB
+}
+
+func NewErasureRepository(db *bun.DB) *ErasureRepository {
+	return &ErasureRepository{db: db}
+}
+
+// ListDue returns accounts whose deletion grace period ended before now.
+func (r *ErasureRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*models.User, error) {
+	var users []*models.User
+	err := r.db.NewSelect().
+		Model(&users).
+		Where("purge_at IS NOT NULL AND purge_at <= ?", now).
+		Order("purge_at ASC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "erasureRepo.ListDue.Select")
+	}
+	return users, nil
+}
+
+// Purge deletes the user's rows from every erasure target, and those of the
+// patients no other account holds a delegation for from every patient
+// target, and stores the receipt built by seal, all in one transaction. An account restored in the
+// meantime is left alone and reported as ErrUserNotFound.
+func (r *ErasureRepository) Purge(ctx context.Context, user *models.User, seal auth.SealErasure) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.NewSelect().
+			Model((*models.User)(nil)).
+			Column("id").
+			Where("id = ?", user.ID).
+			Where("purge_at IS NOT NULL AND purge_at <= ?", time.Now().UTC()).
+			For("UPDATE").
+			Scan(ctx, new(string))
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain_errors.ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		erased := make(map[string]int64, len(erasureTargets)+len(patientTargets))
+		// found before the user's own delegations go
+		var orphans []uuid.UUID
+		err = tx.NewSelect().
+			TableExpr("patient_delegations AS d").
+			ColumnExpr("DISTINCT d.patient_id").
+			Where("d.user_id = ?", user.ID).
+			Where("d.revoked_at IS NULL").
+			Where("NOT EXISTS (SELECT 1 FROM patient_delegations AS o WHERE o.patient_id = d.patient_id AND o.user_id <> d.user_id AND o.revoked_at IS NULL)").
+			Scan(ctx, &orphans)
+		if err != nil {
+			return errors.Wrap(err, "orphaned patients")
+		}
+		if len(orphans) > 0 {
+			for _, target := range patientTargets {
+				if err := erase(ctx, tx, target, orphans, erased); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, target := range erasureTargets {
+			var value any = user.ID
+			if target.byPhone {
+				value = user.Phone
+			}
+			if err := erase(ctx, tx, target, []any{value}, erased); err != nil {
+				return err
+			}
+		}
+
+		receipt, err := seal(erased)
+		if err != nil {
+			return err
+		}
+		_, err = tx.NewInsert().Model(receipt).Returning("*").Exec(ctx)
+		return err
+	})
+	if errors.Is(err, domain_errors.ErrUserNotFound) {
+		return domain_errors.ErrUserNotFound
+	}
+	if err != nil {
+		return errors.Wrap(err, "erasureRepo.Purge.Tx")
+	}
+	return nil
+}
+
+// erase deletes the rows of target whose column is among the values slice,
+// adding their count to erased.
+func erase(ctx context.Context, tx bun.Tx, target erasureTarget, values any, erased map[string]int64) error {
+	res, err := tx.NewDelete().
+		TableExpr("?", bun.Ident(target.table)).
+		Where("? IN (?)", bun.Ident(target.column), bun.In(values)).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, target.table)
+	}
+	n, _ := res.RowsAffected()
+	erased[target.table] += n
+	return nil
+}
@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"swasthAI/internal/auth/models"
+	"swasthAI/internal/auth/repository"
+	consentModels "swasthAI/internal/consent/models"
+	healthModels "swasthAI/internal/health/models"
+	patientModels "swasthAI/internal/patient/models"
+	smsModels "swasthAI/internal/sms/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createErasureTables creates the tables Purge erases from.
+func createErasureTables(t *testing.T) {
+	ctx := context.Background()
+	for _, model := range []any{
+		(*models.OTP)(nil),
+		(*smsModels.OutboxMessage)(nil),
+		(*models.RefreshToken)(nil),
+		(*models.LoginSession)(nil),
+		(*models.ErasureReceipt)(nil),
+		(*patientModels.Profile)(nil),
+		(*patientModels.Delegation)(nil),
+		(*consentModels.ConsentRecord)(nil),
+		(*healthModels.HealthProfile)(nil),
+	} {
+		_, err := testDB.NewCreateTable().Model(model).IfNotExists().Exec(ctx)
+		require.NoError(t, err)
+	}
+}
+
+func TestErasureRepository_PurgeErasesOrphanedPatients(t *testing.T) {
+	ctx := context.Background()
+	createErasureTables(t)
+	repo := repository.NewErasureRepository(testDB)
+
+	now := time.Now().UTC()
+	user := &models.User{Phone: "+914444444444", FirstName: "Test", LastName: "User", DeletionRequestedAt: now.Add(-time.Hour), PurgeAt: now.Add(-time.Minute)}
+	other := &models.User{Phone: "+913333333333", FirstName: "Other", LastName: "User"}
+	for _, u := range []*models.User{user, other} {
+		u.PrepareCreate()
+		_, err := testDB.NewInsert().Model(u).Returning("*").Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	// solo is managed by the user alone, shared by the other account too
+	solo := &patientModels.Profile{FirstName: "Sita", Language: "hi", CreatedBy: user.ID}
+	shared := &patientModels.Profile{FirstName: "Gita", Language: "hi", CreatedBy: user.ID}
+	for _, p := range []*patientModels.Profile{solo, shared} {
+		p.PrepareSave()
+		_, err := testDB.NewInsert().Model(p).Returning("*").Exec(ctx)
+		require.NoError(t, err)
+		_, err = testDB.NewInsert().Model(&patientModels.Delegation{PatientID: p.ID, UserID: user.ID, Relation: patientModels.RelationFamily, Permissions: patientModels.AllDelegatedPermissions, GrantedBy: user.ID}).Exec(ctx)
+		require.NoError(t, err)
+		_, err = testDB.NewInsert().Model(&healthModels.HealthProfile{SubjectID: p.ID, Version: 1, UpdatedBy: user.ID}).Exec(ctx)
+		require.NoError(t, err)
+		_, err = testDB.NewInsert().Model(&consentModels.ConsentRecord{SubjectID: p.ID, RecordedBy: user.ID, Purpose: consentModels.PurposeVoiceAI, Version: 1, Language: "hi", Action: consentModels.ConsentGranted}).Exec(ctx)
+		require.NoError(t, err)
+	}
+	_, err := testDB.NewInsert().Model(&patientModels.Delegation{PatientID: shared.ID, UserID: other.ID, Relation: patientModels.RelationCaregiver, Permissions: patientModels.AllDelegatedPermissions, GrantedBy: user.ID}).Exec(ctx)
+	require.NoError(t, err)
+
+	var erased map[string]int64
+	err = repo.Purge(ctx, user, func(e map[string]int64) (*models.ErasureReceipt, error) {
+		erased = e
+		return &models.ErasureReceipt{ID: uuid.New(), UserID: user.ID, RequestedAt: user.DeletionRequestedAt, PurgedAt: now, Erased: e, Receipt: "signed"}, nil
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, erased["patient_profiles"])
+	assert.EqualValues(t, 3, erased["patient_delegations"])
+
+	count := func(model any, where string, id uuid.UUID) int {
+		n, err := testDB.NewSelect().Model(model).Where(where, id).Count(ctx)
+		require.NoError(t, err)
+		return n
+	}
+	assert.Zero(t, count((*patientModels.Profile)(nil), "id = ?", solo.ID))
+	assert.Zero(t, count((*healthModels.HealthProfile)(nil), "subject_id = ?", solo.ID))
+	assert.Zero(t, count((*consentModels.ConsentRecord)(nil), "subject_id = ?", solo.ID))
+
+	// the other account still manages shared
+	assert.Equal(t, 1, count((*patientModels.Profile)(nil), "id = ?", shared.ID))
+	assert.Equal(t, 1, count((*healthModels.HealthProfile)(nil), "subject_id = ?", shared.ID))
+	assert.Equal(t, 1, count((*patientModels.Delegation)(nil), "patient_id = ?", shared.ID))
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"swasthAI/internal/auth/models"
+	"swasthAI/pkg/domain_errors"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type LoginSessionRepository struct {
+	db *bun.DB
+}
+
+func NewLoginSessionRepository(db *bun.DB) *LoginSessionRepository {
+	return &LoginSessionRepository{db: db}
+}
+
+func (r *LoginSessionRepository) Create(ctx context.Context, session *models.LoginSession) error {
+	_, err := r.db.NewInsert().Model(session).Returning("*").Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "loginSessionRepo.Create.Insert")
+	}
+	return nil
+}
+
+func (r *LoginSessionRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.LoginSession, error) {
+	session := new(models.LoginSession)
+	err := r.db.NewSelect().Model(session).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain_errors.ErrLoginSessionNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "loginSessionRepo.FindByID.Select")
+	}
+	return session, nil
+}
+
+// FindByFamily returns the session bound to a refresh-token family, i.e. the
+// login an access token was issued for.
+func (r *LoginSessionRepository) FindByFamily(ctx context.Context, familyID uuid.UUID) (*models.LoginSession, error) {
+	session := new(models.LoginSession)
+	err := r.db.NewSelect().Model(session).Where("family_id = ?", familyID).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain_errors.ErrLoginSessionNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "loginSessionRepo.FindByFamily.Select")
+	}
+	return session, nil
+}
+
+// ListActive returns the user's sessions that are not revoked, most recently
+// used first.
+func (r *LoginSessionRepository) ListActive(ctx context.Context, userID uuid.UUID) ([]*models.LoginSession, error) {
+	var sessions []*models.LoginSession
+	err := r.db.NewSelect().
+		Model(&sessions).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Order("last_seen_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "loginSessionRepo.ListActive.Select")
+	}
+	return sessions, nil
+}
+
+// Touch records that the session bound to familyID was used again.
+func (r *LoginSessionRepository) Touch(ctx context.Context, familyID uuid.UUID, ip string, seenAt time.Time) error {
+	q := r.db.NewUpdate().
+		Model((*models.LoginSession)(nil)).
+		Set("last_seen_at = ?", seenAt).
+		Where("family_id = ?", familyID).
+		Where("revoked_at IS NULL")
+	if ip != "" {
+		q = q.Set("ip = ?", ip)
+	}
+	if _, err := q.Exec(ctx); err != nil {
+		return errors.Wrap(err, "loginSessionRepo.Touch.Update")
+	}
+	return nil
+}
+
+func (r *LoginSessionRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.LoginSession)(nil)).
+		Set("revoked_at = ?", time.Now().UTC()).
+		Where("family_id = ?", familyID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "loginSessionRepo.RevokeFamily.Update")
+	}
+	return nil
+}
+
+func (r *LoginSessionRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.LoginSession)(nil)).
+		Set("revoked_at = ?", time.Now().UTC()).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "loginSessionRepo.RevokeAllForUser.Update")
+	}
+	return nil
+}
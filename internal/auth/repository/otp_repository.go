@@ -2,9 +2,16 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"time"
-	"github.com/uptrace/bun"
+
 	"swasthAI/internal/auth/models"
+	"swasthAI/pkg/domain_errors"
+	"swasthAI/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
 )
 
 type OTPRepository struct {
@@ -15,22 +22,151 @@ func NewOTPRepository(db *bun.DB) *OTPRepository {
 	return &OTPRepository{db: db}
 }
 
-func (o *OTPRepository) Create(ctx context.Context, otp *models.OTP) error {
+// Create hashes the plain code and stores the OTP. The plain code is never persisted.
+func (r *OTPRepository) Create(ctx context.Context, otp *models.OTP) error {
+	hash, err := utils.HashOTP(otp.OTP)
+	if err != nil {
+		return errors.Wrap(err, "otpRepo.Create.HashOTP")
+	}
+	otp.CodeHash = hash
+
+	_, err = r.db.NewInsert().Model(otp).Returning("*").Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "otpRepo.Create.Insert")
+	}
 	return nil
 }
 
+// FindByPhone returns the most recently issued OTP for the phone.
 func (r *OTPRepository) FindByPhone(ctx context.Context, phone string) (models.OTP, error) {
-	return models.OTP{}, nil
+	var otp models.OTP
+	err := r.db.NewSelect().
+		Model(&otp).
+		Where("phone = ?", phone).
+		OrderExpr("created_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.OTP{}, domain_errors.ErrOTPNotFound
+	}
+	if err != nil {
+		return models.OTP{}, errors.Wrap(err, "otpRepo.FindByPhone.Select")
+	}
+	return otp, nil
+}
+
+// IncrementAttempts consumes one verification attempt. It fails with
+// ErrOTPAttemptsExceeded once the limit is reached, so concurrent guesses
+// cannot exceed it.
+func (r *OTPRepository) IncrementAttempts(ctx context.Context, id uuid.UUID, maxAttempts int) error {
+	res, err := r.db.NewUpdate().
+		Model((*models.OTP)(nil)).
+		Set("attempts = attempts + 1").
+		Where("id = ?", id).
+		Where("verified = FALSE").
+		Where("attempts < ?", maxAttempts).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "otpRepo.IncrementAttempts.Update")
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain_errors.ErrOTPAttemptsExceeded
+	}
+	return nil
 }
 
-func (r *OTPRepository) IncrementAttempts(ctx context.Context, phone string) error {
+// Lock blocks new codes for the OTP's phone until the given time.
+func (r *OTPRepository) Lock(ctx context.Context, id uuid.UUID, until time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.OTP)(nil)).
+		Set("locked_until = ?", until).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "otpRepo.Lock.Update")
+	}
+	return nil
+}
+
+// MarkVerified flags the OTP as used. It fails with ErrInvalidOTP if the
+// code was already verified, so each code can log in only once.
+func (r *OTPRepository) MarkVerified(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.NewUpdate().
+		Model((*models.OTP)(nil)).
+		Set("verified = TRUE").
+		Set("verified_at = current_timestamp").
+		Where("id = ?", id).
+		Where("verified = FALSE").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "otpRepo.MarkVerified.Update")
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain_errors.ErrInvalidOTP
+	}
+	return nil
+}
+
+// ConsumeVerified uses up the phone's latest OTP if it was verified since
+// the given time. It fails with ErrPhoneNotVerified if there is no such code
+// or it was already consumed, so one verification registers one account.
+func (r *OTPRepository) ConsumeVerified(ctx context.Context, phone string, since time.Time) error {
+	latest := r.db.NewSelect().
+		Model((*models.OTP)(nil)).
+		Column("id").
+		Where("phone = ?", phone).
+		OrderExpr("created_at DESC").
+		Limit(1)
+	res, err := r.db.NewUpdate().
+		Model((*models.OTP)(nil)).
+		Set("consumed_at = current_timestamp").
+		Where("id = (?)", latest).
+		Where("verified = TRUE").
+		Where("verified_at >= ?", since).
+		Where("consumed_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "otpRepo.ConsumeVerified.Update")
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain_errors.ErrPhoneNotVerified
+	}
 	return nil
 }
 
 func (r *OTPRepository) Delete(ctx context.Context, phone string) error {
+	_, err := r.db.NewDelete().Model((*models.OTP)(nil)).Where("phone = ?", phone).Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "otpRepo.Delete.Delete")
+	}
 	return nil
 }
 
-func (r *OTPRepository) CountRecent(ctx context.Context, phone string, duration time.Time) (int, error) {
-	return 0, nil
+// CountRecent returns how many OTPs were issued to the phone since the given time.
+func (r *OTPRepository) CountRecent(ctx context.Context, phone string, since time.Time) (int, error) {
+	count, err := r.db.NewSelect().
+		Model((*models.OTP)(nil)).
+		Where("phone = ?", phone).
+		Where("created_at >= ?", since).
+		Count(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "otpRepo.CountRecent.Count")
+	}
+	return count, nil
+}
+
+// FirstRecent returns when the oldest OTP issued to the phone since the
+// given time was created, i.e. when it drops out of a sliding window.
+func (r *OTPRepository) FirstRecent(ctx context.Context, phone string, since time.Time) (time.Time, error) {
+	var first bun.NullTime
+	err := r.db.NewSelect().
+		Model((*models.OTP)(nil)).
+		ColumnExpr("MIN(created_at)").
+		Where("phone = ?", phone).
+		Where("created_at >= ?", since).
+		Scan(ctx, &first)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "otpRepo.FirstRecent.Select")
+	}
+	return first.Time, nil
 }
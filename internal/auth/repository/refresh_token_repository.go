@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"swasthAI/internal/auth/models"
+	"swasthAI/pkg/domain_errors"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type RefreshTokenRepository struct {
+	db *bun.DB
+}
+
+func NewRefreshTokenRepository(db *bun.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	_, err := r.db.NewInsert().Model(token).Returning("*").Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "refreshTokenRepo.Create.Insert")
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.RefreshToken, error) {
+	token := new(models.RefreshToken)
+	err := r.db.NewSelect().Model(token).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain_errors.ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "refreshTokenRepo.FindByID.Select")
+	}
+	return token, nil
+}
+
+// Rotate marks the old token as replaced and stores its successor in one
+// transaction. If the old token was already rotated or revoked, nothing is
+// stored and ErrRefreshTokenReused is returned.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, oldID uuid.UUID, next *models.RefreshToken) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewUpdate().
+			Model((*models.RefreshToken)(nil)).
+			Set("rotated_at = ?", time.Now().UTC()).
+			Set("replaced_by = ?", next.ID).
+			Where("id = ?", oldID).
+			Where("rotated_at IS NULL").
+			Where("revoked_at IS NULL").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return domain_errors.ErrRefreshTokenReused
+		}
+
+		_, err = tx.NewInsert().Model(next).Returning("*").Exec(ctx)
+		return err
+	})
+	if errors.Is(err, domain_errors.ErrRefreshTokenReused) {
+		return domain_errors.ErrRefreshTokenReused
+	}
+	if err != nil {
+		return errors.Wrap(err, "refreshTokenRepo.Rotate.Tx")
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.RefreshToken)(nil)).
+		Set("revoked_at = ?", time.Now().UTC()).
+		Where("family_id = ?", familyID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "refreshTokenRepo.RevokeFamily.Update")
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.RefreshToken)(nil)).
+		Set("revoked_at = ?", time.Now().UTC()).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "refreshTokenRepo.RevokeAllForUser.Update")
+	}
+	return nil
+}
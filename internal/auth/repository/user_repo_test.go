@@ -76,6 +76,10 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		log.Fatalf("failed to create table: %v", err)
 	}
+	_, err = testDB.NewCreateTable().Model((*models.RoleChange)(nil)).IfNotExists().Exec(ctx)
+	if err != nil {
+		log.Fatalf("failed to create table: %v", err)
+	}
 
 	// === Run tests ===
 	code := m.Run()
@@ -92,7 +96,7 @@ func TestUserRepository_Integration(t *testing.T) {
 
 	// === Helper: Reset DB before each subtest ===
 	resetDB := func(t *testing.T) {
-		_, err := testDB.ExecContext(ctx, `TRUNCATE TABLE users RESTART IDENTITY CASCADE`)
+		_, err := testDB.ExecContext(ctx, `TRUNCATE TABLE users, role_changes RESTART IDENTITY CASCADE`)
 		require.NoError(t, err)
 	}
 
@@ -168,12 +172,15 @@ func TestUserRepository_Integration(t *testing.T) {
 		created.FirstName = "अमिताभ"
 		created.Language = "ta"
 		created.FullName = "अमिताभ वर्मा" // Must set manually for Bun
+		// not a profile field, left as stored
+		created.Roles = []models.Role{models.RoleAdmin}
 
 		updated, err := repo.Update(ctx, created)
 		require.NoError(t, err)
 		assert.Equal(t, "अमिताभ", updated.FirstName)
 		assert.Equal(t, "ta", updated.Language)
 		assert.Equal(t, "अमिताभ वर्मा", updated.FullName)
+		assert.Equal(t, []models.Role{models.RolePatient}, updated.Roles)
 	})
 
 	t.Run("Create - Duplicate Phone", func(t *testing.T) {
@@ -198,4 +205,71 @@ func TestUserRepository_Integration(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "duplicate key")
 	})
+
+	t.Run("UpdateRoles and ListByRole", func(t *testing.T) {
+		resetDB(t)
+
+		user := &models.User{
+			Phone:     "+916666666666",
+			FirstName: "सीता",
+			LastName:  "देवी",
+			Language:  "hi",
+		}
+		user.PrepareCreate()
+		created, err := repo.Create(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, []models.Role{models.RolePatient}, created.Roles)
+
+		created.Roles = []models.Role{models.RolePatient, models.RoleASHAWorker}
+		change := &models.RoleChange{
+			UserID:   created.ID,
+			OldRoles: []models.Role{models.RolePatient},
+			NewRoles: created.Roles,
+			Reason:   "joined ASHA programme",
+		}
+		require.NoError(t, repo.UpdateRoles(ctx, created, change))
+
+		found, err := repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.True(t, found.HasRole(models.RoleASHAWorker))
+
+		workers, err := repo.ListByRole(ctx, models.RoleASHAWorker, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, workers, 1)
+		assert.Equal(t, created.ID, workers[0].ID)
+
+		history, err := repo.ListRoleChanges(ctx, created.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, "joined ASHA programme", history[0].Reason)
+	})
+
+	t.Run("ScheduleDeletion and CancelDeletion", func(t *testing.T) {
+		resetDB(t)
+
+		user := &models.User{
+			Phone:     "+917777777777",
+			FirstName: "Gopal",
+			LastName:  "Rao",
+			Language:  "te",
+		}
+		user.PrepareCreate()
+		created, err := repo.Create(ctx, user)
+		require.NoError(t, err)
+
+		created.DeletionRequestedAt = time.Now().UTC()
+		created.PurgeAt = created.DeletionRequestedAt.Add(time.Hour)
+		require.NoError(t, repo.ScheduleDeletion(ctx, created))
+		assert.ErrorIs(t, repo.ScheduleDeletion(ctx, created), domain_errors.ErrAccountPendingDeletion)
+
+		found, err := repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.True(t, found.IsPendingDeletion())
+
+		require.NoError(t, repo.CancelDeletion(ctx, found))
+		found, err = repo.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.False(t, found.IsPendingDeletion())
+		assert.ErrorIs(t, repo.CancelDeletion(ctx, found), domain_errors.ErrRestoreWindowExpired)
+	})
 }
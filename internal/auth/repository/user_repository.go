@@ -6,6 +6,7 @@ import (
 	"swasthAI/internal/auth/models"
 	"swasthAI/pkg/domain_errors"
 	"swasthAI/pkg/logger"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -46,15 +47,25 @@ func (r *UserRepository) FindByPhone(ctx context.Context, phone string) (*models
 func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	user := new(models.User)
 	err := r.db.NewSelect().Model(user).Where("id = ?", id).Scan(ctx)
-
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain_errors.ErrUserNotFound
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "authRepo.FindByID.Select")
 	}
 	return user, nil
 }
 
+// Update stores the user's names and language. Roles and the deletion
+// schedule have their own methods, so a profile edit made from a stale read
+// cannot undo them.
 func (r *UserRepository) Update(ctx context.Context, user *models.User) (*models.User, error) {
-	_, err := r.db.NewUpdate().Model(user).Where("id = ?", user.ID).Returning("*").Exec(ctx)
+	_, err := r.db.NewUpdate().
+		Model(user).
+		Column("first_name", "last_name", "full_name", "first_name_latin", "last_name_latin", "full_name_latin", "language").
+		Where("id = ?", user.ID).
+		Returning("*").
+		Exec(ctx)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, domain_errors.ErrUserNotFound
 	}
@@ -63,3 +74,97 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) (*models
 	}
 	return user, nil
 }
+
+// UpdateRoles stores the user's new roles and permissions and records the
+// change in the same transaction.
+func (r *UserRepository) UpdateRoles(ctx context.Context, user *models.User, change *models.RoleChange) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewUpdate().
+			Model(user).
+			Column("roles", "permissions").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return domain_errors.ErrUserNotFound
+		}
+
+		_, err = tx.NewInsert().Model(change).Returning("*").Exec(ctx)
+		return err
+	})
+	if errors.Is(err, domain_errors.ErrUserNotFound) {
+		return domain_errors.ErrUserNotFound
+	}
+	if err != nil {
+		return errors.Wrap(err, "authRepo.UpdateRoles.Tx")
+	}
+	return nil
+}
+
+// ListByRole returns users holding role, oldest first. An empty role lists
+// every user.
+func (r *UserRepository) ListByRole(ctx context.Context, role models.Role, limit, offset int) ([]*models.User, error) {
+	var users []*models.User
+	q := r.db.NewSelect().Model(&users).Order("created_at ASC", "id ASC").Limit(limit).Offset(offset)
+	if role != "" {
+		q = q.Where("? = ANY(roles)", string(role))
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, errors.Wrap(err, "authRepo.ListByRole.Select")
+	}
+	return users, nil
+}
+
+// ListRoleChanges returns the role history of a user, newest first.
+func (r *UserRepository) ListRoleChanges(ctx context.Context, userID uuid.UUID) ([]*models.RoleChange, error) {
+	var changes []*models.RoleChange
+	err := r.db.NewSelect().
+		Model(&changes).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "authRepo.ListRoleChanges.Select")
+	}
+	return changes, nil
+}
+
+// ScheduleDeletion stores the user's deletion request. It fails with
+// ErrAccountPendingDeletion if one is already pending.
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, user *models.User) error {
+	res, err := r.db.NewUpdate().
+		Model(user).
+		Column("deletion_requested_at", "purge_at").
+		WherePK().
+		Where("purge_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "authRepo.ScheduleDeletion.Update")
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain_errors.ErrAccountPendingDeletion
+	}
+	return nil
+}
+
+// CancelDeletion restores an account whose grace period has not ended yet.
+func (r *UserRepository) CancelDeletion(ctx context.Context, user *models.User) error {
+	res, err := r.db.NewUpdate().
+		Model((*models.User)(nil)).
+		Set("deletion_requested_at = NULL").
+		Set("purge_at = NULL").
+		Where("id = ?", user.ID).
+		Where("purge_at > ?", time.Now().UTC()).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "authRepo.CancelDeletion.Update")
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain_errors.ErrRestoreWindowExpired
+	}
+	user.DeletionRequestedAt = time.Time{}
+	user.PurgeAt = time.Time{}
+	return nil
+}
@@ -3,78 +3,212 @@ package usecase
 import (
 	"context"
 	"errors"
-	"fmt"
+	"slices"
 	"time"
 
 	"swasthAI/config"
+	"swasthAI/internal/abuse"
+	"swasthAI/internal/audit"
+	auditModels "swasthAI/internal/audit/models"
 	"swasthAI/internal/auth"
 	"swasthAI/internal/auth/models"
+	"swasthAI/internal/otpchannel"
+	otpModels "swasthAI/internal/otpchannel/models"
+	"swasthAI/internal/voice"
 	"swasthAI/pkg/domain_errors"
 	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/jwtkeys"
 	"swasthAI/pkg/logger"
+	"swasthAI/pkg/phone"
 	"swasthAI/pkg/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type AuthUsecase struct {
-	userRepo auth.UserRepository
-	otpRepo  auth.OTPRepository
-	cfg      config.Config
-	logger   logger.Logger
+	userRepo    auth.UserRepository
+	otpRepo     auth.OTPRepository
+	refreshRepo auth.RefreshTokenRepository
+	sessionRepo auth.LoginSessionRepository
+	erasureRepo auth.ErasureRepository
+	voiceRepo   voice.SessionRepository
+	otpRouter   otpchannel.OTPRouter
+	abuseUC     abuse.AbuseUsecase
+	auditUC     audit.AuditUsecase
+	keys        *jwtkeys.KeySet
+	phones      *phone.Normalizer
+	cfg         config.Config
+	logger      logger.Logger
+}
+
+func NewAuthUsecase(repo auth.UserRepository, otpRepo auth.OTPRepository, refreshRepo auth.RefreshTokenRepository, sessionRepo auth.LoginSessionRepository, erasureRepo auth.ErasureRepository, voiceRepo voice.SessionRepository, otpRouter otpchannel.OTPRouter, abuseUC abuse.AbuseUsecase, auditUC audit.AuditUsecase, keys *jwtkeys.KeySet, phones *phone.Normalizer, cfg config.Config, logger logger.Logger) *AuthUsecase {
+	return &AuthUsecase{userRepo: repo, otpRepo: otpRepo, refreshRepo: refreshRepo, sessionRepo: sessionRepo, erasureRepo: erasureRepo, voiceRepo: voiceRepo, otpRouter: otpRouter, abuseUC: abuseUC, auditUC: auditUC, keys: keys, phones: phones, cfg: cfg, logger: logger}
 }
 
-func NewAuthUsecase(repo auth.UserRepository, otpRepo auth.OTPRepository, cfg config.Config, logger logger.Logger) *AuthUsecase {
-	return &AuthUsecase{userRepo: repo, otpRepo: otpRepo, cfg: cfg, logger: logger}
+// SendOTP sends a code over channel, or the default channel if it is
+// empty, and returns the channel used.
+func (uc *AuthUsecase) SendOTP(ctx context.Context, phone string, channel otpModels.Channel) (_ otpModels.Channel, err error) {
+	defer func() { uc.audit(ctx, auditModels.ActionOTPSend, uuid.Nil, phone, err) }()
+
+	phone, err = domain_errors.NormalizeUserPhone(uc.phones, phone)
+	if err != nil {
+		return "", err
+	}
+
+	latest, err := uc.latestOTP(ctx, phone)
+	if err != nil {
+		return "", err
+	}
+	return uc.issueOTP(ctx, phone, latest, channel)
 }
 
-func (uc *AuthUsecase) SendOTP(ctx context.Context, phone string) error {
-	// TODO: Implement SMS sending logic
-	err := domain_errors.ValidateUserPhone(phone)
+// ResendOTP issues a fresh code for a phone that already requested one,
+// over channel or, if it is empty, the channel of the previous code.
+func (uc *AuthUsecase) ResendOTP(ctx context.Context, phone string, channel otpModels.Channel) (_ otpModels.Channel, err error) {
+	defer func() { uc.audit(ctx, auditModels.ActionOTPResend, uuid.Nil, phone, err) }()
+
+	phone, err = domain_errors.NormalizeUserPhone(uc.phones, phone)
 	if err != nil {
-		uc.logger.Error("error", err)
-		return domain_errors.ErrInvalidPhoneFormat
+		return "", err
 	}
 
-	//Rate limiting , max 3 otp / hour
-	count, err := uc.otpRepo.CountRecent(ctx, phone, time.Now().UTC().Truncate(time.Second))
+	latest, err := uc.latestOTP(ctx, phone)
+	if err != nil {
+		return "", err
+	}
+	if latest == nil {
+		return "", domain_errors.ErrOTPNotFound
+	}
+	return uc.issueOTP(ctx, phone, latest, channel)
+}
+
+// latestOTP returns the last OTP issued to the phone, or nil if there is none.
+func (uc *AuthUsecase) latestOTP(ctx context.Context, phone string) (*models.OTP, error) {
+	latest, err := uc.otpRepo.FindByPhone(ctx, phone)
+	if errors.Is(err, domain_errors.ErrOTPNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		uc.logger.Error("failed to find OTP (authUC.latestOTP.otpRepo.FindByPhone)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return &latest, nil
+}
+
+// issueOTP enforces the lockout, resend cooldown and hourly send limit from
+// the OTP policy and the abuse guard, then stores and sends a new code.
+func (uc *AuthUsecase) issueOTP(ctx context.Context, phone string, latest *models.OTP, requested otpModels.Channel) (otpModels.Channel, error) {
+	policy := uc.cfg.OTP
+	now := time.Now().UTC()
+
+	channel, err := uc.otpChannel(requested, latest)
+	if err != nil {
+		return "", err
+	}
+
+	if latest != nil {
+		if latest.IsLocked(now) {
+			return "", domain_errors.ErrOTPAttemptsExceeded.WithRetryAfter(latest.LockedUntil.Sub(now))
+		}
+		cooldownEnds := latest.CreatedAt.Add(time.Duration(policy.ResendCooldown) * time.Second)
+		if now.Before(cooldownEnds) {
+			return "", domain_errors.ErrResendCooldown.WithRetryAfter(cooldownEnds.Sub(now))
+		}
+	}
+
+	//sliding one hour window
+	windowStart := now.Add(-time.Hour)
+	count, err := uc.otpRepo.CountRecent(ctx, phone, windowStart)
 	if err != nil {
 		uc.logger.Error("error while getting recent otp count (otpRepo.CountRecent)", "error", err)
-		return appErrors.ErrInternal
+		return "", appErrors.ErrInternal
 	}
-	if count >= 3 {
-		return domain_errors.ErrOTPAttemptsExceeded
+	if count >= policy.MaxSendsPerHour {
+		first, err := uc.otpRepo.FirstRecent(ctx, phone, windowStart)
+		if err != nil {
+			uc.logger.Error("error while getting first recent otp (otpRepo.FirstRecent)", "error", err)
+			return "", appErrors.ErrInternal
+		}
+		return "", domain_errors.ErrOTPAttemptsExceeded.WithRetryAfter(first.Add(time.Hour).Sub(now))
+	}
+
+	if err := uc.abuseUC.CheckOTPSend(ctx, phone); err != nil {
+		return "", err
 	}
 
 	//create and store otp
-	otp := utils.GenerateOTP()
-	expiresAt := time.Now().Add(5 * time.Minute)
+	otp, err := uc.generateOTP()
+	if err != nil {
+		uc.logger.Error("failed to generate OTP (authUC.issueOTP.generateOTP)", "error", err)
+		return "", appErrors.ErrInternal
+	}
+	ttl := time.Duration(policy.TTL) * time.Second
 
 	err = uc.otpRepo.Create(ctx, &models.OTP{
 		Phone:     phone,
 		OTP:       otp,
-		ExpiresAt: expiresAt,
+		ExpiresAt: now.Add(ttl),
 		Attempts:  0,
 		Verified:  false,
+		Channel:   channel,
 	})
 	if err != nil {
-		uc.logger.Error("failed to store OTP (authUC.sendSMS.otpRepo.Create)", "error", err)
-		return appErrors.ErrDatabase
+		uc.logger.Error("failed to store OTP (authUC.issueOTP.otpRepo.Create)", "error", err)
+		return "", appErrors.ErrDatabase
 	}
 
-	//send sms , mocking for now
-	if err = uc.sendSMS(ctx, fmt.Sprintf("Your OTP is %s. Valid for 5 minutes.", otp), phone); err != nil {
-		uc.logger.Error("failed to send SMS", "error", err)
-		return domain_errors.ErrFailedToSendOTP
+	if err = uc.sendOTPMessage(ctx, channel, phone, otp, ttl); err != nil {
+		uc.logger.Error("failed to send OTP (authUC.issueOTP.sendOTPMessage)", "channel", channel, "error", err)
+		return "", domain_errors.ErrFailedToSendOTP.WithCause(err)
 	}
-	return nil
+	return channel, nil
 }
 
-func (uc *AuthUsecase) VerifyOTP(ctx context.Context, phone, otp string) (*models.UserWithToken, bool, error) {
+// otpChannel picks the channel for a new code: the one asked for, else the
+// one the previous code went over, else the default.
+func (uc *AuthUsecase) otpChannel(requested otpModels.Channel, latest *models.OTP) (otpModels.Channel, error) {
+	switch {
+	case requested != "":
+		if !requested.IsValid() {
+			return "", domain_errors.ErrInvalidOTPChannel
+		}
+		if !uc.otpRouter.Enabled(requested) {
+			return "", domain_errors.ErrOTPChannelUnavailable
+		}
+		return requested, nil
+	case latest != nil && uc.otpRouter.Enabled(latest.Channel):
+		return latest.Channel, nil
+	}
+	return uc.otpRouter.Default(), nil
+}
+
+// generateOTP returns a random code, or the configured fixed code when
+// running in the development environment.
+func (uc *AuthUsecase) generateOTP() (string, error) {
+	if uc.cfg.Server.Environment == "development" && uc.cfg.OTP.DevCode != "" {
+		return uc.cfg.OTP.DevCode, nil
+	}
+	return utils.GenerateOTP(uc.cfg.OTP.Length)
+}
 
-	// otpRecord, err := uc.otpRepo.FindByPhone(ctx, phone)
-	// if err !=
+func (uc *AuthUsecase) VerifyOTP(ctx context.Context, phone, otp string) (result *models.UserWithToken, exists bool, err error) {
+	defer func() {
+		if result == nil {
+			uc.audit(ctx, auditModels.ActionOTPVerify, uuid.Nil, phone, err)
+			return
+		}
+		uc.audit(ctx, auditModels.ActionOTPVerify, result.User.ID, phone, nil)
+		uc.audit(ctx, auditModels.ActionLogin, result.User.ID, phone, nil)
+	}()
 
-	// //TODO: Implement OTP verification logic
-	// otpRecord.Verified = true
+	phone, err = domain_errors.NormalizeUserPhone(uc.phones, phone)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := uc.checkOTP(ctx, phone, otp); err != nil {
+		return nil, false, err
+	}
 
 	//check if user already exists
 	existingUser, err := uc.userRepo.FindByPhone(ctx, phone)
@@ -90,34 +224,103 @@ func (uc *AuthUsecase) VerifyOTP(ctx context.Context, phone, otp string) (*model
 	}
 
 	if existingUser != nil {
-		// user already exists, generate new token
-		token, refreshToken, err := utils.GenerateJWTToken(existingUser, uc.cfg.JWT)
+		if existingUser.IsPendingDeletion() {
+			return nil, false, domain_errors.ErrAccountPendingDeletion
+		}
+		// user already exists, start a new login
+		if err := uc.bootstrapAdmin(ctx, existingUser); err != nil {
+			return nil, false, err
+		}
+		tokens, err := uc.issueTokens(ctx, existingUser)
 		if err != nil {
-			uc.logger.Error("Failed to generate token (authUC.VerifyOTP.GenerateJWTToken)", "error", err)
-			return nil, false, appErrors.ErrJWTGeneration
+			return nil, false, err
 		}
 
-		uc.otpRepo.Delete(ctx, otp)
-
 		return &models.UserWithToken{
 			User:         existingUser,
-			Token:        token,
-			RefreshToken: refreshToken,
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
 		}, true, nil
 	}
 	//signup
 	return nil, false, nil
 }
 
-func (uc *AuthUsecase) RegisterUser(ctx context.Context, input *models.RegisterUserInput) (*models.UserWithToken, error) {
-	existingUser, err := uc.userRepo.FindByPhone(ctx, input.Phone)
+// checkOTP validates the code against the latest OTP for the phone and marks
+// it verified. Every check consumes an attempt, whether it succeeds or not.
+func (uc *AuthUsecase) checkOTP(ctx context.Context, phone, code string) error {
+	otpRecord, err := uc.otpRepo.FindByPhone(ctx, phone)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrOTPNotFound) {
+			return domain_errors.ErrInvalidOTP
+		}
+		uc.logger.Error("failed to find OTP (authUC.checkOTP.otpRepo.FindByPhone)", "error", err)
+		return appErrors.ErrDatabase
+	}
+
+	if otpRecord.Verified {
+		return domain_errors.ErrInvalidOTP
+	}
+	if otpRecord.IsExpired(time.Now()) {
+		return domain_errors.ErrOTPExpired
+	}
+	maxAttempts := uc.cfg.OTP.MaxAttempts
+	if otpRecord.Attempts >= maxAttempts {
+		return domain_errors.ErrOTPAttemptsExceeded
+	}
+
+	if err := uc.otpRepo.IncrementAttempts(ctx, otpRecord.ID, maxAttempts); err != nil {
+		if errors.Is(err, domain_errors.ErrOTPAttemptsExceeded) {
+			return domain_errors.ErrOTPAttemptsExceeded
+		}
+		uc.logger.Error("failed to increment OTP attempts (authUC.checkOTP.otpRepo.IncrementAttempts)", "error", err)
+		return appErrors.ErrDatabase
+	}
+
+	if !utils.CompareOTP(otpRecord.CodeHash, code) {
+		remaining := maxAttempts - (otpRecord.Attempts + 1)
+		if remaining <= 0 {
+			lockedUntil := time.Now().UTC().Add(time.Duration(uc.cfg.OTP.LockoutDuration) * time.Second)
+			if err := uc.otpRepo.Lock(ctx, otpRecord.ID, lockedUntil); err != nil {
+				uc.logger.Error("failed to lock OTP (authUC.checkOTP.otpRepo.Lock)", "error", err)
+			}
+		}
+		return domain_errors.ErrInvalidOTP.WithDetails(map[string]any{"retry_attempts": max(remaining, 0)})
+	}
+
+	if err := uc.otpRepo.MarkVerified(ctx, otpRecord.ID); err != nil {
+		if errors.Is(err, domain_errors.ErrInvalidOTP) {
+			return domain_errors.ErrInvalidOTP
+		}
+		uc.logger.Error("failed to mark OTP verified (authUC.checkOTP.otpRepo.MarkVerified)", "error", err)
+		return appErrors.ErrDatabase
+	}
+	return nil
+}
+
+func (uc *AuthUsecase) RegisterUser(ctx context.Context, input *models.RegisterUserInput) (result *models.UserWithToken, err error) {
+	phone := input.Phone
+	defer func() {
+		subjectID := uuid.Nil
+		if result != nil {
+			subjectID = result.User.ID
+		}
+		uc.audit(ctx, auditModels.ActionRegister, subjectID, phone, err)
+	}()
+
+	phone, err = domain_errors.NormalizeUserPhone(uc.phones, phone)
+	if err != nil {
+		return nil, err
+	}
+
+	existingUser, err := uc.userRepo.FindByPhone(ctx, phone)
 
 	if err != nil && !errors.Is(err, domain_errors.ErrUserNotFound) {
 		uc.logger.Error("failed to search user (authUC.RegisterUser.userRepo.FindByPhone)", "error", err)
 		return nil, appErrors.ErrDatabase
 	}
 	if existingUser != nil {
-		uc.logger.Error("user already exists", "phone", input.Phone)
+		uc.logger.Error("user already exists", "phone", phone)
 		return nil, domain_errors.ErrUserAlreadyExists
 	}
 
@@ -127,12 +330,24 @@ func (uc *AuthUsecase) RegisterUser(ctx context.Context, input *models.RegisterU
 		return nil, domain_errors.ErrInvalidLanguage
 	}
 
+	// the phone must have been verified with a code that was not used to register before
+	since := time.Now().UTC().Add(-time.Duration(uc.cfg.OTP.RegisterWindow) * time.Second)
+	if err := uc.otpRepo.ConsumeVerified(ctx, phone, since); err != nil {
+		if errors.Is(err, domain_errors.ErrPhoneNotVerified) {
+			return nil, domain_errors.ErrPhoneNotVerified
+		}
+		uc.logger.Error("failed to consume verified OTP (authUC.RegisterUser.otpRepo.ConsumeVerified)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+
 	//create user
 	user := models.User{
-		FirstName: input.FirstName,
-		LastName:  input.LastName,
-		Phone:     input.Phone,
-		Language:  input.Language,
+		FirstName:      input.FirstName,
+		LastName:       input.LastName,
+		FirstNameLatin: input.FirstNameLatin,
+		LastNameLatin:  input.LastNameLatin,
+		Phone:          phone,
+		Language:       input.Language,
 	}
 	user.PrepareCreate()
 	createdUser, err := uc.userRepo.Create(ctx, &user)
@@ -142,51 +357,516 @@ func (uc *AuthUsecase) RegisterUser(ctx context.Context, input *models.RegisterU
 	}
 
 	//generate token
-	token, refreshToken, err := utils.GenerateJWTToken(createdUser, uc.cfg.JWT)
+	tokens, err := uc.issueTokens(ctx, createdUser)
 	if err != nil {
-		uc.logger.Error("failed to generate token (authUC.RegisterUser.GenerateJWTToken)", "error", err)
-		return nil, appErrors.ErrJWTGeneration
+		return nil, err
 	}
 	return &models.UserWithToken{
 		User:         createdUser,
-		Token:        token,
-		RefreshToken: refreshToken,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
 	}, nil
 }
 
-func (uc *AuthUsecase) RefreshToken(ctx context.Context, refreshToken string) (*models.Tokens, error) {
-	user, err := utils.ValidateRefreshToken(refreshToken, uc.cfg.JWT)
+// RefreshToken rotates a refresh token. Presenting a token that was already
+// rotated means it leaked, so the whole token family is revoked.
+func (uc *AuthUsecase) RefreshToken(ctx context.Context, refreshToken string) (_ *models.Tokens, err error) {
+	var userID uuid.UUID
+	defer func() { uc.audit(ctx, auditModels.ActionTokenRefresh, userID, "", err) }()
+
+	claims, err := utils.ValidateRefreshToken(refreshToken, uc.keys)
 	if err != nil {
 		uc.logger.Error("Invalid refresh token", "error", err)
 		return nil, appErrors.ErrUnauthorized
 	}
 
-	newToken, newRefreshToken, err := utils.GenerateJWTToken(user, uc.cfg.JWT)
+	stored, err := uc.refreshRepo.FindByID(ctx, uuid.MustParse(claims.RegisteredClaims.ID))
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrRefreshTokenNotFound) {
+			return nil, appErrors.ErrUnauthorized
+		}
+		uc.logger.Error("failed to find refresh token (authUC.RefreshToken.refreshRepo.FindByID)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	userID = stored.UserID
+	if stored.IsRevoked() {
+		return nil, domain_errors.ErrRefreshTokenRevoked
+	}
+	if stored.IsRotated() {
+		return nil, uc.revokeReusedFamily(ctx, stored)
+	}
+
+	// roles may have changed since the last refresh
+	user, err := uc.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrUserNotFound) {
+			return nil, appErrors.ErrUnauthorized
+		}
+		uc.logger.Error("failed to find user (authUC.RefreshToken.userRepo.FindByID)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	if user.IsPendingDeletion() {
+		return nil, appErrors.ErrUnauthorized
+	}
+
+	pair, err := utils.GenerateTokenPair(tokenSubject(user), stored.FamilyID, uc.keys, uc.cfg.JWT)
 	if err != nil {
 		uc.logger.Error("Failed to generate new tokens", "error", err)
 		return nil, appErrors.ErrJWTGeneration
 	}
 
+	err = uc.refreshRepo.Rotate(ctx, stored.ID, uc.newRefreshToken(ctx, stored.UserID, stored.FamilyID, pair))
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrRefreshTokenReused) {
+			return nil, uc.revokeReusedFamily(ctx, stored)
+		}
+		uc.logger.Error("failed to rotate refresh token (authUC.RefreshToken.refreshRepo.Rotate)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+
+	client := utils.ClientInfoFromContext(ctx)
+	if err := uc.sessionRepo.Touch(ctx, stored.FamilyID, client.IP, time.Now().UTC()); err != nil {
+		uc.logger.Error("failed to update session last seen (authUC.RefreshToken.sessionRepo.Touch)", "error", err)
+	}
+
 	return &models.Tokens{
-		Token:        newToken,
-		RefreshToken: newRefreshToken,
-		ExpiresIn:    86400,
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
+	}, nil
+}
+
+// Logout revokes the token family of the current login.
+func (uc *AuthUsecase) Logout(ctx context.Context) (err error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		uc.logger.Error("missing identity in context")
+		return appErrors.ErrUnauthorized
+	}
+	defer func() { uc.audit(ctx, auditModels.ActionLogout, identity.UserID, "", err) }()
+
+	return uc.revokeSession(ctx, identity.FamilyID)
+}
+
+// LogoutAll revokes every login of the current user.
+func (uc *AuthUsecase) LogoutAll(ctx context.Context) (err error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		uc.logger.Error("missing identity in context")
+		return appErrors.ErrUnauthorized
+	}
+	defer func() { uc.audit(ctx, auditModels.ActionLogoutAll, identity.UserID, "", err) }()
+
+	if err := uc.refreshRepo.RevokeAllForUser(ctx, identity.UserID); err != nil {
+		uc.logger.Error("failed to revoke user tokens (authUC.LogoutAll.refreshRepo.RevokeAllForUser)", "error", err)
+		return appErrors.ErrDatabase
+	}
+	if err := uc.sessionRepo.RevokeAllForUser(ctx, identity.UserID); err != nil {
+		uc.logger.Error("failed to revoke user sessions (authUC.LogoutAll.sessionRepo.RevokeAllForUser)", "error", err)
+		return appErrors.ErrDatabase
+	}
+	return nil
+}
+
+// ListSessions returns the devices the current user is logged in on.
+func (uc *AuthUsecase) ListSessions(ctx context.Context) ([]*models.LoginSession, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		uc.logger.Error("missing identity in context")
+		return nil, appErrors.ErrUnauthorized
+	}
+
+	sessions, err := uc.sessionRepo.ListActive(ctx, identity.UserID)
+	if err != nil {
+		uc.logger.Error("failed to list sessions (authUC.ListSessions.sessionRepo.ListActive)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	for _, session := range sessions {
+		session.Current = session.FamilyID == identity.FamilyID
+	}
+	return sessions, nil
+}
+
+// RevokeSession logs the current user out of one device.
+func (uc *AuthUsecase) RevokeSession(ctx context.Context, sessionID uuid.UUID) (err error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		uc.logger.Error("missing identity in context")
+		return appErrors.ErrUnauthorized
+	}
+	defer func() { uc.audit(ctx, auditModels.ActionSessionRevoke, identity.UserID, "", err) }()
+
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrLoginSessionNotFound) {
+			return domain_errors.ErrLoginSessionNotFound
+		}
+		uc.logger.Error("failed to find session (authUC.RevokeSession.sessionRepo.FindByID)", "error", err)
+		return appErrors.ErrDatabase
+	}
+	// another user's session is reported as missing so ids can't be probed
+	if session.UserID != identity.UserID || session.IsRevoked() {
+		return domain_errors.ErrLoginSessionNotFound
+	}
+
+	return uc.revokeSession(ctx, session.FamilyID)
+}
+
+// RequestAccountDeletion sends the OTP that confirms an account deletion.
+// CheckSession fails unless the login an access token was issued for is
+// still active, so logging out or revoking a session also cuts off the
+// access tokens it already handed out.
+func (uc *AuthUsecase) CheckSession(ctx context.Context, familyID uuid.UUID) error {
+	session, err := uc.sessionRepo.FindByFamily(ctx, familyID)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrLoginSessionNotFound) {
+			return appErrors.ErrUnauthorized
+		}
+		uc.logger.Error("failed to find session (authUC.CheckSession.sessionRepo.FindByFamily)", "error", err)
+		return appErrors.ErrDatabase
+	}
+	if session.IsRevoked() {
+		return domain_errors.ErrRefreshTokenRevoked
+	}
+	return nil
+}
+
+func (uc *AuthUsecase) RequestAccountDeletion(ctx context.Context) (err error) {
+	user, err := uc.currentUser(ctx, "RequestAccountDeletion")
+	if err != nil {
+		return err
+	}
+	defer func() { uc.audit(ctx, auditModels.ActionDeletionRequest, user.ID, "", err) }()
+	if user.IsPendingDeletion() {
+		return domain_errors.ErrAccountPendingDeletion
+	}
+
+	latest, err := uc.latestOTP(ctx, user.Phone)
+	if err != nil {
+		return err
+	}
+	_, err = uc.issueOTP(ctx, user.Phone, latest, "")
+	return err
+}
+
+// DeleteAccount schedules the current user's account for erasure once the
+// OTP is confirmed. Every login is revoked straight away; the data is purged
+// when the grace period ends unless the account is restored first.
+func (uc *AuthUsecase) DeleteAccount(ctx context.Context, otp string) (_ *models.AccountDeletion, err error) {
+	user, err := uc.currentUser(ctx, "DeleteAccount")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { uc.audit(ctx, auditModels.ActionAccountDelete, user.ID, "", err) }()
+	if user.IsPendingDeletion() {
+		return nil, domain_errors.ErrAccountPendingDeletion
+	}
+	if err := uc.checkOTP(ctx, user.Phone, otp); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	user.DeletionRequestedAt = now
+	user.PurgeAt = now.Add(time.Duration(uc.cfg.Account.DeletionGracePeriod) * time.Second)
+	if err := uc.userRepo.ScheduleDeletion(ctx, user); err != nil {
+		if errors.Is(err, domain_errors.ErrAccountPendingDeletion) {
+			return nil, domain_errors.ErrAccountPendingDeletion
+		}
+		uc.logger.Error("failed to schedule deletion (authUC.DeleteAccount.userRepo.ScheduleDeletion)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+
+	if err := uc.refreshRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		uc.logger.Error("failed to revoke user tokens (authUC.DeleteAccount.refreshRepo.RevokeAllForUser)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	if err := uc.sessionRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		uc.logger.Error("failed to revoke user sessions (authUC.DeleteAccount.sessionRepo.RevokeAllForUser)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	uc.logger.Info("account scheduled for deletion", "user_id", user.ID, "purge_at", user.PurgeAt)
+
+	return &models.AccountDeletion{RequestedAt: user.DeletionRequestedAt, PurgeAt: user.PurgeAt}, nil
+}
+
+// RestoreAccount cancels a pending deletion and logs the user in. Deleted
+// accounts cannot log in otherwise, so the phone and OTP are checked here.
+func (uc *AuthUsecase) RestoreAccount(ctx context.Context, phone, otp string) (_ *models.UserWithToken, err error) {
+	var userID uuid.UUID
+	defer func() { uc.audit(ctx, auditModels.ActionAccountRestore, userID, phone, err) }()
+
+	phone, err = domain_errors.NormalizeUserPhone(uc.phones, phone)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkOTP(ctx, phone, otp); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByPhone(ctx, phone)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrUserNotFound) {
+			return nil, domain_errors.ErrUserNotFound
+		}
+		uc.logger.Error("failed to find user (authUC.RestoreAccount.userRepo.FindByPhone)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	userID = user.ID
+	if !user.IsPendingDeletion() {
+		return nil, domain_errors.ErrAccountNotDeleted
+	}
+
+	if err := uc.userRepo.CancelDeletion(ctx, user); err != nil {
+		if errors.Is(err, domain_errors.ErrRestoreWindowExpired) {
+			return nil, domain_errors.ErrRestoreWindowExpired
+		}
+		uc.logger.Error("failed to cancel deletion (authUC.RestoreAccount.userRepo.CancelDeletion)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	uc.logger.Info("account restored", "user_id", user.ID)
+
+	tokens, err := uc.issueTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UserWithToken{
+		User:         user,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
 	}, nil
 }
 
-func (uc *AuthUsecase) ResendOTP(ctx context.Context, phone string) error {
-	//TODO: Implement OTP resend logic
+// PurgeDue erases one batch of accounts whose grace period has ended and
+// returns how many were purged.
+func (uc *AuthUsecase) PurgeDue(ctx context.Context) (int, error) {
+	users, err := uc.erasureRepo.ListDue(ctx, time.Now().UTC(), uc.purgeBatchSize())
+	if err != nil {
+		uc.logger.Error("failed to list accounts to purge (authUC.PurgeDue.erasureRepo.ListDue)", "error", err)
+		return 0, appErrors.ErrDatabase
+	}
+
+	purged := 0
+	for _, user := range users {
+		// voice sessions may be kept outside Postgres, so they are erased
+		// first and counted in the receipt
+		voiceSessions, err := uc.voiceRepo.DeleteUserSessions(ctx, user.ID.String())
+		if err != nil {
+			uc.logger.Error("failed to erase voice sessions (authUC.PurgeDue.voiceRepo.DeleteUserSessions)", "user_id", user.ID, "error", err)
+			return purged, appErrors.ErrInternal
+		}
+		err = uc.erasureRepo.Purge(ctx, user, uc.sealErasure(user, voiceSessions))
+		if errors.Is(err, domain_errors.ErrUserNotFound) {
+			// restored or purged since it was listed
+			continue
+		}
+		if err != nil {
+			uc.logger.Error("failed to purge account (authUC.PurgeDue.erasureRepo.Purge)", "user_id", user.ID, "error", err)
+			return purged, appErrors.ErrDatabase
+		}
+		uc.logger.Info("account purged", "user_id", user.ID)
+		uc.audit(ctx, auditModels.ActionAccountPurge, user.ID, "", nil)
+		purged++
+	}
+	return purged, nil
+}
+
+// RunPurge purges due accounts every purge interval until ctx is cancelled.
+func (uc *AuthUsecase) RunPurge(ctx context.Context) {
+	interval := time.Duration(uc.cfg.Account.PurgeInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				n, err := uc.PurgeDue(ctx)
+				if err != nil || n < uc.purgeBatchSize() {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (uc *AuthUsecase) purgeBatchSize() int {
+	if uc.cfg.Account.PurgeBatchSize <= 0 {
+		return 50
+	}
+	return uc.cfg.Account.PurgeBatchSize
+}
+
+// sealErasure signs the erasure receipt for user with the token keys,
+// adding the voice sessions erased before the purge.
+func (uc *AuthUsecase) sealErasure(user *models.User, voiceSessions int) auth.SealErasure {
+	return func(erased map[string]int64) (*models.ErasureReceipt, error) {
+		erased["voice_sessions"] += int64(voiceSessions)
+		now := time.Now().UTC()
+		receipt := &models.ErasureReceipt{
+			ID:          uuid.New(),
+			UserID:      user.ID,
+			RequestedAt: user.DeletionRequestedAt,
+			PurgedAt:    now,
+			Erased:      erased,
+		}
+		signed, err := uc.keys.Sign(&models.ErasureClaims{
+			RequestedAt: receipt.RequestedAt.Unix(),
+			Erased:      erased,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:       receipt.ID.String(),
+				Subject:  user.ID.String(),
+				IssuedAt: jwt.NewNumericDate(now),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		receipt.Receipt = signed
+		return receipt, nil
+	}
+}
+
+// currentUser loads the authenticated user for op.
+func (uc *AuthUsecase) currentUser(ctx context.Context, op string) (*models.User, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		uc.logger.Error("missing identity in context")
+		return nil, appErrors.ErrUnauthorized
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, identity.UserID)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrUserNotFound) {
+			return nil, appErrors.ErrUnauthorized
+		}
+		uc.logger.Error("failed to find user (authUC."+op+".userRepo.FindByID)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return user, nil
+}
+
+// issueTokens starts a new token family for the user, records its first
+// refresh token and registers the device as a login session.
+func (uc *AuthUsecase) issueTokens(ctx context.Context, user *models.User) (*utils.TokenPair, error) {
+	userID := user.ID
+	familyID := uuid.New()
+	pair, err := utils.GenerateTokenPair(tokenSubject(user), familyID, uc.keys, uc.cfg.JWT)
+	if err != nil {
+		uc.logger.Error("failed to generate token (authUC.issueTokens.GenerateTokenPair)", "error", err)
+		return nil, appErrors.ErrJWTGeneration
+	}
+
+	if err := uc.refreshRepo.Create(ctx, uc.newRefreshToken(ctx, userID, familyID, pair)); err != nil {
+		uc.logger.Error("failed to store refresh token (authUC.issueTokens.refreshRepo.Create)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+
+	client := utils.ClientInfoFromContext(ctx)
+	session := &models.LoginSession{
+		UserID:     userID,
+		FamilyID:   familyID,
+		DeviceID:   client.DeviceID,
+		DeviceName: client.DeviceName,
+		Platform:   client.Platform,
+		AppVersion: client.AppVersion,
+		IP:         client.IP,
+		UserAgent:  client.UserAgent,
+		LastSeenAt: time.Now().UTC(),
+	}
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		uc.logger.Error("failed to store login session (authUC.issueTokens.sessionRepo.Create)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return pair, nil
+}
+
+// tokenSubject puts the user's roles and effective permissions into the
+// access token.
+func tokenSubject(user *models.User) utils.TokenSubject {
+	subject := utils.TokenSubject{UserID: user.ID}
+	for _, role := range user.Roles {
+		subject.Roles = append(subject.Roles, string(role))
+	}
+	for _, perm := range models.EffectivePermissions(user.Roles, user.Permissions) {
+		subject.Permissions = append(subject.Permissions, string(perm))
+	}
+	return subject
+}
+
+// bootstrapAdmin grants the admin role to phones listed in the RBAC config.
+// It runs only when an existing account logs in with an OTP; a bootstrap
+// phone that registers gets the role at its next login.
+func (uc *AuthUsecase) bootstrapAdmin(ctx context.Context, user *models.User) error {
+	if user.HasRole(models.RoleAdmin) || !slices.Contains(uc.cfg.RBAC.BootstrapAdmins, user.Phone) {
+		return nil
+	}
+
+	change := &models.RoleChange{
+		UserID:         user.ID,
+		OldRoles:       user.Roles,
+		NewRoles:       append(slices.Clone(user.Roles), models.RoleAdmin),
+		OldPermissions: user.Permissions,
+		NewPermissions: user.Permissions,
+		Reason:         "bootstrap admin from config",
+	}
+	updated := *user
+	updated.Roles = change.NewRoles
+	if err := uc.userRepo.UpdateRoles(ctx, &updated, change); err != nil {
+		uc.logger.Error("failed to grant bootstrap admin (authUC.bootstrapAdmin.userRepo.UpdateRoles)", "error", err)
+		return appErrors.ErrDatabase
+	}
+	uc.logger.Info("granted admin role from config", "user_id", user.ID)
+	user.Roles = change.NewRoles
 	return nil
 }
 
+// revokeSession revokes a token family together with its login session.
+func (uc *AuthUsecase) revokeSession(ctx context.Context, familyID uuid.UUID) error {
+	if err := uc.refreshRepo.RevokeFamily(ctx, familyID); err != nil {
+		uc.logger.Error("failed to revoke token family (authUC.revokeSession.refreshRepo.RevokeFamily)", "error", err)
+		return appErrors.ErrDatabase
+	}
+	if err := uc.sessionRepo.RevokeFamily(ctx, familyID); err != nil {
+		uc.logger.Error("failed to revoke session (authUC.revokeSession.sessionRepo.RevokeFamily)", "error", err)
+		return appErrors.ErrDatabase
+	}
+	return nil
+}
+
+func (uc *AuthUsecase) newRefreshToken(ctx context.Context, userID, familyID uuid.UUID, pair *utils.TokenPair) *models.RefreshToken {
+	client := utils.ClientInfoFromContext(ctx)
+	return &models.RefreshToken{
+		ID:        pair.RefreshID,
+		UserID:    userID,
+		FamilyID:  familyID,
+		DeviceID:  client.DeviceID,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		ExpiresAt: pair.RefreshExpiresAt,
+	}
+}
+
+func (uc *AuthUsecase) revokeReusedFamily(ctx context.Context, token *models.RefreshToken) error {
+	uc.logger.Warn("refresh token reuse detected, revoking family", "user_id", token.UserID, "family_id", token.FamilyID)
+	if err := uc.revokeSession(ctx, token.FamilyID); err != nil {
+		return err
+	}
+	return domain_errors.ErrRefreshTokenReused
+}
+
 func (uc *AuthUsecase) GetUserByID(ctx context.Context, token string) (*models.User, error) {
-	claims, ok := ctx.Value("claims").(*utils.JWTClaims)
+	identity, ok := auth.FromContext(ctx)
 	if !ok {
-		uc.logger.Error("Invalid claims in context")
+		uc.logger.Error("missing identity in context")
 		return nil, appErrors.ErrUnauthorized
 	}
 
-	user, err := uc.userRepo.FindByID(ctx, claims.ID)
+	user, err := uc.userRepo.FindByID(ctx, identity.UserID)
 	if err != nil {
 		uc.logger.Error("Failed to find user (authUC.GetUserByID.FindByID)", "error", err)
 		return nil, appErrors.ErrDatabase
@@ -197,14 +877,15 @@ func (uc *AuthUsecase) GetUserByID(ctx context.Context, token string) (*models.U
 	return user, nil
 }
 
-func (uc *AuthUsecase) UpdateProfile(ctx context.Context, input *models.UpdateProfileInput) (*models.User, error) {
-	claims, ok := ctx.Value("claims").(*utils.JWTClaims)
+func (uc *AuthUsecase) UpdateProfile(ctx context.Context, input *models.UpdateProfileInput) (_ *models.User, err error) {
+	identity, ok := auth.FromContext(ctx)
 	if !ok {
-		uc.logger.Error("Invalid claims in context")
+		uc.logger.Error("missing identity in context")
 		return nil, appErrors.ErrUnauthorized
 	}
+	defer func() { uc.audit(ctx, auditModels.ActionProfileUpdate, identity.UserID, "", err) }()
 
-	user, err := uc.userRepo.FindByID(ctx, claims.ID)
+	user, err := uc.userRepo.FindByID(ctx, identity.UserID)
 	if err != nil {
 		uc.logger.Error("Failed to find user (authUC.UpdateProfile.FindByID)", "error", err)
 		return nil, appErrors.ErrDatabase
@@ -213,11 +894,14 @@ func (uc *AuthUsecase) UpdateProfile(ctx context.Context, input *models.UpdatePr
 		return nil, domain_errors.ErrUserNotFound
 	}
 
+	// a changed name drops the old Latin form unless a new one is given
 	if input.FirstName != "" {
 		user.FirstName = input.FirstName
+		user.FirstNameLatin = input.FirstNameLatin
 	}
 	if input.LastName != "" {
-		user.Language = input.LastName
+		user.LastName = input.LastName
+		user.LastNameLatin = input.LastNameLatin
 	}
 	if input.Language != "" {
 		if err := domain_errors.ValidateUserLanguage(input.Language); err != nil {
@@ -225,7 +909,7 @@ func (uc *AuthUsecase) UpdateProfile(ctx context.Context, input *models.UpdatePr
 		}
 		user.Language = input.Language
 	}
-	user.FullName = user.FirstName + " " + user.LastName
+	user.PrepareNames()
 
 	updatedUser, err := uc.userRepo.Update(ctx, user)
 	if err != nil {
@@ -235,8 +919,37 @@ func (uc *AuthUsecase) UpdateProfile(ctx context.Context, input *models.UpdatePr
 	return updatedUser, nil
 }
 
-func (uc *AuthUsecase) sendSMS(ctx context.Context, message, phone string) error {
-	//TODO: Implement OTP resend logic
+// audit records the outcome of an action on the subject's account. Phones
+// are masked, as audit events are kept after an account is erased.
+func (uc *AuthUsecase) audit(ctx context.Context, action auditModels.Action, subjectID uuid.UUID, number string, err error) {
+	event := &auditModels.Event{Action: action, Err: err}
+	if subjectID != uuid.Nil {
+		event.SubjectID = &subjectID
+	}
+	if number != "" {
+		event.Metadata = map[string]any{"phone": phone.Mask(number)}
+	}
+	uc.auditUC.Record(ctx, event)
+}
 
-	return nil
+// sendOTPMessage sends the code over channel in the user's language, or the
+// default SMS language if the phone is not registered yet.
+func (uc *AuthUsecase) sendOTPMessage(ctx context.Context, channel otpModels.Channel, phone, code string, ttl time.Duration) error {
+	language := uc.cfg.SMS.DefaultLanguage
+	user, err := uc.userRepo.FindByPhone(ctx, phone)
+	if err != nil && !errors.Is(err, domain_errors.ErrUserNotFound) {
+		uc.logger.Warn("failed to look up user language (authUC.sendOTPMessage.userRepo.FindByPhone)", "error", err)
+	}
+	if err == nil && user != nil {
+		language = user.Language
+	}
+
+	_, err = uc.otpRouter.Send(ctx, &otpModels.OTPMessage{
+		Channel:  channel,
+		To:       phone,
+		Code:     code,
+		Language: language,
+		TTL:      ttl,
+	})
+	return err
 }
@@ -2,14 +2,25 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"swasthAI/config"
+	abuseMocks "swasthAI/internal/abuse/mocks"
+	auditMocks "swasthAI/internal/audit/mocks"
+	auditModels "swasthAI/internal/audit/models"
+	"swasthAI/internal/auth"
 	mocks "swasthAI/internal/auth/mocks"
 	"swasthAI/internal/auth/models"
+	otpMocks "swasthAI/internal/otpchannel/mocks"
+	otpModels "swasthAI/internal/otpchannel/models"
+	voiceMocks "swasthAI/internal/voice/mocks"
 	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/jwtkeys"
 	"swasthAI/pkg/logger"
+	"swasthAI/pkg/phone"
 	"swasthAI/pkg/utils"
 
 	"github.com/golang/mock/gomock"
@@ -17,68 +28,358 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func setupTest(t *testing.T) (AuthUsecase, *mocks.MockUserRepository, *mocks.MockOTPRepository, *gomock.Controller) {
+type testDeps struct {
+	userRepo    *mocks.MockUserRepository
+	otpRepo     *mocks.MockOTPRepository
+	refreshRepo *mocks.MockRefreshTokenRepository
+	sessionRepo *mocks.MockLoginSessionRepository
+	erasureRepo *mocks.MockErasureRepository
+	voiceRepo   *voiceMocks.MockSessionRepository
+	otp         *otpMocks.MockOTPRouter
+	abuse       *abuseMocks.MockAbuseUsecase
+	abuseErr    error // returned by the abuse guard for every send
+	audit       *auditMocks.MockAuditUsecase
+	events      []*auditModels.Event // audit events recorded so far
+}
+
+func setupTest(t *testing.T) (AuthUsecase, *testDeps, *gomock.Controller) {
 	ctrl := gomock.NewController(t)
-	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	mockOTPRepo := mocks.NewMockOTPRepository(ctrl)
+	deps := &testDeps{
+		userRepo:    mocks.NewMockUserRepository(ctrl),
+		otpRepo:     mocks.NewMockOTPRepository(ctrl),
+		refreshRepo: mocks.NewMockRefreshTokenRepository(ctrl),
+		sessionRepo: mocks.NewMockLoginSessionRepository(ctrl),
+		erasureRepo: mocks.NewMockErasureRepository(ctrl),
+		voiceRepo:   voiceMocks.NewMockSessionRepository(ctrl),
+		otp:         otpMocks.NewMockOTPRouter(ctrl),
+		abuse:       abuseMocks.NewMockAbuseUsecase(ctrl),
+		audit:       auditMocks.NewMockAuditUsecase(ctrl),
+	}
+	deps.audit.EXPECT().Record(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, event *auditModels.Event) {
+		deps.events = append(deps.events, event)
+	}).AnyTimes()
+	deps.abuse.EXPECT().CheckOTPSend(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, phone string) error {
+		return deps.abuseErr
+	}).AnyTimes()
+	// SMS and voice are configured, WhatsApp is not
+	deps.otp.EXPECT().Default().Return(otpModels.ChannelSMS).AnyTimes()
+	deps.otp.EXPECT().Enabled(gomock.Any()).DoAndReturn(func(channel otpModels.Channel) bool {
+		return channel == otpModels.ChannelSMS || channel == otpModels.ChannelVoice
+	}).AnyTimes()
 
 	cfg := config.Config{
 		JWT: config.JWT{
-			Secret: "test-secret",
+			Secret:           "test-secret",
+			ExpiresIn:        900,
+			RefreshExpiresIn: 604800,
+		},
+		SMS:     config.SMS{DefaultLanguage: "en"},
+		Account: config.Account{DeletionGracePeriod: 86400, PurgeBatchSize: 10},
+		OTP: config.OTP{
+			Length:          6,
+			TTL:             300,
+			MaxAttempts:     3,
+			ResendCooldown:  60,
+			MaxSendsPerHour: 3,
+			LockoutDuration: 900,
+			RegisterWindow:  900,
 		},
 	}
 	log, _ := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	keys, err := jwtkeys.New(cfg.JWT)
+	assert.NoError(t, err)
+	phones, err := phone.New(config.Phone{DefaultRegion: "IN"})
+	assert.NoError(t, err)
 
-	uc := NewAuthUsecase(mockUserRepo, mockOTPRepo, cfg, *log)
-	return *uc, mockUserRepo, mockOTPRepo, ctrl
+	uc := NewAuthUsecase(deps.userRepo, deps.otpRepo, deps.refreshRepo, deps.sessionRepo, deps.erasureRepo, deps.voiceRepo, deps.otp, deps.abuse, deps.audit, keys, phones, cfg, *log)
+	return *uc, deps, ctrl
 }
 
 func TestAuthUsecase_SendOTP_Success(t *testing.T) {
-	uc, _, mockOTPRepo, ctrl := setupTest(t)
+	uc, deps, ctrl := setupTest(t)
 	defer ctrl.Finish()
 
 	ctx := context.Background()
 	phone := "+919876543210"
 
-	mockOTPRepo.EXPECT().CountRecent(ctx, phone, time.Now().UTC().Truncate(time.Second)).Return(0, nil)
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{}, domain_errors.ErrOTPNotFound)
+	deps.otpRepo.EXPECT().CountRecent(ctx, phone, gomock.Any()).Return(0, nil)
 
 	// Mock: OTP create
-	mockOTPRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	deps.otpRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+	// Registered user gets the OTP in their language
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(&models.User{Phone: phone, Language: "hi"}, nil)
+	deps.otp.EXPECT().Send(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, msg *otpModels.OTPMessage) (*otpModels.SendResult, error) {
+		assert.Equal(t, phone, msg.To)
+		assert.Equal(t, otpModels.ChannelSMS, msg.Channel)
+		assert.Equal(t, "hi", msg.Language)
+		assert.NotEmpty(t, msg.Code)
+		assert.Equal(t, 5*time.Minute, msg.TTL)
+		return &otpModels.SendResult{Channel: msg.Channel}, nil
+	})
 
-	err := uc.SendOTP(ctx, phone)
+	channel, err := uc.SendOTP(ctx, phone, "")
 	assert.NoError(t, err)
+	assert.Equal(t, otpModels.ChannelSMS, channel)
+}
+
+func TestAuthUsecase_SendOTP_NewUserDefaultLanguage(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{}, domain_errors.ErrOTPNotFound)
+	deps.otpRepo.EXPECT().CountRecent(ctx, phone, gomock.Any()).Return(0, nil)
+	deps.otpRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(nil, domain_errors.ErrUserNotFound)
+	deps.otp.EXPECT().Send(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, msg *otpModels.OTPMessage) (*otpModels.SendResult, error) {
+		assert.Equal(t, "en", msg.Language)
+		return &otpModels.SendResult{}, nil
+	})
+
+	_, err := uc.SendOTP(ctx, phone, "")
+	assert.NoError(t, err)
+}
+
+func TestAuthUsecase_SendOTP_SMSFailure(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+	providerErr := errors.New("gateway unavailable")
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{}, domain_errors.ErrOTPNotFound)
+	deps.otpRepo.EXPECT().CountRecent(ctx, phone, gomock.Any()).Return(0, nil)
+	deps.otpRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(nil, domain_errors.ErrUserNotFound)
+	deps.otp.EXPECT().Send(ctx, gomock.Any()).Return(nil, providerErr)
+
+	_, err := uc.SendOTP(ctx, phone, "")
+	assert.True(t, errors.Is(err, domain_errors.ErrFailedToSendOTP))
+	assert.True(t, errors.Is(err, providerErr))
 }
 
 func TestAuthUsecase_SendOTP_InvalidPhone(t *testing.T) {
-	uc, _, _, ctrl := setupTest(t)
+	uc, _, ctrl := setupTest(t)
 	defer ctrl.Finish()
 
-	err := uc.SendOTP(context.Background(), "invalid")
+	_, err := uc.SendOTP(context.Background(), "invalid", "")
 	assert.Error(t, err)
 	assert.Equal(t, domain_errors.ErrInvalidPhoneFormat, err)
 }
 
+func TestAuthUsecase_SendOTP_NormalizesPhone(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{}, domain_errors.ErrOTPNotFound)
+	deps.otpRepo.EXPECT().CountRecent(ctx, phone, gomock.Any()).Return(0, nil)
+	deps.otpRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, otp *models.OTP) error {
+		assert.Equal(t, phone, otp.Phone)
+		return nil
+	})
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(nil, domain_errors.ErrUserNotFound)
+	deps.otp.EXPECT().Send(ctx, gomock.Any()).Return(&otpModels.SendResult{}, nil)
+
+	_, err := uc.SendOTP(ctx, "098765-43210", "")
+	assert.NoError(t, err)
+}
+
+func TestAuthUsecase_SendOTP_RegionNotAllowed(t *testing.T) {
+	uc, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	_, err := uc.SendOTP(context.Background(), "+977 9812345678", "")
+	assert.ErrorIs(t, err, domain_errors.ErrPhoneRegionBlocked)
+}
+
 func TestAuthUsecase_SendOTP_RateLimit(t *testing.T) {
-	uc, _, mockOTPRepo, ctrl := setupTest(t)
+	uc, deps, ctrl := setupTest(t)
 	defer ctrl.Finish()
 
 	ctx := context.Background()
 	phone := "+919876543210"
 
-	mockOTPRepo.EXPECT().CountRecent(ctx, phone, time.Now().UTC().Truncate(time.Second)).Return(3, nil)
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{CreatedAt: time.Now().Add(-10 * time.Minute)}, nil)
+	deps.otpRepo.EXPECT().CountRecent(ctx, phone, gomock.Any()).Return(3, nil)
+	deps.otpRepo.EXPECT().FirstRecent(ctx, phone, gomock.Any()).Return(time.Now().Add(-50*time.Minute), nil)
 
-	err := uc.SendOTP(ctx, phone)
+	_, err := uc.SendOTP(ctx, phone, "")
 	assert.Error(t, err)
-	assert.Equal(t, domain_errors.ErrOTPAttemptsExceeded, err)
+	assert.True(t, errors.Is(err, domain_errors.ErrOTPAttemptsExceeded))
+	assert.InDelta(t, 600, err.(*appErrors.AppError).RetryAfter, 2)
 }
 
-func TestAuthUsecase_VerifyOTP_Login_Success(t *testing.T) {
-	uc, mockUserRepo, mockOTPRepo, ctrl := setupTest(t)
+func TestAuthUsecase_SendOTP_AbuseGuard(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+	deps.abuseErr = domain_errors.ErrAbuseBlocked.WithRetryAfter(time.Hour)
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{}, domain_errors.ErrOTPNotFound)
+	deps.otpRepo.EXPECT().CountRecent(ctx, phone, gomock.Any()).Return(0, nil)
+
+	// no code is stored or sent
+	_, err := uc.SendOTP(ctx, phone, "")
+	assert.ErrorIs(t, err, domain_errors.ErrAbuseBlocked)
+	assert.Equal(t, 3600, err.(*appErrors.AppError).RetryAfter)
+}
+
+func TestAuthUsecase_SendOTP_Cooldown(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{CreatedAt: time.Now().Add(-20 * time.Second)}, nil)
+
+	_, err := uc.SendOTP(ctx, phone, "")
+	assert.True(t, errors.Is(err, domain_errors.ErrResendCooldown))
+	assert.InDelta(t, 40, err.(*appErrors.AppError).RetryAfter, 2)
+}
+
+func TestAuthUsecase_SendOTP_LockedOut(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{
+		Attempts:    3,
+		CreatedAt:   time.Now().Add(-5 * time.Minute),
+		LockedUntil: time.Now().Add(10 * time.Minute),
+	}, nil)
+
+	_, err := uc.SendOTP(ctx, phone, "")
+	assert.True(t, errors.Is(err, domain_errors.ErrOTPAttemptsExceeded))
+	assert.InDelta(t, 600, err.(*appErrors.AppError).RetryAfter, 2)
+}
+
+func TestAuthUsecase_ResendOTP_Success(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{CreatedAt: time.Now().Add(-2 * time.Minute)}, nil)
+	deps.otpRepo.EXPECT().CountRecent(ctx, phone, gomock.Any()).Return(1, nil)
+	deps.otpRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, otp *models.OTP) error {
+		assert.Len(t, otp.OTP, 6)
+		return nil
+	})
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(nil, domain_errors.ErrUserNotFound)
+	deps.otp.EXPECT().Send(ctx, gomock.Any()).Return(&otpModels.SendResult{}, nil)
+
+	_, err := uc.ResendOTP(ctx, phone, "")
+	assert.NoError(t, err)
+}
+
+func TestAuthUsecase_ResendOTP_NothingToResend(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{}, domain_errors.ErrOTPNotFound)
+
+	_, err := uc.ResendOTP(ctx, phone, "")
+	assert.Equal(t, domain_errors.ErrOTPNotFound, err)
+}
+
+func TestAuthUsecase_ResendOTP_FallbackChannel(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
 	defer ctrl.Finish()
 
 	ctx := context.Background()
 	phone := "+919876543210"
+
+	// the SMS never arrived, so the user asks for a call instead
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{CreatedAt: time.Now().Add(-2 * time.Minute), Channel: otpModels.ChannelSMS}, nil)
+	deps.otpRepo.EXPECT().CountRecent(ctx, phone, gomock.Any()).Return(1, nil)
+	deps.otpRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, otp *models.OTP) error {
+		assert.Equal(t, otpModels.ChannelVoice, otp.Channel)
+		return nil
+	})
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(nil, domain_errors.ErrUserNotFound)
+	deps.otp.EXPECT().Send(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, msg *otpModels.OTPMessage) (*otpModels.SendResult, error) {
+		assert.Equal(t, otpModels.ChannelVoice, msg.Channel)
+		return &otpModels.SendResult{Channel: msg.Channel}, nil
+	})
+
+	channel, err := uc.ResendOTP(ctx, phone, otpModels.ChannelVoice)
+	assert.NoError(t, err)
+	assert.Equal(t, otpModels.ChannelVoice, channel)
+}
+
+func TestAuthUsecase_ResendOTP_KeepsPreviousChannel(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(models.OTP{CreatedAt: time.Now().Add(-2 * time.Minute), Channel: otpModels.ChannelVoice}, nil)
+	deps.otpRepo.EXPECT().CountRecent(ctx, phone, gomock.Any()).Return(1, nil)
+	deps.otpRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(nil, domain_errors.ErrUserNotFound)
+	deps.otp.EXPECT().Send(ctx, gomock.Any()).Return(&otpModels.SendResult{}, nil)
+
+	channel, err := uc.ResendOTP(ctx, phone, "")
+	assert.NoError(t, err)
+	assert.Equal(t, otpModels.ChannelVoice, channel)
+}
+
+func TestAuthUsecase_SendOTP_Channel(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	deps.otpRepo.EXPECT().FindByPhone(ctx, "+919876543210").Return(models.OTP{}, domain_errors.ErrOTPNotFound).Times(2)
+
+	_, err := uc.SendOTP(ctx, "+919876543210", "pigeon")
+	assert.Equal(t, domain_errors.ErrInvalidOTPChannel, err)
+
+	_, err = uc.SendOTP(ctx, "+919876543210", otpModels.ChannelWhatsApp)
+	assert.Equal(t, domain_errors.ErrOTPChannelUnavailable, err)
+}
+
+func pendingOTP(t *testing.T, phone, code string) models.OTP {
+	hash, err := utils.HashOTP(code)
+	assert.NoError(t, err)
+	return models.OTP{
+		ID:        uuid.New(),
+		Phone:     phone,
+		CodeHash:  hash,
+		ExpiresAt: time.Now().Add(5 * time.Minute),
+	}
+}
+
+func TestAuthUsecase_VerifyOTP_Login_Success(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := utils.WithClientInfo(context.Background(), utils.ClientInfo{
+		IP:         "10.0.0.7",
+		DeviceName: "Redmi Note 12",
+		Platform:   "android",
+		AppVersion: "1.4.0",
+	})
+	phone := "+919876543210"
 	otp := "123456"
+	otpRec := pendingOTP(t, phone, otp)
 
 	user := &models.User{
 		ID:        uuid.New(),
@@ -87,38 +388,54 @@ func TestAuthUsecase_VerifyOTP_Login_Success(t *testing.T) {
 		LastName:  "कुमार",
 	}
 
-	mockUserRepo.EXPECT().FindByPhone(ctx, phone).Return(user, nil)
-	mockOTPRepo.EXPECT().Delete(ctx, otp).Return(nil)
-
-	// Mock token generation
-	// GenerateTokens := func(u *models.User, cfg config.JWT) (string, string, error) {
-	// 	return "access-token", "refresh-token", nil
-	// }
+	gomock.InOrder(
+		deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil),
+		deps.otpRepo.EXPECT().IncrementAttempts(ctx, otpRec.ID, 3).Return(nil),
+		deps.otpRepo.EXPECT().MarkVerified(ctx, otpRec.ID).Return(nil),
+		deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(user, nil),
+		deps.refreshRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, token *models.RefreshToken) error {
+			assert.Equal(t, user.ID, token.UserID)
+			assert.NotEqual(t, uuid.Nil, token.FamilyID)
+			return nil
+		}),
+		deps.sessionRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, session *models.LoginSession) error {
+			assert.Equal(t, user.ID, session.UserID)
+			assert.Equal(t, "Redmi Note 12", session.DeviceName)
+			assert.Equal(t, "android", session.Platform)
+			assert.Equal(t, "1.4.0", session.AppVersion)
+			assert.Equal(t, "10.0.0.7", session.IP)
+			return nil
+		}),
+	)
 
 	result, registered, err := uc.VerifyOTP(ctx, phone, otp)
 	assert.NoError(t, err)
 	assert.True(t, registered)
 	assert.Equal(t, user.ID, result.User.ID)
-	assert.Equal(t, "access-token", result.Token)
+	assert.NotEmpty(t, result.Token)
+	assert.NotEmpty(t, result.RefreshToken)
+
+	if assert.Len(t, deps.events, 2) {
+		assert.Equal(t, auditModels.ActionOTPVerify, deps.events[0].Action)
+		assert.Equal(t, auditModels.ActionLogin, deps.events[1].Action)
+		assert.Equal(t, user.ID, *deps.events[1].SubjectID)
+		assert.Equal(t, "+9198******10", deps.events[1].Metadata["phone"])
+	}
 }
 
 func TestAuthUsecase_VerifyOTP_Signup_Flow(t *testing.T) {
-	uc, mockUserRepo, _, ctrl := setupTest(t)
+	uc, deps, ctrl := setupTest(t)
 	defer ctrl.Finish()
 
 	ctx := context.Background()
 	phone := "+919876543210"
 	otp := "123456"
+	otpRec := pendingOTP(t, phone, otp)
 
-	// otpRec := &models.OTP{
-	// 	Phone:     phone,
-	// 	OTP:       otp,
-	// 	ExpiresAt: time.Now().Add(5 * time.Minute),
-	// 	Attempts:  0,
-	// }
-
-	// mockOTPRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil)
-	mockUserRepo.EXPECT().FindByPhone(ctx, phone).Return((*models.User)(nil), nil)
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil)
+	deps.otpRepo.EXPECT().IncrementAttempts(ctx, otpRec.ID, 3).Return(nil)
+	deps.otpRepo.EXPECT().MarkVerified(ctx, otpRec.ID).Return(nil)
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return((*models.User)(nil), nil)
 
 	result, registered, err := uc.VerifyOTP(ctx, phone, otp)
 	assert.NoError(t, err)
@@ -126,56 +443,390 @@ func TestAuthUsecase_VerifyOTP_Signup_Flow(t *testing.T) {
 	assert.Nil(t, result)
 }
 
+func TestAuthUsecase_VerifyOTP_WrongCode(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+	otpRec := pendingOTP(t, phone, "123456")
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil)
+	deps.otpRepo.EXPECT().IncrementAttempts(ctx, otpRec.ID, 3).Return(nil)
+
+	result, registered, err := uc.VerifyOTP(ctx, phone, "654321")
+	assert.ErrorIs(t, err, domain_errors.ErrInvalidOTP)
+	assert.Equal(t, 2, err.(*appErrors.AppError).Details["retry_attempts"])
+	assert.False(t, registered)
+	assert.Nil(t, result)
+
+	if assert.Len(t, deps.events, 1) {
+		assert.Equal(t, auditModels.ActionOTPVerify, deps.events[0].Action)
+		assert.Nil(t, deps.events[0].SubjectID)
+		assert.ErrorIs(t, deps.events[0].Err, domain_errors.ErrInvalidOTP)
+	}
+}
+
+func TestAuthUsecase_VerifyOTP_LastWrongAttemptLocks(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+	otpRec := pendingOTP(t, phone, "123456")
+	otpRec.Attempts = 2
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil)
+	deps.otpRepo.EXPECT().IncrementAttempts(ctx, otpRec.ID, 3).Return(nil)
+	deps.otpRepo.EXPECT().Lock(ctx, otpRec.ID, gomock.Any()).DoAndReturn(func(ctx context.Context, id uuid.UUID, until time.Time) error {
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), until, 5*time.Second)
+		return nil
+	})
+
+	_, _, err := uc.VerifyOTP(ctx, phone, "000000")
+	assert.ErrorIs(t, err, domain_errors.ErrInvalidOTP)
+	assert.Equal(t, 0, err.(*appErrors.AppError).Details["retry_attempts"])
+}
+
+func TestAuthUsecase_VerifyOTP_Expired(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+	otpRec := pendingOTP(t, phone, "123456")
+	otpRec.ExpiresAt = time.Now().Add(-time.Minute)
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil)
+
+	_, _, err := uc.VerifyOTP(ctx, phone, "123456")
+	assert.Equal(t, domain_errors.ErrOTPExpired, err)
+}
+
+func TestAuthUsecase_VerifyOTP_AlreadyUsed(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+	otpRec := pendingOTP(t, phone, "123456")
+	otpRec.Verified = true
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil)
+
+	_, _, err := uc.VerifyOTP(ctx, phone, "123456")
+	assert.Equal(t, domain_errors.ErrInvalidOTP, err)
+}
+
+func TestAuthUsecase_VerifyOTP_AttemptsExceeded(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+	otpRec := pendingOTP(t, phone, "123456")
+	otpRec.Attempts = 3
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil)
+
+	_, _, err := uc.VerifyOTP(ctx, phone, "123456")
+	assert.Equal(t, domain_errors.ErrOTPAttemptsExceeded, err)
+}
+
 func TestAuthUsecase_RegisterUser_Success(t *testing.T) {
-	uc, mockUserRepo, _, ctrl := setupTest(t)
+	uc, deps, ctrl := setupTest(t)
 	defer ctrl.Finish()
 
 	ctx := context.Background()
 	input := &models.RegisterUserInput{
-		Phone:     "+919876543210",
+		Phone:     "98765 43210",
 		FirstName: "रमेश",
 		LastName:  "कुमार",
 		Language:  "hi",
 	}
 
-	// otpRec := &models.OTP{
-	// 	Phone:    input.Phone,
-	// 	Verified: true,
-	// }
-
-	mockUserRepo.EXPECT().FindByPhone(ctx, input.Phone).Return((*models.User)(nil), nil)
-	mockUserRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, u *models.User) (*models.User, error) {
+	deps.userRepo.EXPECT().FindByPhone(ctx, "+919876543210").Return((*models.User)(nil), nil)
+	deps.otpRepo.EXPECT().ConsumeVerified(ctx, "+919876543210", gomock.Any()).DoAndReturn(func(ctx context.Context, phone string, since time.Time) error {
+		assert.WithinDuration(t, time.Now().Add(-15*time.Minute), since, 5*time.Second)
+		return nil
+	})
+	deps.userRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, u *models.User) (*models.User, error) {
+		assert.Equal(t, "+919876543210", u.Phone)
 		u.ID = uuid.New()
 		return u, nil
 	})
-
-	// utils.GenerateTokens = func(u *models.User, cfg config.JWTConfig) (string, string, error) {
-	// 	return "access-token", "refresh-token", nil
-	// }
+	deps.refreshRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	deps.sessionRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
 
 	result, err := uc.RegisterUser(ctx, input)
 	assert.NoError(t, err)
 	assert.Equal(t, input.FirstName, result.User.FirstName)
-	assert.Equal(t, "access-token", result.Token)
+	assert.NotEmpty(t, result.Token)
+	assert.NotEmpty(t, result.RefreshToken)
+	assert.NotEqual(t, result.Token, result.RefreshToken)
+}
+
+func TestAuthUsecase_RegisterUser_NoBootstrapAdmin(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+	uc.cfg.RBAC.BootstrapAdmins = []string{"+919800000001"}
+
+	ctx := context.Background()
+	input := &models.RegisterUserInput{
+		Phone:     "+919800000001",
+		FirstName: "Anita",
+		LastName:  "Rao",
+		Language:  "en",
+	}
+
+	deps.userRepo.EXPECT().FindByPhone(ctx, input.Phone).Return((*models.User)(nil), nil)
+	deps.otpRepo.EXPECT().ConsumeVerified(ctx, input.Phone, gomock.Any()).Return(nil)
+	deps.userRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, u *models.User) (*models.User, error) {
+		u.ID = uuid.New()
+		return u, nil
+	})
+	deps.refreshRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	deps.sessionRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+	result, err := uc.RegisterUser(ctx, input)
+	assert.NoError(t, err)
+	assert.False(t, result.User.HasRole(models.RoleAdmin))
+}
+
+func TestAuthUsecase_VerifyOTP_BootstrapAdmin(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+	uc.cfg.RBAC.BootstrapAdmins = []string{"+919800000001"}
+
+	ctx := context.Background()
+	phone := "+919800000001"
+	otpRec := pendingOTP(t, phone, "123456")
+	user := &models.User{ID: uuid.New(), Phone: phone, FirstName: "Anita", LastName: "Rao", Roles: []models.Role{models.RolePatient}}
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil)
+	deps.otpRepo.EXPECT().IncrementAttempts(ctx, otpRec.ID, 3).Return(nil)
+	deps.otpRepo.EXPECT().MarkVerified(ctx, otpRec.ID).Return(nil)
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(user, nil)
+	deps.userRepo.EXPECT().UpdateRoles(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, u *models.User, change *models.RoleChange) error {
+		assert.Equal(t, []models.Role{models.RolePatient, models.RoleAdmin}, u.Roles)
+		assert.Equal(t, []models.Role{models.RolePatient}, change.OldRoles)
+		assert.Equal(t, uuid.Nil, change.ChangedBy)
+		return nil
+	})
+	deps.refreshRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	deps.sessionRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+	result, _, err := uc.VerifyOTP(ctx, phone, "123456")
+	assert.NoError(t, err)
+	assert.True(t, result.User.HasRole(models.RoleAdmin))
+
+	claims := &utils.JWTClaims{}
+	_, err = uc.keys.Parse(result.Token, claims)
+	assert.NoError(t, err)
+	assert.Contains(t, claims.Permissions, string(models.PermRoleAssign))
+}
+
+func TestAuthUsecase_RegisterUser_OTPNotVerified(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	input := &models.RegisterUserInput{Phone: "+919876543210", FirstName: "Ramesh", LastName: "Kumar", Language: "hi"}
+
+	deps.userRepo.EXPECT().FindByPhone(ctx, input.Phone).Return((*models.User)(nil), nil)
+	deps.otpRepo.EXPECT().ConsumeVerified(ctx, input.Phone, gomock.Any()).Return(domain_errors.ErrPhoneNotVerified)
+
+	_, err := uc.RegisterUser(ctx, input)
+	assert.Equal(t, domain_errors.ErrPhoneNotVerified, err)
+}
+
+func issueRefreshToken(t *testing.T, uc AuthUsecase) (*utils.TokenPair, *models.RefreshToken) {
+	t.Helper()
+	userID, familyID := uuid.New(), uuid.New()
+	pair, err := utils.GenerateTokenPair(utils.TokenSubject{UserID: userID}, familyID, uc.keys, uc.cfg.JWT)
+	assert.NoError(t, err)
+	return pair, &models.RefreshToken{
+		ID:        pair.RefreshID,
+		UserID:    userID,
+		FamilyID:  familyID,
+		ExpiresAt: pair.RefreshExpiresAt,
+	}
+}
+
+func TestAuthUsecase_RefreshToken_Rotates(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	pair, stored := issueRefreshToken(t, uc)
+
+	deps.refreshRepo.EXPECT().FindByID(ctx, stored.ID).Return(stored, nil)
+	deps.userRepo.EXPECT().FindByID(ctx, stored.UserID).Return(&models.User{ID: stored.UserID, Roles: []models.Role{models.RoleDoctor}}, nil)
+	deps.refreshRepo.EXPECT().Rotate(ctx, stored.ID, gomock.Any()).DoAndReturn(func(ctx context.Context, oldID uuid.UUID, next *models.RefreshToken) error {
+		assert.NotEqual(t, stored.ID, next.ID)
+		assert.Equal(t, stored.FamilyID, next.FamilyID)
+		assert.Equal(t, stored.UserID, next.UserID)
+		return nil
+	})
+	deps.sessionRepo.EXPECT().Touch(ctx, stored.FamilyID, "", gomock.Any()).Return(nil)
+
+	tokens, err := uc.RefreshToken(ctx, pair.RefreshToken)
+	assert.NoError(t, err)
+	assert.NotEqual(t, pair.RefreshToken, tokens.RefreshToken)
+	assert.Equal(t, 900, tokens.ExpiresIn)
+
+	// the new access token carries the user's current roles
+	claims := &utils.JWTClaims{}
+	_, err = uc.keys.Parse(tokens.Token, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"doctor"}, claims.Roles)
+	assert.Contains(t, claims.Permissions, "consultation:read")
+}
+
+func TestAuthUsecase_RefreshToken_ReuseRevokesFamily(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	pair, stored := issueRefreshToken(t, uc)
+	stored.RotatedAt = time.Now().Add(-time.Minute)
+
+	deps.refreshRepo.EXPECT().FindByID(ctx, stored.ID).Return(stored, nil)
+	deps.refreshRepo.EXPECT().RevokeFamily(ctx, stored.FamilyID).Return(nil)
+	deps.sessionRepo.EXPECT().RevokeFamily(ctx, stored.FamilyID).Return(nil)
+
+	_, err := uc.RefreshToken(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, domain_errors.ErrRefreshTokenReused)
+}
+
+func TestAuthUsecase_RefreshToken_ConcurrentRotationRevokesFamily(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	pair, stored := issueRefreshToken(t, uc)
+
+	deps.refreshRepo.EXPECT().FindByID(ctx, stored.ID).Return(stored, nil)
+	deps.userRepo.EXPECT().FindByID(ctx, stored.UserID).Return(&models.User{ID: stored.UserID}, nil)
+	deps.refreshRepo.EXPECT().Rotate(ctx, stored.ID, gomock.Any()).Return(domain_errors.ErrRefreshTokenReused)
+	deps.refreshRepo.EXPECT().RevokeFamily(ctx, stored.FamilyID).Return(nil)
+	deps.sessionRepo.EXPECT().RevokeFamily(ctx, stored.FamilyID).Return(nil)
+
+	_, err := uc.RefreshToken(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, domain_errors.ErrRefreshTokenReused)
+}
+
+func TestAuthUsecase_RefreshToken_Revoked(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	pair, stored := issueRefreshToken(t, uc)
+	stored.RevokedAt = time.Now().Add(-time.Minute)
+
+	deps.refreshRepo.EXPECT().FindByID(ctx, stored.ID).Return(stored, nil)
+
+	_, err := uc.RefreshToken(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, domain_errors.ErrRefreshTokenRevoked)
+}
+
+func TestAuthUsecase_RefreshToken_RejectsAccessToken(t *testing.T) {
+	uc, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	pair, _ := issueRefreshToken(t, uc)
+
+	_, err := uc.RefreshToken(context.Background(), pair.AccessToken)
+	assert.ErrorIs(t, err, appErrors.ErrUnauthorized)
+}
+
+func TestAuthUsecase_Logout(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	identity := &auth.Identity{UserID: uuid.New(), FamilyID: uuid.New()}
+	ctx := auth.WithIdentity(context.Background(), identity)
+
+	deps.refreshRepo.EXPECT().RevokeFamily(ctx, identity.FamilyID).Return(nil)
+	deps.sessionRepo.EXPECT().RevokeFamily(ctx, identity.FamilyID).Return(nil)
+	assert.NoError(t, uc.Logout(ctx))
+
+	deps.refreshRepo.EXPECT().RevokeAllForUser(ctx, identity.UserID).Return(nil)
+	deps.sessionRepo.EXPECT().RevokeAllForUser(ctx, identity.UserID).Return(nil)
+	assert.NoError(t, uc.LogoutAll(ctx))
 }
 
-// func TestAuthUsecase_RegisterUser_OTPNotVerified(t *testing.T) {
-// 	uc, _, _, ctrl := setupTest(t)
-// 	defer ctrl.Finish()
+func TestAuthUsecase_ListSessions_MarksCurrent(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	identity := &auth.Identity{UserID: uuid.New(), FamilyID: uuid.New()}
+	ctx := auth.WithIdentity(context.Background(), identity)
 
-// 	input := &models.RegisterUserInput{Phone: "+919876543210"}
+	deps.sessionRepo.EXPECT().ListActive(ctx, identity.UserID).Return([]*models.LoginSession{
+		{ID: uuid.New(), UserID: identity.UserID, FamilyID: uuid.New(), DeviceName: "Old phone"},
+		{ID: uuid.New(), UserID: identity.UserID, FamilyID: identity.FamilyID, DeviceName: "This phone"},
+	}, nil)
 
-// 	_, err := uc.RegisterUser(context.Background(), input)
-// 	assert.Error(t, err)
-// 	assert.Equal(t, domain_errors.ErrInvalidOTP, err)
-// }
+	sessions, err := uc.ListSessions(ctx)
+	assert.NoError(t, err)
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+}
+
+func TestAuthUsecase_RevokeSession(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	identity := &auth.Identity{UserID: uuid.New(), FamilyID: uuid.New()}
+	ctx := auth.WithIdentity(context.Background(), identity)
+	session := &models.LoginSession{ID: uuid.New(), UserID: identity.UserID, FamilyID: uuid.New()}
+
+	deps.sessionRepo.EXPECT().FindByID(ctx, session.ID).Return(session, nil)
+	deps.refreshRepo.EXPECT().RevokeFamily(ctx, session.FamilyID).Return(nil)
+	deps.sessionRepo.EXPECT().RevokeFamily(ctx, session.FamilyID).Return(nil)
+
+	assert.NoError(t, uc.RevokeSession(ctx, session.ID))
+}
+
+func TestAuthUsecase_RevokeSession_OtherUser(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	identity := &auth.Identity{UserID: uuid.New(), FamilyID: uuid.New()}
+	ctx := auth.WithIdentity(context.Background(), identity)
+	session := &models.LoginSession{ID: uuid.New(), UserID: uuid.New(), FamilyID: uuid.New()}
+
+	deps.sessionRepo.EXPECT().FindByID(ctx, session.ID).Return(session, nil)
+
+	err := uc.RevokeSession(ctx, session.ID)
+	assert.ErrorIs(t, err, domain_errors.ErrLoginSessionNotFound)
+}
+
+func TestAuthUsecase_CheckSession(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	active := &models.LoginSession{ID: uuid.New(), FamilyID: uuid.New()}
+	revoked := &models.LoginSession{ID: uuid.New(), FamilyID: uuid.New(), RevokedAt: time.Now()}
+	purged := uuid.New()
+
+	deps.sessionRepo.EXPECT().FindByFamily(ctx, active.FamilyID).Return(active, nil)
+	deps.sessionRepo.EXPECT().FindByFamily(ctx, revoked.FamilyID).Return(revoked, nil)
+	deps.sessionRepo.EXPECT().FindByFamily(ctx, purged).Return(nil, domain_errors.ErrLoginSessionNotFound)
+
+	assert.NoError(t, uc.CheckSession(ctx, active.FamilyID))
+	assert.Equal(t, domain_errors.ErrRefreshTokenRevoked, uc.CheckSession(ctx, revoked.FamilyID))
+	assert.Equal(t, appErrors.ErrUnauthorized, uc.CheckSession(ctx, purged))
+}
 
 func TestAuthUsecase_UpdateProfile_Success(t *testing.T) {
-	uc, mockUserRepo, _, ctrl := setupTest(t)
+	uc, deps, ctrl := setupTest(t)
 	defer ctrl.Finish()
 
-	ctx := context.WithValue(context.Background(), "claims", &utils.JWTClaims{ID: uuid.New()})
-	userID := ctx.Value("claims").(*utils.JWTClaims).ID
+	userID := uuid.New()
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{UserID: userID})
 
 	existing := &models.User{
 		ID:        uuid.New(),
@@ -189,11 +840,12 @@ func TestAuthUsecase_UpdateProfile_Success(t *testing.T) {
 		Language:  "ta",
 	}
 
-	mockUserRepo.EXPECT().FindByID(ctx, userID).Return(existing, nil)
-	mockUserRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, u *models.User) (*models.User, error) {
+	deps.userRepo.EXPECT().FindByID(ctx, userID).Return(existing, nil)
+	deps.userRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, u *models.User) (*models.User, error) {
 		assert.Equal(t, "राम", u.FirstName)
 		assert.Equal(t, "ta", u.Language)
 		assert.Equal(t, "राम कुमार", u.FullName)
+		assert.Equal(t, "Ram Kumar", u.FullNameLatin)
 		return u, nil
 	})
 
@@ -202,3 +854,167 @@ func TestAuthUsecase_UpdateProfile_Success(t *testing.T) {
 	assert.Equal(t, "राम", updated.FirstName)
 	assert.Equal(t, "ta", updated.Language)
 }
+
+func TestAuthUsecase_UpdateProfile_LastNameWithLatin(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{UserID: userID})
+
+	existing := &models.User{ID: userID, FirstName: "முருகன்", LastName: "பிள்ளை", Language: "ta"}
+	existing.PrepareNames()
+
+	deps.userRepo.EXPECT().FindByID(ctx, userID).Return(existing, nil)
+	deps.userRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, u *models.User) (*models.User, error) {
+		assert.Equal(t, "செல்வம்", u.LastName)
+		assert.Equal(t, "ta", u.Language)
+		assert.Equal(t, "Murugan Selvam", u.FullNameLatin)
+		return u, nil
+	})
+
+	_, err := uc.UpdateProfile(ctx, &models.UpdateProfileInput{
+		FirstName:      "முருகன்",
+		FirstNameLatin: "Murugan",
+		LastName:       "செல்வம்",
+		LastNameLatin:  "Selvam",
+		Language:       "ta",
+	})
+	assert.NoError(t, err)
+}
+
+func deletingContext(user *models.User) context.Context {
+	return auth.WithIdentity(context.Background(), &auth.Identity{UserID: user.ID, FamilyID: uuid.New()})
+}
+
+func TestAuthUsecase_DeleteAccount_SchedulesPurge(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	user := &models.User{ID: uuid.New(), Phone: "+919876543210"}
+	ctx := deletingContext(user)
+	otpRec := pendingOTP(t, user.Phone, "123456")
+
+	gomock.InOrder(
+		deps.userRepo.EXPECT().FindByID(ctx, user.ID).Return(user, nil),
+		deps.otpRepo.EXPECT().FindByPhone(ctx, user.Phone).Return(otpRec, nil),
+		deps.otpRepo.EXPECT().IncrementAttempts(ctx, otpRec.ID, 3).Return(nil),
+		deps.otpRepo.EXPECT().MarkVerified(ctx, otpRec.ID).Return(nil),
+		deps.userRepo.EXPECT().ScheduleDeletion(ctx, user).Return(nil),
+		deps.refreshRepo.EXPECT().RevokeAllForUser(ctx, user.ID).Return(nil),
+		deps.sessionRepo.EXPECT().RevokeAllForUser(ctx, user.ID).Return(nil),
+	)
+
+	deletion, err := uc.DeleteAccount(ctx, "123456")
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, deletion.PurgeAt.Sub(deletion.RequestedAt))
+	assert.True(t, user.IsPendingDeletion())
+}
+
+func TestAuthUsecase_DeleteAccount_WrongOTP(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	user := &models.User{ID: uuid.New(), Phone: "+919876543210"}
+	ctx := deletingContext(user)
+	otpRec := pendingOTP(t, user.Phone, "123456")
+
+	deps.userRepo.EXPECT().FindByID(ctx, user.ID).Return(user, nil)
+	deps.otpRepo.EXPECT().FindByPhone(ctx, user.Phone).Return(otpRec, nil)
+	deps.otpRepo.EXPECT().IncrementAttempts(ctx, otpRec.ID, 3).Return(nil)
+
+	_, err := uc.DeleteAccount(ctx, "654321")
+	assert.ErrorIs(t, err, domain_errors.ErrInvalidOTP)
+}
+
+func TestAuthUsecase_VerifyOTP_PendingDeletion(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+	otpRec := pendingOTP(t, phone, "123456")
+	user := &models.User{ID: uuid.New(), Phone: phone, PurgeAt: time.Now().Add(time.Hour)}
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil)
+	deps.otpRepo.EXPECT().IncrementAttempts(ctx, otpRec.ID, 3).Return(nil)
+	deps.otpRepo.EXPECT().MarkVerified(ctx, otpRec.ID).Return(nil)
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(user, nil)
+
+	_, _, err := uc.VerifyOTP(ctx, phone, "123456")
+	assert.ErrorIs(t, err, domain_errors.ErrAccountPendingDeletion)
+}
+
+func TestAuthUsecase_RestoreAccount(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+	otpRec := pendingOTP(t, phone, "123456")
+	user := &models.User{ID: uuid.New(), Phone: phone, DeletionRequestedAt: time.Now().Add(-time.Hour), PurgeAt: time.Now().Add(time.Hour)}
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil)
+	deps.otpRepo.EXPECT().IncrementAttempts(ctx, otpRec.ID, 3).Return(nil)
+	deps.otpRepo.EXPECT().MarkVerified(ctx, otpRec.ID).Return(nil)
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(user, nil)
+	deps.userRepo.EXPECT().CancelDeletion(ctx, user).Return(nil)
+	deps.refreshRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	deps.sessionRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+	result, err := uc.RestoreAccount(ctx, phone, "123456")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Token)
+}
+
+func TestAuthUsecase_RestoreAccount_WindowExpired(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	phone := "+919876543210"
+	otpRec := pendingOTP(t, phone, "123456")
+	user := &models.User{ID: uuid.New(), Phone: phone, PurgeAt: time.Now().Add(-time.Minute)}
+
+	deps.otpRepo.EXPECT().FindByPhone(ctx, phone).Return(otpRec, nil)
+	deps.otpRepo.EXPECT().IncrementAttempts(ctx, otpRec.ID, 3).Return(nil)
+	deps.otpRepo.EXPECT().MarkVerified(ctx, otpRec.ID).Return(nil)
+	deps.userRepo.EXPECT().FindByPhone(ctx, phone).Return(user, nil)
+	deps.userRepo.EXPECT().CancelDeletion(ctx, user).Return(domain_errors.ErrRestoreWindowExpired)
+
+	_, err := uc.RestoreAccount(ctx, phone, "123456")
+	assert.ErrorIs(t, err, domain_errors.ErrRestoreWindowExpired)
+}
+
+func TestAuthUsecase_PurgeDue_SignsReceipt(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	requestedAt := time.Now().Add(-25 * time.Hour).UTC()
+	due := &models.User{ID: uuid.New(), Phone: "+919876543210", DeletionRequestedAt: requestedAt, PurgeAt: requestedAt.Add(24 * time.Hour)}
+	restored := &models.User{ID: uuid.New(), Phone: "+919876543211", PurgeAt: requestedAt}
+
+	deps.erasureRepo.EXPECT().ListDue(ctx, gomock.Any(), 10).Return([]*models.User{due, restored}, nil)
+	deps.voiceRepo.EXPECT().DeleteUserSessions(ctx, due.ID.String()).Return(3, nil)
+	deps.voiceRepo.EXPECT().DeleteUserSessions(ctx, restored.ID.String()).Return(0, nil)
+	deps.erasureRepo.EXPECT().Purge(ctx, due, gomock.Any()).DoAndReturn(func(ctx context.Context, user *models.User, seal auth.SealErasure) error {
+		receipt, err := seal(map[string]int64{"users": 1, "otps": 2})
+		assert.NoError(t, err)
+		assert.Equal(t, due.ID, receipt.UserID)
+
+		claims := &models.ErasureClaims{}
+		_, err = uc.keys.Parse(receipt.Receipt, claims)
+		assert.NoError(t, err)
+		assert.Equal(t, due.ID.String(), claims.Subject)
+		assert.Equal(t, requestedAt.Unix(), claims.RequestedAt)
+		assert.Equal(t, int64(2), claims.Erased["otps"])
+		assert.Equal(t, int64(3), claims.Erased["voice_sessions"])
+		return nil
+	})
+	deps.erasureRepo.EXPECT().Purge(ctx, restored, gomock.Any()).Return(domain_errors.ErrUserNotFound)
+
+	n, err := uc.PurgeDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
@@ -12,4 +12,9 @@ type UserRepository interface {
 	FindByPhone(ctx context.Context, phone string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) (*models.User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	UpdateRoles(ctx context.Context, user *models.User, change *models.RoleChange) error
+	ListByRole(ctx context.Context, role models.Role, limit, offset int) ([]*models.User, error)
+	ListRoleChanges(ctx context.Context, userID uuid.UUID) ([]*models.RoleChange, error)
+	ScheduleDeletion(ctx context.Context, user *models.User) error
+	CancelDeletion(ctx context.Context, user *models.User) error
 }
@@ -0,0 +1,14 @@
+package consent
+
+import (
+	"context"
+	"swasthAI/internal/consent/models"
+
+	"github.com/google/uuid"
+)
+
+type ConsentRepository interface {
+	Create(ctx context.Context, record *models.ConsentRecord) error
+	ListLatest(ctx context.Context, subjectID uuid.UUID) ([]*models.ConsentRecord, error)
+	ListHistory(ctx context.Context, subjectID uuid.UUID) ([]*models.ConsentRecord, error)
+}
@@ -0,0 +1,15 @@
+package consent
+
+import (
+	"context"
+	"swasthAI/internal/consent/models"
+
+	"github.com/google/uuid"
+)
+
+type ConsentUsecase interface {
+	List(ctx context.Context, language string) ([]*models.ConsentStatus, error)
+	Record(ctx context.Context, input *models.ConsentInput) (*models.ConsentRecord, error)
+	History(ctx context.Context) ([]*models.ConsentRecord, error)
+	Require(ctx context.Context, subjectID uuid.UUID, purposes ...models.Purpose) error
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"net/http"
+
+	"swasthAI/config"
+	"swasthAI/internal/consent"
+	"swasthAI/internal/consent/models"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/http_errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	uc     consent.ConsentUsecase
+	logger *logger.Logger
+	Cfg    *config.Config
+}
+
+func NewHandler(uc consent.ConsentUsecase, logger *logger.Logger, cfg *config.Config) *Handler {
+	return &Handler{uc: uc, logger: logger, Cfg: cfg}
+}
+
+func (h *Handler) ListConsents(c echo.Context) error {
+	statuses, err := h.uc.List(c.Request().Context(), c.QueryParam("language"))
+	if err != nil {
+		h.logger.Error("failed to list consents", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"consents": statuses,
+	})
+}
+
+func (h *Handler) RecordConsent(c echo.Context) error {
+	var input models.ConsentInput
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error("failed to read input", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+
+	record, err := h.uc.Record(c.Request().Context(), &input)
+	if err != nil {
+		h.logger.Error("failed to record consent", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusCreated, record)
+}
+
+func (h *Handler) ConsentHistory(c echo.Context) error {
+	records, err := h.uc.History(c.Request().Context())
+	if err != nil {
+		h.logger.Error("failed to list consent history", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"history": records,
+	})
+}
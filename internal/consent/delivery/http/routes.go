@@ -0,0 +1,15 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"swasthAI/internal/middleware"
+)
+
+func (h *Handler) MapConsentRoutes(consents *echo.Group, mw middleware.MiddlewareManager) {
+	// X-Acting-For manages a patient's consents instead of the caller's
+	consents.Use(mw.RequireAuth, mw.ActingFor)
+	consents.GET("", h.ListConsents)
+	consents.POST("", h.RecordConsent)
+	consents.GET("/history", h.ConsentHistory)
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Purpose is something the user must explicitly agree to before we do it.
+type Purpose string
+
+const (
+	PurposeVoiceAI       Purpose = "ai_voice"       // send voice audio to AI models
+	PurposeImageAI       Purpose = "ai_images"      // send medical images and reports to AI models
+	PurposeDoctorSharing Purpose = "doctor_sharing" // share health data with consulted doctors
+)
+
+// AllPurposes lists every purpose, in the order consents are shown.
+var AllPurposes = []Purpose{PurposeVoiceAI, PurposeImageAI, PurposeDoctorSharing}
+
+func (p Purpose) IsValid() bool {
+	for _, known := range AllPurposes {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ConsentText is the wording a user agrees to. The version is per purpose,
+// so every translation of a version says the same thing.
+type ConsentText struct {
+	Purpose  Purpose `json:"purpose"`
+	Version  int     `json:"version"`
+	Language string  `json:"language"`
+	Title    string  `json:"title"`
+	Body     string  `json:"body"`
+}
+
+type ConsentAction string
+
+const (
+	ConsentGranted   ConsentAction = "granted"
+	ConsentWithdrawn ConsentAction = "withdrawn"
+)
+
+// ConsentRecord is one entry in the consent ledger. Records are only ever
+// appended; the latest per purpose is the subject's current decision. The
+// subject is a user or a managed patient, recorded by whoever acted for them.
+type ConsentRecord struct {
+	bun.BaseModel `bun:"table:consent_records"`
+
+	ID         uuid.UUID     `bun:",pk,type:uuid,default:gen_random_uuid()" json:"id"`
+	SubjectID  uuid.UUID     `bun:",notnull,type:uuid" json:"subject_id"`
+	RecordedBy uuid.UUID     `bun:",notnull,type:uuid" json:"recorded_by"`
+	Purpose    Purpose       `bun:",notnull" json:"purpose"`
+	Version    int           `bun:",notnull" json:"version"`
+	Language   string        `bun:",notnull" json:"language"` // language of the text shown
+	Action     ConsentAction `bun:",notnull" json:"action"`
+	IP         string        `bun:",nullzero" json:"-"`
+	UserAgent  string        `bun:",nullzero" json:"-"`
+	CreatedAt  time.Time     `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// ConsentStatus is the subject's current decision for a purpose together
+// with the text to show them.
+type ConsentStatus struct {
+	Purpose        Purpose      `json:"purpose"`
+	Granted        bool         `json:"granted"`
+	GrantedVersion int          `json:"granted_version,omitempty"`
+	UpdatedAt      *time.Time   `json:"updated_at,omitempty"`
+	Text           *ConsentText `json:"text"`
+}
+
+type ConsentInput struct {
+	Purpose  Purpose `json:"purpose" validate:"required"`
+	Version  int     `json:"version" validate:"required,gt=0"`
+	Language string  `json:"language" validate:"required,alpha,len=2"`
+	Granted  bool    `json:"granted"`
+}
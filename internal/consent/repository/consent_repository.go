@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"swasthAI/internal/consent/models"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type ConsentRepository struct {
+	db *bun.DB
+}
+
+func NewConsentRepository(db *bun.DB) *ConsentRepository {
+	return &ConsentRepository{db: db}
+}
+
+func (r *ConsentRepository) Create(ctx context.Context, record *models.ConsentRecord) error {
+	_, err := r.db.NewInsert().Model(record).Returning("*").Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "consentRepo.Create.Insert")
+	}
+	return nil
+}
+
+// ListLatest returns the subject's most recent record for each purpose.
+func (r *ConsentRepository) ListLatest(ctx context.Context, subjectID uuid.UUID) ([]*models.ConsentRecord, error) {
+	var records []*models.ConsentRecord
+	err := r.db.NewSelect().
+		Model(&records).
+		DistinctOn("purpose").
+		Where("subject_id = ?", subjectID).
+		Order("purpose", "created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "consentRepo.ListLatest.Select")
+	}
+	return records, nil
+}
+
+// ListHistory returns every record of the subject, newest first.
+func (r *ConsentRepository) ListHistory(ctx context.Context, subjectID uuid.UUID) ([]*models.ConsentRecord, error) {
+	var records []*models.ConsentRecord
+	err := r.db.NewSelect().
+		Model(&records).
+		Where("subject_id = ?", subjectID).
+		Order("created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "consentRepo.ListHistory.Select")
+	}
+	return records, nil
+}
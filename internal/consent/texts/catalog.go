@@ -0,0 +1,26 @@
+package texts
+
+import "swasthAI/internal/consent/models"
+
+// catalog holds every consent text ever published. Published texts must not
+// be edited: users agreed to them word for word. To change one, add a new
+// version; users have to consent again before it takes effect.
+var catalog = []models.ConsentText{
+	// Voice audio to AI models
+	{Purpose: models.PurposeVoiceAI, Version: 1, Language: "en", Title: "Voice assistant",
+		Body: "Your voice recordings are sent to our AI models to understand your question and answer it. Recordings are not used for anything else and are deleted with your account. You can withdraw this consent at any time; the voice assistant will then stop working."},
+	{Purpose: models.PurposeVoiceAI, Version: 1, Language: "hi", Title: "वॉइस सहायक",
+		Body: "आपका प्रश्न समझने और उसका उत्तर देने के लिए आपकी आवाज़ की रिकॉर्डिंग हमारे AI मॉडल को भेजी जाती है। रिकॉर्डिंग का किसी और काम में उपयोग नहीं होता और खाता हटाने पर वे भी हटा दी जाती हैं। आप यह सहमति कभी भी वापस ले सकते हैं; तब वॉइस सहायक काम करना बंद कर देगा।"},
+
+	// Medical images to AI models
+	{Purpose: models.PurposeImageAI, Version: 1, Language: "en", Title: "Report and image analysis",
+		Body: "X-rays, photos and reports you upload are sent to our AI models for analysis. The results are guidance only and do not replace a doctor. Uploads are deleted with your account. You can withdraw this consent at any time."},
+	{Purpose: models.PurposeImageAI, Version: 1, Language: "hi", Title: "रिपोर्ट और चित्र विश्लेषण",
+		Body: "आपके द्वारा अपलोड किए गए एक्स-रे, फ़ोटो और रिपोर्ट विश्लेषण के लिए हमारे AI मॉडल को भेजे जाते हैं। परिणाम केवल मार्गदर्शन हैं और डॉक्टर का स्थान नहीं लेते। खाता हटाने पर अपलोड भी हटा दिए जाते हैं। आप यह सहमति कभी भी वापस ले सकते हैं।"},
+
+	// Sharing with doctors
+	{Purpose: models.PurposeDoctorSharing, Version: 1, Language: "en", Title: "Sharing with doctors",
+		Body: "When you book a consultation, the doctor can see your profile, past conversations and reports. Only doctors you consult get access. You can withdraw this consent at any time; doctors will then see only what you tell them."},
+	{Purpose: models.PurposeDoctorSharing, Version: 1, Language: "hi", Title: "डॉक्टरों के साथ साझा करना",
+		Body: "परामर्श बुक करने पर डॉक्टर आपकी प्रोफ़ाइल, पिछली बातचीत और रिपोर्ट देख सकते हैं। केवल वही डॉक्टर देख सकते हैं जिनसे आप परामर्श लेते हैं। आप यह सहमति कभी भी वापस ले सकते हैं; तब डॉक्टर केवल वही देखेंगे जो आप उन्हें बताएँगे।"},
+}
@@ -0,0 +1,61 @@
+package texts
+
+import (
+	"fmt"
+
+	"swasthAI/internal/consent/models"
+)
+
+// fallbackLanguage must have a text for every version of every purpose.
+const fallbackLanguage = "en"
+
+type key struct {
+	purpose  models.Purpose
+	version  int
+	language string
+}
+
+// Registry resolves consent texts by purpose, version and language.
+type Registry struct {
+	texts  map[key]models.ConsentText
+	latest map[models.Purpose]int
+}
+
+// NewRegistry loads the built-in catalog.
+func NewRegistry() (*Registry, error) {
+	r := &Registry{texts: make(map[key]models.ConsentText, len(catalog)), latest: make(map[models.Purpose]int)}
+	for _, t := range catalog {
+		r.texts[key{t.Purpose, t.Version, t.Language}] = t
+		if t.Version > r.latest[t.Purpose] {
+			r.latest[t.Purpose] = t.Version
+		}
+	}
+
+	for _, purpose := range models.AllPurposes {
+		latest, ok := r.latest[purpose]
+		if !ok {
+			return nil, fmt.Errorf("consent texts: no text for %s", purpose)
+		}
+		for version := 1; version <= latest; version++ {
+			if _, ok := r.texts[key{purpose, version, fallbackLanguage}]; !ok {
+				return nil, fmt.Errorf("consent texts: no %s text for %s version %d", fallbackLanguage, purpose, version)
+			}
+		}
+	}
+	return r, nil
+}
+
+// Latest returns the version users must have agreed to for purpose.
+func (r *Registry) Latest(purpose models.Purpose) int {
+	return r.latest[purpose]
+}
+
+// Get returns a version of the text for purpose in language, falling back to
+// English when it has not been translated.
+func (r *Registry) Get(purpose models.Purpose, version int, language string) (models.ConsentText, bool) {
+	if t, ok := r.texts[key{purpose, version, language}]; ok {
+		return t, true
+	}
+	t, ok := r.texts[key{purpose, version, fallbackLanguage}]
+	return t, ok
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+
+	"swasthAI/internal/consent"
+	"swasthAI/internal/consent/models"
+	"swasthAI/internal/consent/texts"
+	"swasthAI/internal/patient"
+	patientModels "swasthAI/internal/patient/models"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type ConsentUsecase struct {
+	consentRepo consent.ConsentRepository
+	texts       *texts.Registry
+	logger      logger.Logger
+}
+
+func NewConsentUsecase(consentRepo consent.ConsentRepository, texts *texts.Registry, logger logger.Logger) *ConsentUsecase {
+	return &ConsentUsecase{consentRepo: consentRepo, texts: texts, logger: logger}
+}
+
+// List returns the current decision for every purpose, with the latest text
+// in language.
+func (uc *ConsentUsecase) List(ctx context.Context, language string) ([]*models.ConsentStatus, error) {
+	subjectID, _, err := patient.Subject(ctx, patientModels.DelegateProfileRead)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := uc.latestByPurpose(ctx, subjectID, "List")
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*models.ConsentStatus, 0, len(models.AllPurposes))
+	for _, purpose := range models.AllPurposes {
+		text, _ := uc.texts.Get(purpose, uc.texts.Latest(purpose), language)
+		status := &models.ConsentStatus{Purpose: purpose, Text: &text}
+		if record, ok := latest[purpose]; ok {
+			status.Granted = uc.isGranted(record)
+			if record.Action == models.ConsentGranted {
+				status.GrantedVersion = record.Version
+			}
+			status.UpdatedAt = &record.CreatedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Record appends a grant or withdrawal to the ledger. Only the latest text
+// can be granted; a withdrawal applies whatever version was granted.
+func (uc *ConsentUsecase) Record(ctx context.Context, input *models.ConsentInput) (*models.ConsentRecord, error) {
+	subjectID, actorID, err := patient.Subject(ctx, patientModels.DelegateConsent)
+	if err != nil {
+		return nil, err
+	}
+	if !input.Purpose.IsValid() {
+		return nil, domain_errors.ErrInvalidConsentPurpose
+	}
+	latest := uc.texts.Latest(input.Purpose)
+	if input.Version < 1 || input.Version > latest {
+		return nil, domain_errors.ErrInvalidConsentVersion
+	}
+	if input.Granted && input.Version != latest {
+		return nil, domain_errors.ErrConsentVersionOutdated
+	}
+	text, _ := uc.texts.Get(input.Purpose, input.Version, input.Language)
+
+	action := models.ConsentWithdrawn
+	if input.Granted {
+		action = models.ConsentGranted
+	}
+	client := utils.ClientInfoFromContext(ctx)
+	record := &models.ConsentRecord{
+		SubjectID:  subjectID,
+		RecordedBy: actorID,
+		Purpose:    input.Purpose,
+		Version:    input.Version,
+		Language:   text.Language,
+		Action:     action,
+		IP:         client.IP,
+		UserAgent:  client.UserAgent,
+	}
+	if err := uc.consentRepo.Create(ctx, record); err != nil {
+		uc.logger.Error("failed to record consent (consentUC.Record.consentRepo.Create)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return record, nil
+}
+
+// History returns every consent decision of the subject, newest first.
+func (uc *ConsentUsecase) History(ctx context.Context) ([]*models.ConsentRecord, error) {
+	subjectID, _, err := patient.Subject(ctx, patientModels.DelegateHistoryRead)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := uc.consentRepo.ListHistory(ctx, subjectID)
+	if err != nil {
+		uc.logger.Error("failed to list consents (consentUC.History.consentRepo.ListHistory)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return records, nil
+}
+
+// Require fails with ErrConsentRequired, listing the missing purposes, unless
+// the subject has granted the latest text of every purpose.
+func (uc *ConsentUsecase) Require(ctx context.Context, subjectID uuid.UUID, purposes ...models.Purpose) error {
+	latest, err := uc.latestByPurpose(ctx, subjectID, "Require")
+	if err != nil {
+		return err
+	}
+
+	var missing []models.Purpose
+	for _, purpose := range purposes {
+		if record, ok := latest[purpose]; !ok || !uc.isGranted(record) {
+			missing = append(missing, purpose)
+		}
+	}
+	if len(missing) > 0 {
+		return domain_errors.ErrConsentRequired.WithDetails(map[string]any{"required": missing})
+	}
+	return nil
+}
+
+func (uc *ConsentUsecase) latestByPurpose(ctx context.Context, subjectID uuid.UUID, op string) (map[models.Purpose]*models.ConsentRecord, error) {
+	records, err := uc.consentRepo.ListLatest(ctx, subjectID)
+	if err != nil {
+		uc.logger.Error("failed to load consents (consentUC."+op+".consentRepo.ListLatest)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	latest := make(map[models.Purpose]*models.ConsentRecord, len(records))
+	for _, record := range records {
+		latest[record.Purpose] = record
+	}
+	return latest, nil
+}
+
+// isGranted reports whether record grants the text currently in force.
+func (uc *ConsentUsecase) isGranted(record *models.ConsentRecord) bool {
+	return record.Action == models.ConsentGranted && record.Version == uc.texts.Latest(record.Purpose)
+}
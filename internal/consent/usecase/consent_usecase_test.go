@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/auth"
+	mocks "swasthAI/internal/consent/mocks"
+	"swasthAI/internal/consent/models"
+	"swasthAI/internal/consent/texts"
+	"swasthAI/internal/patient"
+	patientModels "swasthAI/internal/patient/models"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTest(t *testing.T) (*ConsentUsecase, *mocks.MockConsentRepository, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	consentRepo := mocks.NewMockConsentRepository(ctrl)
+	registry, err := texts.NewRegistry()
+	require.NoError(t, err)
+	log, _ := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	return NewConsentUsecase(consentRepo, registry, *log), consentRepo, ctrl
+}
+
+func userContext() (context.Context, uuid.UUID) {
+	userID := uuid.New()
+	return auth.WithIdentity(context.Background(), &auth.Identity{UserID: userID}), userID
+}
+
+func TestConsentUsecase_Require_ListsMissing(t *testing.T) {
+	uc, consentRepo, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, userID := userContext()
+	consentRepo.EXPECT().ListLatest(ctx, userID).Return([]*models.ConsentRecord{
+		{Purpose: models.PurposeVoiceAI, Version: 1, Action: models.ConsentGranted},
+		{Purpose: models.PurposeImageAI, Version: 1, Action: models.ConsentWithdrawn},
+	}, nil).Times(2)
+
+	assert.NoError(t, uc.Require(ctx, userID, models.PurposeVoiceAI))
+
+	err := uc.Require(ctx, userID, models.PurposeVoiceAI, models.PurposeImageAI, models.PurposeDoctorSharing)
+	require.ErrorIs(t, err, domain_errors.ErrConsentRequired)
+	appErr, ok := err.(*appErrors.AppError)
+	require.True(t, ok)
+	assert.Equal(t, []models.Purpose{models.PurposeImageAI, models.PurposeDoctorSharing}, appErr.Details["required"])
+}
+
+func TestConsentUsecase_Record_Grant(t *testing.T) {
+	uc, consentRepo, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, userID := userContext()
+	consentRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, record *models.ConsentRecord) error {
+		assert.Equal(t, userID, record.SubjectID)
+		assert.Equal(t, userID, record.RecordedBy)
+		assert.Equal(t, models.ConsentGranted, record.Action)
+		// no Tamil text yet, so the English one was shown
+		assert.Equal(t, "en", record.Language)
+		return nil
+	})
+
+	_, err := uc.Record(ctx, &models.ConsentInput{Purpose: models.PurposeVoiceAI, Version: 1, Language: "ta", Granted: true})
+	assert.NoError(t, err)
+}
+
+func TestConsentUsecase_Record_UnknownVersion(t *testing.T) {
+	uc, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, _ := userContext()
+	_, err := uc.Record(ctx, &models.ConsentInput{Purpose: models.PurposeVoiceAI, Version: 7, Language: "hi", Granted: true})
+	assert.ErrorIs(t, err, domain_errors.ErrInvalidConsentVersion)
+
+	_, err = uc.Record(ctx, &models.ConsentInput{Purpose: "marketing", Version: 1, Language: "hi", Granted: true})
+	assert.ErrorIs(t, err, domain_errors.ErrInvalidConsentPurpose)
+}
+
+func TestConsentUsecase_Record_ActingForNeedsConsentPermission(t *testing.T) {
+	uc, consentRepo, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, userID := userContext()
+	patientID := uuid.New()
+	input := &models.ConsentInput{Purpose: models.PurposeVoiceAI, Version: 1, Language: "hi", Granted: true}
+
+	readOnly := patient.WithActingFor(ctx, &patient.ActingFor{PatientID: patientID, Permissions: []patientModels.DelegatedPermission{patientModels.DelegateProfileRead}})
+	_, err := uc.Record(readOnly, input)
+	assert.ErrorIs(t, err, domain_errors.ErrNotDelegated)
+
+	manager := patient.WithActingFor(ctx, &patient.ActingFor{PatientID: patientID, Permissions: []patientModels.DelegatedPermission{patientModels.DelegateConsent}})
+	consentRepo.EXPECT().Create(manager, gomock.Any()).DoAndReturn(func(ctx context.Context, record *models.ConsentRecord) error {
+		assert.Equal(t, patientID, record.SubjectID)
+		assert.Equal(t, userID, record.RecordedBy)
+		return nil
+	})
+	_, err = uc.Record(manager, input)
+	assert.NoError(t, err)
+}
+
+func TestConsentUsecase_List(t *testing.T) {
+	uc, consentRepo, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, userID := userContext()
+	grantedAt := time.Now().UTC()
+	consentRepo.EXPECT().ListLatest(ctx, userID).Return([]*models.ConsentRecord{
+		{Purpose: models.PurposeDoctorSharing, Version: 1, Action: models.ConsentGranted, CreatedAt: grantedAt},
+	}, nil)
+
+	statuses, err := uc.List(ctx, "hi")
+	require.NoError(t, err)
+	require.Len(t, statuses, len(models.AllPurposes))
+	for _, status := range statuses {
+		assert.Equal(t, "hi", status.Text.Language)
+		assert.Equal(t, status.Purpose == models.PurposeDoctorSharing, status.Granted)
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+
+	"swasthAI/config"
+	"swasthAI/internal/health"
+	"swasthAI/internal/health/models"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/http_errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	uc     health.HealthUsecase
+	logger *logger.Logger
+	Cfg    *config.Config
+}
+
+func NewHandler(uc health.HealthUsecase, logger *logger.Logger, cfg *config.Config) *Handler {
+	return &Handler{uc: uc, logger: logger, Cfg: cfg}
+}
+
+func (h *Handler) GetProfile(c echo.Context) error {
+	profile, err := h.uc.Get(c.Request().Context())
+	if err != nil {
+		h.logger.Error("failed to get health profile", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, profile)
+}
+
+func (h *Handler) UpdateProfile(c echo.Context) error {
+	var input models.HealthProfileInput
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error("failed to read input", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+
+	profile, err := h.uc.Update(c.Request().Context(), &input)
+	if err != nil {
+		h.logger.Error("failed to update health profile", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, profile)
+}
+
+func (h *Handler) ProfileHistory(c echo.Context) error {
+	profiles, err := h.uc.History(c.Request().Context())
+	if err != nil {
+		h.logger.Error("failed to list health profile history", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"history": profiles,
+	})
+}
@@ -0,0 +1,15 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"swasthAI/internal/middleware"
+)
+
+func (h *Handler) MapHealthRoutes(profile *echo.Group, mw middleware.MiddlewareManager) {
+	// X-Acting-For reads and edits a patient's profile instead of the caller's
+	profile.Use(mw.RequireAuth, mw.ActingFor)
+	profile.GET("", h.GetProfile)
+	profile.PUT("", h.UpdateProfile)
+	profile.GET("/history", h.ProfileHistory)
+}
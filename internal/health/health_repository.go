@@ -0,0 +1,15 @@
+package health
+
+import (
+	"context"
+	"swasthAI/internal/health/models"
+
+	"github.com/google/uuid"
+)
+
+type HealthProfileRepository interface {
+	// Create stores profile as the subject's next version.
+	Create(ctx context.Context, profile *models.HealthProfile) error
+	FindLatest(ctx context.Context, subjectID uuid.UUID) (*models.HealthProfile, error)
+	ListVersions(ctx context.Context, subjectID uuid.UUID) ([]*models.HealthProfile, error)
+}
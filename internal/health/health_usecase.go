@@ -0,0 +1,17 @@
+package health
+
+import (
+	"context"
+	"swasthAI/internal/health/models"
+
+	"github.com/google/uuid"
+)
+
+type HealthUsecase interface {
+	Get(ctx context.Context) (*models.HealthProfile, error)
+	Update(ctx context.Context, input *models.HealthProfileInput) (*models.HealthProfile, error)
+	History(ctx context.Context) ([]*models.HealthProfile, error)
+	// AIContext returns the subject's profile for AI requests, or nil if
+	// they have none.
+	AIContext(ctx context.Context, subjectID uuid.UUID) (*models.AIContext, error)
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type Sex string
+
+const (
+	SexFemale Sex = "female"
+	SexMale   Sex = "male"
+	SexOther  Sex = "other"
+)
+
+func (s Sex) IsValid() bool {
+	switch s {
+	case SexFemale, SexMale, SexOther:
+		return true
+	}
+	return false
+}
+
+type PregnancyStatus string
+
+const (
+	NotPregnant      PregnancyStatus = "not_pregnant"
+	Pregnant         PregnancyStatus = "pregnant"
+	PregnancyUnknown PregnancyStatus = "unknown"
+)
+
+func (p PregnancyStatus) IsValid() bool {
+	switch p {
+	case NotPregnant, Pregnant, PregnancyUnknown:
+		return true
+	}
+	return false
+}
+
+// BloodGroups lists the accepted ABO/Rh blood groups.
+var BloodGroups = []string{"A+", "A-", "B+", "B-", "AB+", "AB-", "O+", "O-"}
+
+// DateLayout is how dates of birth are written.
+const DateLayout = "2006-01-02"
+
+type Medication struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	Dose      string `json:"dose,omitempty" validate:"max=50"`      // e.g. "500 mg"
+	Frequency string `json:"frequency,omitempty" validate:"max=50"` // e.g. "twice a day"
+}
+
+// HealthProfile is one version of the health details of a user or managed
+// patient. Every save adds a version; the highest one is current, and older
+// ones show what was recorded before. Empty fields are unknown.
+type HealthProfile struct {
+	bun.BaseModel `bun:"table:health_profiles"`
+
+	ID                uuid.UUID       `bun:",pk,type:uuid,default:gen_random_uuid()" json:"id"`
+	SubjectID         uuid.UUID       `bun:",notnull,type:uuid,unique:health_profiles_subject_version" json:"subject_id"`
+	Version           int             `bun:",notnull,unique:health_profiles_subject_version" json:"version"`
+	DateOfBirth       string          `bun:",nullzero" json:"date_of_birth,omitempty"` // DateLayout
+	Sex               Sex             `bun:",nullzero" json:"sex,omitempty"`
+	BloodGroup        string          `bun:",nullzero" json:"blood_group,omitempty"`
+	Allergies         []string        `bun:",array" json:"allergies"`
+	ChronicConditions []string        `bun:",array" json:"chronic_conditions"`
+	Medications       []Medication    `bun:",type:jsonb" json:"medications"`
+	PregnancyStatus   PregnancyStatus `bun:",nullzero" json:"pregnancy_status,omitempty"`
+	HeightCM          float64         `bun:",nullzero" json:"height_cm,omitempty"`
+	WeightKG          float64         `bun:",nullzero" json:"weight_kg,omitempty"`
+	UpdatedBy         uuid.UUID       `bun:",notnull,type:uuid" json:"updated_by"`
+	CreatedAt         time.Time       `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// HealthProfileInput replaces the whole profile; omitted fields become unknown.
+type HealthProfileInput struct {
+	DateOfBirth       string          `json:"date_of_birth" validate:"omitempty,datetime=2006-01-02"`
+	Sex               Sex             `json:"sex"`
+	BloodGroup        string          `json:"blood_group"`
+	Allergies         []string        `json:"allergies" validate:"max=30,dive,required,max=100"`
+	ChronicConditions []string        `json:"chronic_conditions" validate:"max=30,dive,required,max=100"`
+	Medications       []Medication    `json:"medications" validate:"max=30,dive"`
+	PregnancyStatus   PregnancyStatus `json:"pregnancy_status"`
+	HeightCM          float64         `json:"height_cm" validate:"omitempty,gte=30,lte=250"`
+	WeightKG          float64         `json:"weight_kg" validate:"omitempty,gte=1,lte=350"`
+}
+
+// AIContext is the part of a health profile sent along with voice and
+// vision requests so the AI can take it into account. It carries the age
+// rather than the date of birth.
+type AIContext struct {
+	AgeYears          int             `json:"age_years,omitempty"`
+	Sex               Sex             `json:"sex,omitempty"`
+	BloodGroup        string          `json:"blood_group,omitempty"`
+	Allergies         []string        `json:"allergies,omitempty"`
+	ChronicConditions []string        `json:"chronic_conditions,omitempty"`
+	Medications       []Medication    `json:"medications,omitempty"`
+	PregnancyStatus   PregnancyStatus `json:"pregnancy_status,omitempty"`
+	HeightCM          float64         `json:"height_cm,omitempty"`
+	WeightKG          float64         `json:"weight_kg,omitempty"`
+}
+
+// AIContext returns the profile as context for AI models at now.
+func (p *HealthProfile) AIContext(now time.Time) *AIContext {
+	return &AIContext{
+		AgeYears:          Age(p.DateOfBirth, now),
+		Sex:               p.Sex,
+		BloodGroup:        p.BloodGroup,
+		Allergies:         p.Allergies,
+		ChronicConditions: p.ChronicConditions,
+		Medications:       p.Medications,
+		PregnancyStatus:   p.PregnancyStatus,
+		HeightCM:          p.HeightCM,
+		WeightKG:          p.WeightKG,
+	}
+}
+
+// Age returns the completed years between a date of birth and now, or 0 if
+// the date is unknown.
+func Age(dateOfBirth string, now time.Time) int {
+	dob, err := time.Parse(DateLayout, dateOfBirth)
+	if err != nil {
+		return 0
+	}
+	age := now.Year() - dob.Year()
+	if now.Month() < dob.Month() || (now.Month() == dob.Month() && now.Day() < dob.Day()) {
+		age--
+	}
+	return age
+}
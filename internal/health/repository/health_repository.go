@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"swasthAI/internal/health/models"
+	"swasthAI/pkg/domain_errors"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type HealthProfileRepository struct {
+	db *bun.DB
+}
+
+func NewHealthProfileRepository(db *bun.DB) *HealthProfileRepository {
+	return &HealthProfileRepository{db: db}
+}
+
+func (r *HealthProfileRepository) Create(ctx context.Context, profile *models.HealthProfile) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// serialise saves of one subject so versions stay consecutive
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", profile.SubjectID.String()); err != nil {
+			return errors.Wrap(err, "healthProfileRepo.Create.Lock")
+		}
+
+		var latest int
+		err := tx.NewSelect().
+			Model((*models.HealthProfile)(nil)).
+			ColumnExpr("coalesce(max(version), 0)").
+			Where("subject_id = ?", profile.SubjectID).
+			Scan(ctx, &latest)
+		if err != nil {
+			return errors.Wrap(err, "healthProfileRepo.Create.SelectVersion")
+		}
+		profile.Version = latest + 1
+
+		if _, err := tx.NewInsert().Model(profile).Returning("*").Exec(ctx); err != nil {
+			return errors.Wrap(err, "healthProfileRepo.Create.Insert")
+		}
+		return nil
+	})
+}
+
+func (r *HealthProfileRepository) FindLatest(ctx context.Context, subjectID uuid.UUID) (*models.HealthProfile, error) {
+	profile := new(models.HealthProfile)
+	err := r.db.NewSelect().
+		Model(profile).
+		Where("subject_id = ?", subjectID).
+		Order("version DESC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain_errors.ErrHealthProfileNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "healthProfileRepo.FindLatest.Select")
+	}
+	return profile, nil
+}
+
+// ListVersions returns every version of the subject's profile, newest first.
+func (r *HealthProfileRepository) ListVersions(ctx context.Context, subjectID uuid.UUID) ([]*models.HealthProfile, error) {
+	var profiles []*models.HealthProfile
+	err := r.db.NewSelect().
+		Model(&profiles).
+		Where("subject_id = ?", subjectID).
+		Order("version DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "healthProfileRepo.ListVersions.Select")
+	}
+	return profiles, nil
+}
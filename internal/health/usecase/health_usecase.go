@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"swasthAI/internal/audit"
+	auditModels "swasthAI/internal/audit/models"
+	"swasthAI/internal/health"
+	"swasthAI/internal/health/models"
+	"swasthAI/internal/patient"
+	patientModels "swasthAI/internal/patient/models"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// maxAge bounds dates of birth.
+const maxAge = 130
+
+type HealthUsecase struct {
+	repo    health.HealthProfileRepository
+	auditUC audit.AuditUsecase
+	logger  logger.Logger
+}
+
+func NewHealthUsecase(repo health.HealthProfileRepository, auditUC audit.AuditUsecase, logger logger.Logger) *HealthUsecase {
+	return &HealthUsecase{repo: repo, auditUC: auditUC, logger: logger}
+}
+
+// Get returns the current health profile of the caller, or of the patient
+// acted for.
+func (uc *HealthUsecase) Get(ctx context.Context) (*models.HealthProfile, error) {
+	subjectID, _, err := patient.Subject(ctx, patientModels.DelegateProfileRead)
+	if err != nil {
+		return nil, err
+	}
+	return uc.latest(ctx, subjectID, "Get")
+}
+
+// Update saves input as a new version of the profile.
+func (uc *HealthUsecase) Update(ctx context.Context, input *models.HealthProfileInput) (_ *models.HealthProfile, err error) {
+	subjectID, actorID, err := patient.Subject(ctx, patientModels.DelegateProfileWrite)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		uc.auditUC.Record(ctx, &auditModels.Event{Action: auditModels.ActionHealthUpdate, SubjectID: &subjectID, Err: err})
+	}()
+
+	profile, err := newProfile(input, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	profile.SubjectID = subjectID
+	profile.UpdatedBy = actorID
+
+	if err := uc.repo.Create(ctx, profile); err != nil {
+		uc.logger.Error("failed to save health profile (healthUC.Update.repo.Create)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return profile, nil
+}
+
+// History returns every saved version, newest first.
+func (uc *HealthUsecase) History(ctx context.Context) ([]*models.HealthProfile, error) {
+	subjectID, _, err := patient.Subject(ctx, patientModels.DelegateHistoryRead)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := uc.repo.ListVersions(ctx, subjectID)
+	if err != nil {
+		uc.logger.Error("failed to list health profile versions (healthUC.History.repo.ListVersions)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return profiles, nil
+}
+
+func (uc *HealthUsecase) AIContext(ctx context.Context, subjectID uuid.UUID) (*models.AIContext, error) {
+	profile, err := uc.latest(ctx, subjectID, "AIContext")
+	if errors.Is(err, domain_errors.ErrHealthProfileNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return profile.AIContext(time.Now().UTC()), nil
+}
+
+func (uc *HealthUsecase) latest(ctx context.Context, subjectID uuid.UUID, op string) (*models.HealthProfile, error) {
+	profile, err := uc.repo.FindLatest(ctx, subjectID)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrHealthProfileNotFound) {
+			return nil, domain_errors.ErrHealthProfileNotFound
+		}
+		uc.logger.Error("failed to find health profile (healthUC."+op+".repo.FindLatest)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return profile, nil
+}
+
+// newProfile validates input and cleans up its lists.
+func newProfile(input *models.HealthProfileInput, now time.Time) (*models.HealthProfile, error) {
+	if input.DateOfBirth != "" {
+		dob, err := time.Parse(models.DateLayout, input.DateOfBirth)
+		if err != nil || dob.After(now) || dob.Before(now.AddDate(-maxAge, 0, 0)) {
+			return nil, domain_errors.ErrInvalidDateOfBirth
+		}
+	}
+	if input.Sex != "" && !input.Sex.IsValid() {
+		return nil, domain_errors.ErrInvalidSex
+	}
+	bloodGroup := strings.ToUpper(strings.TrimSpace(input.BloodGroup))
+	if bloodGroup != "" && !slices.Contains(models.BloodGroups, bloodGroup) {
+		return nil, domain_errors.ErrInvalidBloodGroup
+	}
+	if input.PregnancyStatus != "" && !input.PregnancyStatus.IsValid() {
+		return nil, domain_errors.ErrInvalidPregnancyStatus
+	}
+	if input.PregnancyStatus == models.Pregnant && input.Sex == models.SexMale {
+		return nil, domain_errors.ErrPregnancyNotApplicable
+	}
+
+	medications := make([]models.Medication, 0, len(input.Medications))
+	for _, m := range input.Medications {
+		medications = append(medications, models.Medication{
+			Name:      strings.TrimSpace(m.Name),
+			Dose:      strings.TrimSpace(m.Dose),
+			Frequency: strings.TrimSpace(m.Frequency),
+		})
+	}
+
+	return &models.HealthProfile{
+		DateOfBirth:       input.DateOfBirth,
+		Sex:               input.Sex,
+		BloodGroup:        bloodGroup,
+		Allergies:         cleanList(input.Allergies),
+		ChronicConditions: cleanList(input.ChronicConditions),
+		Medications:       medications,
+		PregnancyStatus:   input.PregnancyStatus,
+		HeightCM:          input.HeightCM,
+		WeightKG:          input.WeightKG,
+	}, nil
+}
+
+// cleanList trims entries and drops blanks and case-insensitive duplicates,
+// keeping the first spelling.
+func cleanList(items []string) []string {
+	cleaned := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		key := strings.ToLower(item)
+		if item == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, item)
+	}
+	return cleaned
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+	auditMocks "swasthAI/internal/audit/mocks"
+	"swasthAI/internal/auth"
+	mocks "swasthAI/internal/health/mocks"
+	"swasthAI/internal/health/models"
+	"swasthAI/internal/patient"
+	patientModels "swasthAI/internal/patient/models"
+	"swasthAI/pkg/domain_errors"
+	"swasthAI/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTest(t *testing.T) (*HealthUsecase, *mocks.MockHealthProfileRepository, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	healthRepo := mocks.NewMockHealthProfileRepository(ctrl)
+	auditUC := auditMocks.NewMockAuditUsecase(ctrl)
+	auditUC.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+	log, _ := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	return NewHealthUsecase(healthRepo, auditUC, *log), healthRepo, ctrl
+}
+
+func userContext() (context.Context, uuid.UUID) {
+	userID := uuid.New()
+	return auth.WithIdentity(context.Background(), &auth.Identity{UserID: userID}), userID
+}
+
+func TestHealthUsecase_Update_CleansInput(t *testing.T) {
+	uc, healthRepo, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, userID := userContext()
+	healthRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, profile *models.HealthProfile) error {
+		assert.Equal(t, userID, profile.SubjectID)
+		assert.Equal(t, userID, profile.UpdatedBy)
+		assert.Equal(t, "B+", profile.BloodGroup)
+		assert.Equal(t, []string{"Penicillin"}, profile.Allergies)
+		assert.NotNil(t, profile.ChronicConditions)
+		assert.NotNil(t, profile.Medications)
+		profile.Version = 1
+		return nil
+	})
+
+	profile, err := uc.Update(ctx, &models.HealthProfileInput{
+		DateOfBirth: "1990-04-12",
+		Sex:         models.SexFemale,
+		BloodGroup:  " b+ ",
+		Allergies:   []string{"Penicillin ", "penicillin", " "},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, profile.Version)
+}
+
+func TestHealthUsecase_Update_Rejects(t *testing.T) {
+	uc, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, _ := userContext()
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format(models.DateLayout)
+	tests := []struct {
+		name  string
+		input models.HealthProfileInput
+		err   error
+	}{
+		{"future date of birth", models.HealthProfileInput{DateOfBirth: tomorrow}, domain_errors.ErrInvalidDateOfBirth},
+		{"ancient date of birth", models.HealthProfileInput{DateOfBirth: "1850-01-01"}, domain_errors.ErrInvalidDateOfBirth},
+		{"unknown sex", models.HealthProfileInput{Sex: "x"}, domain_errors.ErrInvalidSex},
+		{"unknown blood group", models.HealthProfileInput{BloodGroup: "C+"}, domain_errors.ErrInvalidBloodGroup},
+		{"unknown pregnancy status", models.HealthProfileInput{PregnancyStatus: "maybe"}, domain_errors.ErrInvalidPregnancyStatus},
+		{"pregnant male", models.HealthProfileInput{Sex: models.SexMale, PregnancyStatus: models.Pregnant}, domain_errors.ErrPregnancyNotApplicable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.Update(ctx, &tt.input)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestHealthUsecase_Update_ActingForNeedsWrite(t *testing.T) {
+	uc, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, _ := userContext()
+	ctx = patient.WithActingFor(ctx, &patient.ActingFor{
+		PatientID:   uuid.New(),
+		Permissions: []patientModels.DelegatedPermission{patientModels.DelegateProfileRead},
+	})
+
+	_, err := uc.Update(ctx, &models.HealthProfileInput{})
+	assert.ErrorIs(t, err, domain_errors.ErrNotDelegated)
+}
+
+func TestHealthUsecase_AIContext(t *testing.T) {
+	uc, healthRepo, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	subjectID := uuid.New()
+	healthRepo.EXPECT().FindLatest(ctx, subjectID).Return(nil, domain_errors.ErrHealthProfileNotFound)
+
+	aiContext, err := uc.AIContext(ctx, subjectID)
+	require.NoError(t, err)
+	assert.Nil(t, aiContext)
+
+	now := time.Now().UTC()
+	dob := now.AddDate(-30, 0, 1).Format(models.DateLayout)
+	healthRepo.EXPECT().FindLatest(ctx, subjectID).Return(&models.HealthProfile{DateOfBirth: dob, Allergies: []string{"Peanuts"}}, nil)
+
+	aiContext, err = uc.AIContext(ctx, subjectID)
+	require.NoError(t, err)
+	// the birthday is tomorrow
+	assert.Equal(t, 29, aiContext.AgeYears)
+	assert.Equal(t, []string{"Peanuts"}, aiContext.Allergies)
+}
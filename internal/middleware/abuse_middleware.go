@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"swasthAI/internal/abuse"
+	"swasthAI/internal/abuse/models"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ChallengeSolution stores a solved OTP challenge, sent in the
+// X-Challenge-Token and X-Challenge-Nonce headers, in the request context.
+func (mw *MiddlewareManager) ChallengeSolution(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		token := req.Header.Get("X-Challenge-Token")
+		if token == "" {
+			return next(c)
+		}
+		ctx := abuse.WithSolution(req.Context(), models.Solution{
+			Token: token,
+			Nonce: req.Header.Get("X-Challenge-Nonce"),
+		})
+		c.SetRequest(req.WithContext(ctx))
+		return next(c)
+	}
+}
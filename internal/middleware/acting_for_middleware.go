@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"swasthAI/internal/patient"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/http_errors"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// ActingForHeader names the patient a caregiver or ASHA worker is acting for.
+const ActingForHeader = "X-Acting-For"
+
+// ActingFor resolves the X-Acting-For header against the caller's
+// delegations. Without the header the caller acts for themselves. It must run
+// after RequireAuth.
+func (mw *MiddlewareManager) ActingFor(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Request().Header.Get(ActingForHeader) == "" {
+			return next(c)
+		}
+		if err := mw.resolveActingFor(c); err != nil {
+			return sendError(c, err)
+		}
+		return next(c)
+	}
+}
+
+// RequireActingFor is ActingFor for routes that only make sense for a
+// managed patient.
+func (mw *MiddlewareManager) RequireActingFor(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Request().Header.Get(ActingForHeader) == "" {
+			return http_errors.Send(c, domain_errors.ErrActingForRequired)
+		}
+		if err := mw.resolveActingFor(c); err != nil {
+			return sendError(c, err)
+		}
+		return next(c)
+	}
+}
+
+func (mw *MiddlewareManager) resolveActingFor(c echo.Context) error {
+	patientID, err := uuid.Parse(c.Request().Header.Get(ActingForHeader))
+	if err != nil {
+		return domain_errors.ErrActingForRequired
+	}
+
+	ctx := c.Request().Context()
+	actingFor, err := mw.PatientUC.ResolveActingFor(ctx, patientID)
+	if err != nil {
+		return err
+	}
+
+	c.SetRequest(c.Request().WithContext(patient.WithActingFor(ctx, actingFor)))
+	return nil
+}
+
+func sendError(c echo.Context, err error) error {
+	if appErr, ok := err.(*appErrors.AppError); ok {
+		return http_errors.Send(c, appErr)
+	}
+	return http_errors.Send(c, appErrors.ErrInternal)
+}
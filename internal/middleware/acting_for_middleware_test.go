@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"swasthAI/internal/patient"
+	mock_patient "swasthAI/internal/patient/mocks"
+	"swasthAI/internal/patient/models"
+	"swasthAI/pkg/domain_errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveActingFor runs req through mw and reports the patient seen by the handler.
+func serveActingFor(mw echo.MiddlewareFunc, req *http.Request) (*httptest.ResponseRecorder, *patient.ActingFor) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	var seen *patient.ActingFor
+	handler := mw(func(c echo.Context) error {
+		seen, _ = patient.ActingForFromContext(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	})
+	_ = handler(e.NewContext(req, rec))
+	return rec, seen
+}
+
+func TestActingFor_WithoutHeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mw := &MiddlewareManager{PatientUC: mock_patient.NewMockPatientUsecase(ctrl)}
+
+	rec, actingFor := serveActingFor(mw.ActingFor, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Nil(t, actingFor)
+
+	rec, _ = serveActingFor(mw.RequireActingFor, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestActingFor_Delegated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	patientUC := mock_patient.NewMockPatientUsecase(ctrl)
+	mw := &MiddlewareManager{PatientUC: patientUC}
+
+	patientID := uuid.New()
+	patientUC.EXPECT().ResolveActingFor(gomock.Any(), patientID).Return(&patient.ActingFor{
+		PatientID:   patientID,
+		Relation:    models.RelationASHA,
+		Permissions: []models.DelegatedPermission{models.DelegateVoiceUse},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(ActingForHeader, patientID.String())
+	rec, actingFor := serveActingFor(mw.RequireActingFor, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	require.NotNil(t, actingFor)
+	assert.Equal(t, patientID, actingFor.PatientID)
+}
+
+func TestActingFor_NotDelegated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	patientUC := mock_patient.NewMockPatientUsecase(ctrl)
+	mw := &MiddlewareManager{PatientUC: patientUC}
+
+	patientID := uuid.New()
+	patientUC.EXPECT().ResolveActingFor(gomock.Any(), patientID).Return(nil, domain_errors.ErrNotDelegated)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(ActingForHeader, patientID.String())
+	rec, actingFor := serveActingFor(mw.ActingFor, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Nil(t, actingFor)
+}
+
+func TestActingFor_MalformedHeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mw := &MiddlewareManager{PatientUC: mock_patient.NewMockPatientUsecase(ctrl)}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(ActingForHeader, "not-a-uuid")
+	rec, _ := serveActingFor(mw.ActingFor, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -1,83 +1,215 @@
 package middleware
 
 import (
-	"context"
+	"net/http"
 	"strings"
-	"swasthAI/config"
-	"swasthAI/internal/auth/usecase"
+	auditModels "swasthAI/internal/audit/models"
+	"swasthAI/internal/auth"
+	"swasthAI/internal/auth/models"
 	appErrors "swasthAI/pkg/errors"
 	"swasthAI/pkg/http_errors"
 	"swasthAI/pkg/utils"
 
-	"fmt"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
-func (mw *MiddlewareManager) AuthJWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+// BearerSubprotocol is the WebSocket subprotocol that carries an access
+// token. Browsers can't set headers on a WebSocket upgrade, so clients offer
+// ["bearer", "<token>"] as subprotocols; the server answers with "bearer".
+const BearerSubprotocol = "bearer"
+
+// accessTokenParam is the query parameter accepted on WebSocket upgrades.
+const accessTokenParam = "access_token"
+
+// RequireAuth rejects requests without a valid access token.
+func (mw *MiddlewareManager) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		bearerHeader := c.Request().Header.Get("Authorization")
-		if bearerHeader != "" {
-			headerParts := strings.Split(bearerHeader, " ")
-			if len(headerParts) != 2 {
-				mw.Logger.Error("len headerParts != 2")
-				return http_errors.Send(c, appErrors.ErrInvalidInput)
-			}
+		tokenString, err := extractToken(c.Request())
+		if err != nil {
+			return http_errors.Send(c, err.(*appErrors.AppError))
+		}
+		if tokenString == "" {
+			return http_errors.Send(c, appErrors.ErrUnauthorized)
+		}
 
-			tokenString := headerParts[1]
-			err := mw.ValidateJWTToken(tokenString, mw.AuthUC, mw.Cfg, c)
-			if err != nil {
-				return err
-			}
-			return next(c)
+		if err := mw.authenticate(c, tokenString); err != nil {
+			return http_errors.Send(c, err.(*appErrors.AppError))
 		}
 		return next(c)
 	}
 }
 
-func (mw MiddlewareManager) ValidateJWTToken(tokenString string, authUC usecase.AuthUsecase, cfg config.Config, c echo.Context) error {
-	fmt.Printf("ValidateJWTToken %s\n", tokenString)
-	if tokenString == "" {
-		return appErrors.ErrInvalidJWTToken
+// OptionalAuth lets anonymous requests through but still rejects a token that
+// was sent and does not verify.
+func (mw *MiddlewareManager) OptionalAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tokenString, err := extractToken(c.Request())
+		if err != nil {
+			return http_errors.Send(c, err.(*appErrors.AppError))
+		}
+		if tokenString == "" {
+			return next(c)
+		}
+
+		if err := mw.authenticate(c, tokenString); err != nil {
+			return http_errors.Send(c, err.(*appErrors.AppError))
+		}
+		return next(c)
 	}
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signin method %v", token.Header["alg"])
+}
+
+// Require lets a request through only if the caller holds every one of
+// perms. It must run after RequireAuth or OptionalAuth.
+func (mw *MiddlewareManager) Require(perms ...models.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			identity, ok := auth.FromContext(c.Request().Context())
+			if !ok {
+				return http_errors.Send(c, appErrors.ErrUnauthorized)
+			}
+			for _, perm := range perms {
+				if !identity.HasPermission(perm) {
+					mw.Logger.Warn("permission denied", "user_id", identity.UserID, "permission", perm, "path", c.Path())
+					mw.audit(c, auditModels.ActionAccessDenied, appErrors.ErrForbidden, map[string]any{"permission": perm})
+					return http_errors.Send(c, appErrors.ErrForbidden)
+				}
+			}
+			return next(c)
 		}
-		secret := []byte(cfg.JWT.Secret)
-		return secret, nil
-	})
+	}
+}
+
+// authenticate verifies an access token and the login it belongs to, and
+// stores the caller's identity in the request context.
+func (mw *MiddlewareManager) authenticate(c echo.Context, tokenString string) error {
+	claims, err := utils.ValidateAccessToken(tokenString, mw.Keys)
 	if err != nil {
+		mw.Logger.Error("invalid access token", "error", err, "ip", c.RealIP())
+		mw.audit(c, auditModels.ActionAccessTokenReject, appErrors.ErrInvalidJWTToken, nil)
+		return appErrors.ErrInvalidJWTToken
+	}
+	if claims.FamilyID == uuid.Nil {
+		return appErrors.ErrJWTInvalidClaims
+	}
+	if err := mw.AuthUC.CheckSession(c.Request().Context(), claims.FamilyID); err != nil {
+		mw.audit(c, auditModels.ActionAccessTokenReject, err, nil)
 		return err
 	}
 
-	if !token.Valid {
-		return appErrors.ErrInvalidJWTToken
+	identity := &auth.Identity{
+		UserID:   claims.ID,
+		FamilyID: claims.FamilyID,
+		TokenID:  claims.RegisteredClaims.ID,
+	}
+	for _, role := range claims.Roles {
+		identity.Roles = append(identity.Roles, models.Role(role))
+	}
+	for _, perm := range claims.Permissions {
+		identity.Permissions = append(identity.Permissions, models.Permission(perm))
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		fmt.Println("claims", claims)
-		userID, ok := claims["id"].(string)
-		if !ok {
-			return appErrors.ErrJWTInvalidClaims
+	ctx := auth.WithIdentity(c.Request().Context(), identity)
+	c.SetRequest(c.Request().WithContext(ctx))
+	return nil
+}
+
+// audit records a request the middleware turned away. Handlers never audit;
+// usecases record what they did.
+func (mw *MiddlewareManager) audit(c echo.Context, action auditModels.Action, err error, metadata map[string]any) {
+	if mw.AuditUC == nil {
+		return
+	}
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata["method"] = c.Request().Method
+	metadata["path"] = c.Path()
+	mw.AuditUC.Record(c.Request().Context(), &auditModels.Event{Action: action, Metadata: metadata, Err: err})
+}
+
+// extractToken reads the access token from the Authorization header. On a
+// WebSocket upgrade it also accepts the bearer subprotocol or the
+// access_token query parameter. An empty token means none was sent.
+func extractToken(req *http.Request) (string, error) {
+	if header := req.Header.Get("Authorization"); header != "" {
+		parts := strings.SplitN(header, " ", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") || parts[1] == "" {
+			return "", appErrors.ErrInvalidJWTToken
 		}
+		return parts[1], nil
+	}
 
-		userUUID, err := uuid.Parse(userID)
-		if err != nil {
-			return err
+	if !isWebSocketUpgrade(req) {
+		return "", nil
+	}
+	if token := subprotocolToken(req); token != "" {
+		return token, nil
+	}
+	return req.URL.Query().Get(accessTokenParam), nil
+}
+
+func isWebSocketUpgrade(req *http.Request) bool {
+	return strings.EqualFold(req.Header.Get("Upgrade"), "websocket")
+}
+
+// subprotocolToken returns the entry offered right after "bearer" in
+// Sec-WebSocket-Protocol.
+func subprotocolToken(req *http.Request) string {
+	var protocols []string
+	for _, header := range req.Header.Values("Sec-WebSocket-Protocol") {
+		for _, p := range strings.Split(header, ",") {
+			protocols = append(protocols, strings.TrimSpace(p))
 		}
+	}
+	for i := 0; i+1 < len(protocols); i++ {
+		if protocols[i] == BearerSubprotocol {
+			return protocols[i+1]
+		}
+	}
+	return ""
+}
+
+// maxRequestIDLength caps client-supplied request IDs, which end up in logs
+// and the audit log.
+const maxRequestIDLength = 64
 
-		jwtClaims := &utils.JWTClaims{
-			ID: userUUID,
+// RequestIDMiddleware gives every request an X-Request-ID, keeping the one
+// the client sent or generating one, and echoes it in the response so that
+// logs and audit events can be matched to a client report.
+func (mw *MiddlewareManager) RequestIDMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		id := req.Header.Get(echo.HeaderXRequestID)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+			req.Header.Set(echo.HeaderXRequestID, id)
 		}
-		fmt.Printf("set claims %v\n", jwtClaims)
-		ctx := context.WithValue(c.Request().Context(), "claims", jwtClaims)
-		c.SetRequest(c.Request().WithContext(ctx))
+		c.Response().Header().Set(echo.HeaderXRequestID, id)
+		return next(c)
+	}
+}
+
+// ClientInfoMiddleware stores the caller's IP, user agent, request ID and
+// device details in the request context for usecases to record.
+func (mw *MiddlewareManager) ClientInfoMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		ctx := utils.WithClientInfo(req.Context(), utils.ClientInfo{
+			IP:        c.RealIP(),
+			UserAgent: req.UserAgent(),
+			RequestID: req.Header.Get("X-Request-ID"),
+			DeviceID:  req.Header.Get("X-Device-ID"),
+
+			DeviceName: req.Header.Get("X-Device-Name"),
+			Platform:   req.Header.Get("X-Platform"),
+			AppVersion: req.Header.Get("X-App-Version"),
+		})
+		c.SetRequest(req.WithContext(ctx))
+		return next(c)
 	}
-	return nil
 }
 
 func (mw *MiddlewareManager) LoggerMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
@@ -0,0 +1,217 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"swasthAI/config"
+	mock_audit "swasthAI/internal/audit/mocks"
+	auditModels "swasthAI/internal/audit/models"
+	"swasthAI/internal/auth"
+	mock_auth "swasthAI/internal/auth/mocks"
+	"swasthAI/internal/auth/models"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/jwtkeys"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAuthTest(t *testing.T) (*MiddlewareManager, *utils.TokenPair, uuid.UUID) {
+	t.Helper()
+	cfg := config.JWT{Secret: "test-secret", ExpiresIn: 900, RefreshExpiresIn: 3600}
+	keys, err := jwtkeys.New(cfg)
+	require.NoError(t, err)
+	log, _ := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+
+	userID := uuid.New()
+	pair, err := utils.GenerateTokenPair(utils.TokenSubject{
+		UserID:      userID,
+		Roles:       []string{"doctor"},
+		Permissions: []string{"consultation:read", "patient:read"},
+	}, uuid.New(), keys, cfg)
+	require.NoError(t, err)
+
+	// every login is active unless a test says otherwise
+	authUC := mock_auth.NewMockAuthUsecase(gomock.NewController(t))
+	authUC.EXPECT().CheckSession(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return &MiddlewareManager{AuthUC: authUC, Keys: keys, Logger: log}, pair, userID
+}
+
+// serve runs req through mw and reports the identity seen by the handler.
+func serve(mw echo.MiddlewareFunc, req *http.Request) (*httptest.ResponseRecorder, *auth.Identity) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	var seen *auth.Identity
+	handler := mw(func(c echo.Context) error {
+		seen, _ = auth.FromContext(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	})
+	_ = handler(e.NewContext(req, rec))
+	return rec, seen
+}
+
+func upgradeRequest(target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	return req
+}
+
+func TestRequireAuth_BearerHeader(t *testing.T) {
+	mw, pair, userID := setupAuthTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+	rec, identity := serve(mw.RequireAuth, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	require.NotNil(t, identity)
+	assert.Equal(t, userID, identity.UserID)
+}
+
+func TestRequireAuth_MissingToken(t *testing.T) {
+	mw, _, _ := setupAuthTest(t)
+
+	rec, identity := serve(mw.RequireAuth, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Nil(t, identity)
+}
+
+func TestRequireAuth_RejectsRefreshToken(t *testing.T) {
+	mw, pair, _ := setupAuthTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+pair.RefreshToken)
+	rec, _ := serve(mw.RequireAuth, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRequireAuth_RevokedSession(t *testing.T) {
+	mw, pair, _ := setupAuthTest(t)
+	authUC := mock_auth.NewMockAuthUsecase(gomock.NewController(t))
+	authUC.EXPECT().CheckSession(gomock.Any(), gomock.Any()).Return(domain_errors.ErrRefreshTokenRevoked)
+	mw.AuthUC = authUC
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+	rec, identity := serve(mw.RequireAuth, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Nil(t, identity)
+}
+
+func TestRequireAuth_WebSocketSubprotocol(t *testing.T) {
+	mw, pair, userID := setupAuthTest(t)
+
+	req := upgradeRequest("/ws")
+	req.Header.Set("Sec-WebSocket-Protocol", BearerSubprotocol+", "+pair.AccessToken)
+	rec, identity := serve(mw.RequireAuth, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	require.NotNil(t, identity)
+	assert.Equal(t, userID, identity.UserID)
+}
+
+func TestRequireAuth_QueryParamOnlyOnUpgrade(t *testing.T) {
+	mw, pair, userID := setupAuthTest(t)
+
+	rec, identity := serve(mw.RequireAuth, upgradeRequest("/ws?access_token="+pair.AccessToken))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	require.NotNil(t, identity)
+	assert.Equal(t, userID, identity.UserID)
+
+	rec, _ = serve(mw.RequireAuth, httptest.NewRequest(http.MethodGet, "/?access_token="+pair.AccessToken, nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestOptionalAuth(t *testing.T) {
+	mw, pair, userID := setupAuthTest(t)
+
+	rec, identity := serve(mw.OptionalAuth, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Nil(t, identity)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+	rec, identity = serve(mw.OptionalAuth, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	require.NotNil(t, identity)
+	assert.Equal(t, userID, identity.UserID)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer not-a-token")
+	rec, _ = serve(mw.OptionalAuth, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRequire_Permissions(t *testing.T) {
+	mw, pair, _ := setupAuthTest(t)
+
+	withPerms := func(perms ...models.Permission) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return mw.RequireAuth(mw.Require(perms...)(next))
+		}
+	}
+	authed := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+		return req
+	}
+
+	rec, identity := serve(withPerms(models.PermConsultationRead, models.PermPatientRead), authed())
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	require.NotNil(t, identity)
+	assert.True(t, identity.HasRole(models.RoleDoctor))
+
+	rec, _ = serve(withPerms(models.PermConsultationRead, models.PermRoleAssign), authed())
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec, _ = serve(mw.Require(models.PermConsultationRead), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRequire_AuditsDenial(t *testing.T) {
+	mw, pair, userID := setupAuthTest(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	auditUC := mock_audit.NewMockAuditUsecase(ctrl)
+	mw.AuditUC = auditUC
+
+	auditUC.EXPECT().Record(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, event *auditModels.Event) {
+		identity, ok := auth.FromContext(ctx)
+		require.True(t, ok)
+		assert.Equal(t, userID, identity.UserID)
+		assert.Equal(t, auditModels.ActionAccessDenied, event.Action)
+		assert.Equal(t, models.PermRoleAssign, event.Metadata["permission"])
+		assert.ErrorIs(t, event.Err, appErrors.ErrForbidden)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+	rec, _ := serve(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return mw.RequireAuth(mw.Require(models.PermRoleAssign)(next))
+	}, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	mw, _, _ := setupAuthTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderXRequestID, "client-42")
+	rec, _ := serve(mw.RequestIDMiddleware, req)
+	assert.Equal(t, "client-42", rec.Header().Get(echo.HeaderXRequestID))
+
+	rec, _ = serve(mw.RequestIDMiddleware, httptest.NewRequest(http.MethodGet, "/", nil))
+	_, err := uuid.Parse(rec.Header().Get(echo.HeaderXRequestID))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+
+	"swasthAI/config"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IPExtractor decides where c.RealIP comes from. Forwarding headers are set
+// by whoever sends the request, so they are only read when the request
+// comes from one of the trusted proxies; otherwise the peer address is used.
+func IPExtractor(cfg config.Server) (echo.IPExtractor, error) {
+	if len(cfg.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range cfg.TrustedProxies {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("server.trustedproxies: %w", err)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"swasthAI/config"
+	"swasthAI/pkg/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clientIP runs a request from remoteAddr with the given X-Forwarded-For
+// through ClientInfoMiddleware and returns the IP the usecases see, which
+// is what the abuse guard counts.
+func clientIP(t *testing.T, cfg config.Server, remoteAddr, forwardedFor string) string {
+	extractor, err := IPExtractor(cfg)
+	require.NoError(t, err)
+	e := echo.New()
+	e.IPExtractor = extractor
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+	}
+	var ip string
+	handler := (&MiddlewareManager{}).ClientInfoMiddleware(func(c echo.Context) error {
+		ip = utils.ClientInfoFromContext(c.Request().Context()).IP
+		return nil
+	})
+	require.NoError(t, handler(e.NewContext(req, httptest.NewRecorder())))
+	return ip
+}
+
+func TestClientIP_IgnoresSpoofedForwardedFor(t *testing.T) {
+	cfg := config.Server{}
+
+	assert.Equal(t, "203.0.113.7", clientIP(t, cfg, "203.0.113.7:51234", ""))
+	assert.Equal(t, "203.0.113.7", clientIP(t, cfg, "203.0.113.7:51234", "198.51.100.1"))
+	assert.Equal(t, "203.0.113.7", clientIP(t, cfg, "203.0.113.7:51234", "198.51.100.2"))
+}
+
+func TestClientIP_TrustedProxy(t *testing.T) {
+	cfg := config.Server{TrustedProxies: []string{"10.0.0.0/8"}}
+
+	assert.Equal(t, "203.0.113.7", clientIP(t, cfg, "10.1.2.3:443", "203.0.113.7"))
+	// the client's own X-Forwarded-For entries come before the proxy's
+	assert.Equal(t, "203.0.113.7", clientIP(t, cfg, "10.1.2.3:443", "198.51.100.1, 203.0.113.7"))
+	// only the configured proxies are believed
+	assert.Equal(t, "192.168.1.9", clientIP(t, cfg, "192.168.1.9:443", "198.51.100.1"))
+}
+
+func TestIPExtractor_InvalidRange(t *testing.T) {
+	_, err := IPExtractor(config.Server{TrustedProxies: []string{"10.0.0.0"}})
+	assert.Error(t, err)
+}
@@ -2,16 +2,22 @@ package middleware
 
 import (
 	"swasthAI/config"
-	"swasthAI/internal/auth/usecase"
+	"swasthAI/internal/audit"
+	"swasthAI/internal/auth"
+	"swasthAI/internal/patient"
+	"swasthAI/pkg/jwtkeys"
 	"swasthAI/pkg/logger"
 )
 
 type MiddlewareManager struct {
-	AuthUC usecase.AuthUsecase
-	Cfg    config.Config
-	Logger *logger.Logger
+	AuthUC    auth.AuthUsecase
+	PatientUC patient.PatientUsecase
+	AuditUC   audit.AuditUsecase
+	Keys      *jwtkeys.KeySet
+	Cfg       config.Config
+	Logger    *logger.Logger
 }
 
-func NewMiddlewareManager(uc *usecase.AuthUsecase, cfg config.Config, logger *logger.Logger) *MiddlewareManager {
-	return &MiddlewareManager{AuthUC: *uc, Cfg: cfg, Logger: logger}
+func NewMiddlewareManager(uc auth.AuthUsecase, patientUC patient.PatientUsecase, auditUC audit.AuditUsecase, keys *jwtkeys.KeySet, cfg config.Config, logger *logger.Logger) *MiddlewareManager {
+	return &MiddlewareManager{AuthUC: uc, PatientUC: patientUC, AuditUC: auditUC, Keys: keys, Cfg: cfg, Logger: logger}
 }
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Channel is how a one-time code reaches the user.
+type Channel string
+
+const (
+	ChannelSMS      Channel = "sms"
+	ChannelVoice    Channel = "voice" // an automated call reads the code out
+	ChannelWhatsApp Channel = "whatsapp"
+)
+
+func (c Channel) IsValid() bool {
+	switch c {
+	case ChannelSMS, ChannelVoice, ChannelWhatsApp:
+		return true
+	}
+	return false
+}
+
+// OTPMessage is a code to deliver to one phone.
+type OTPMessage struct {
+	Channel  Channel
+	To       string // E.164
+	Code     string
+	Language string // the recipient's language, e.g. "hi"
+	TTL      time.Duration
+}
+
+// SendResult identifies the channel and provider that accepted a code.
+type SendResult struct {
+	Channel   Channel `json:"channel"`
+	Provider  string  `json:"provider"`
+	MessageID string  `json:"message_id,omitempty"`
+}
@@ -0,0 +1,19 @@
+package otpchannel
+
+import (
+	"context"
+	"swasthAI/internal/otpchannel/models"
+)
+
+// OTPSender delivers one-time codes over a single channel.
+type OTPSender interface {
+	Channel() models.Channel
+	Send(ctx context.Context, msg *models.OTPMessage) (*models.SendResult, error)
+}
+
+// OTPRouter hands each code to the sender of the channel it names.
+type OTPRouter interface {
+	Send(ctx context.Context, msg *models.OTPMessage) (*models.SendResult, error)
+	Enabled(channel models.Channel) bool
+	Default() models.Channel
+}
@@ -0,0 +1,23 @@
+package sender
+
+import "fmt"
+
+// ProviderError is returned when a voice or WhatsApp gateway rejects or
+// fails to accept a code.
+type ProviderError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+	Err        error
+}
+
+func (e *ProviderError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("otp provider %s: %v", e.Provider, e.Err)
+	}
+	return fmt.Sprintf("otp provider %s: status %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,53 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// postJSON sends payload to url and returns the response body. Responses
+// outside 2xx become a ProviderError.
+func postJSON(ctx context.Context, client *http.Client, provider, url string, headers map[string]string, payload any) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, &ProviderError{Provider: provider, Err: err}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, &ProviderError{Provider: provider, Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &ProviderError{Provider: provider, Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &ProviderError{Provider: provider, StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	return respBody, nil
+}
+
+// parseID extracts the gateway's reference for a call or message, which
+// providers return under different keys.
+func parseID(body []byte, keys ...string) string {
+	var resp map[string]any
+	if json.Unmarshal(body, &resp) != nil {
+		return ""
+	}
+	for _, key := range keys {
+		if id, ok := resp[key].(string); ok && id != "" {
+			return id
+		}
+	}
+	return ""
+}
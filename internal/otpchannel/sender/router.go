@@ -0,0 +1,46 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+
+	"swasthAI/internal/otpchannel"
+	"swasthAI/internal/otpchannel/models"
+)
+
+// Router sends each code over the channel it names.
+type Router struct {
+	senders        map[models.Channel]otpchannel.OTPSender
+	defaultChannel models.Channel
+}
+
+// NewRouter routes to senders; defaultChannel must be one of them.
+func NewRouter(defaultChannel models.Channel, senders ...otpchannel.OTPSender) (*Router, error) {
+	r := &Router{senders: make(map[models.Channel]otpchannel.OTPSender, len(senders)), defaultChannel: defaultChannel}
+	for _, s := range senders {
+		r.senders[s.Channel()] = s
+	}
+	if !r.Enabled(defaultChannel) {
+		return nil, fmt.Errorf("otp channels: default channel %q is not enabled", defaultChannel)
+	}
+	return r, nil
+}
+
+func (r *Router) Send(ctx context.Context, msg *models.OTPMessage) (*models.SendResult, error) {
+	s, ok := r.senders[msg.Channel]
+	if !ok {
+		return nil, fmt.Errorf("otp channels: channel %q is not enabled", msg.Channel)
+	}
+	return s.Send(ctx, msg)
+}
+
+// Enabled reports whether codes can be sent over channel.
+func (r *Router) Enabled(channel models.Channel) bool {
+	_, ok := r.senders[channel]
+	return ok
+}
+
+// Default is the channel used when the client asks for none.
+func (r *Router) Default() models.Channel {
+	return r.defaultChannel
+}
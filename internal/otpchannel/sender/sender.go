@@ -0,0 +1,40 @@
+package sender
+
+import (
+	"fmt"
+
+	"swasthAI/config"
+	"swasthAI/internal/otpchannel"
+	"swasthAI/internal/otpchannel/models"
+	"swasthAI/internal/sms"
+)
+
+// NewFromConfig builds a Router over the enabled channels. SMS is sent
+// through smsUC.
+func NewFromConfig(cfg config.OTPChannels, smsUC sms.SMSUsecase) (*Router, error) {
+	var senders []otpchannel.OTPSender
+	for _, name := range cfg.Enabled {
+		switch channel := models.Channel(name); channel {
+		case models.ChannelSMS:
+			senders = append(senders, NewSMSSender(smsUC))
+		case models.ChannelVoice:
+			if cfg.Voice.URL == "" {
+				return nil, fmt.Errorf("otp channels: voice is enabled without a gateway url")
+			}
+			senders = append(senders, NewVoiceSender(cfg.Voice))
+		case models.ChannelWhatsApp:
+			if cfg.WhatsApp.URL == "" || cfg.WhatsApp.Template == "" {
+				return nil, fmt.Errorf("otp channels: whatsapp is enabled without a url and template")
+			}
+			senders = append(senders, NewWhatsAppSender(cfg.WhatsApp))
+		default:
+			return nil, fmt.Errorf("otp channels: unknown channel %q", name)
+		}
+	}
+
+	defaultChannel := models.Channel(cfg.Default)
+	if defaultChannel == "" {
+		defaultChannel = models.ChannelSMS
+	}
+	return NewRouter(defaultChannel, senders...)
+}
@@ -0,0 +1,168 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/otpchannel/models"
+	smsMocks "swasthAI/internal/sms/mocks"
+	smsModels "swasthAI/internal/sms/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func otpMessage(channel models.Channel, language string) *models.OTPMessage {
+	return &models.OTPMessage{Channel: channel, To: "+919876543210", Code: "4821", Language: language, TTL: 5 * time.Minute}
+}
+
+func TestVoiceSender_Send(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Write([]byte(`{"call_sid":"CA123"}`))
+	}))
+	defer srv.Close()
+
+	s := NewVoiceSender(config.OTPVoice{Name: "exotel", URL: srv.URL, APIKey: "secret", AuthHeader: "X-Api-Key", CallerID: "08044556677", Repeat: 2})
+	res, err := s.Send(context.Background(), otpMessage(models.ChannelVoice, "hi"))
+	require.NoError(t, err)
+	assert.Equal(t, &models.SendResult{Channel: models.ChannelVoice, Provider: "exotel", MessageID: "CA123"}, res)
+	assert.Equal(t, "919876543210", got["to"])
+	assert.Equal(t, "08044556677", got["from"])
+	assert.Equal(t, "hi-IN", got["language"])
+	assert.Equal(t, VoiceScript("hi", "4821", 2).Text, got["text"])
+}
+
+func TestVoiceSender_ProviderError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("busy"))
+	}))
+	defer srv.Close()
+
+	s := NewVoiceSender(config.OTPVoice{Name: "exotel", URL: srv.URL})
+	_, err := s.Send(context.Background(), otpMessage(models.ChannelVoice, "en"))
+	var perr *ProviderError
+	require.True(t, errors.As(err, &perr))
+	assert.Equal(t, http.StatusServiceUnavailable, perr.StatusCode)
+	assert.Equal(t, "busy", perr.Body)
+}
+
+func TestVoiceScript(t *testing.T) {
+	s := VoiceScript("ta", "307", 2)
+	assert.Equal(t, "ta-IN", s.Locale)
+	assert.Equal(t, "உங்கள் Swasth AI சரிபார்ப்புக் குறியீடு: மூன்று, பூஜ்ஜியம், ஏழு. மீண்டும், உங்கள் குறியீடு: மூன்று, பூஜ்ஜியம், ஏழு.", s.Text)
+
+	// unknown languages fall back to English
+	s = VoiceScript("xx", "12", 1)
+	assert.Equal(t, "en-IN", s.Locale)
+	assert.Equal(t, "Your Swasth AI verification code is: one, two.", s.Text)
+}
+
+func TestWhatsAppSender_Send(t *testing.T) {
+	var got struct {
+		To       string `json:"to"`
+		Type     string `json:"type"`
+		Template struct {
+			Name       string            `json:"name"`
+			Language   map[string]string `json:"language"`
+			Components []struct {
+				Type       string              `json:"type"`
+				Parameters []map[string]string `json:"parameters"`
+			} `json:"components"`
+		} `json:"template"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Write([]byte(`{"messaging_product":"whatsapp","messages":[{"id":"wamid.HBg"}]}`))
+	}))
+	defer srv.Close()
+
+	s := NewWhatsAppSender(config.OTPWhatsApp{Name: "whatsapp", URL: srv.URL, Token: "token", Template: "otp_code"})
+	res, err := s.Send(context.Background(), otpMessage(models.ChannelWhatsApp, "ta"))
+	require.NoError(t, err)
+	assert.Equal(t, "wamid.HBg", res.MessageID)
+	assert.Equal(t, "919876543210", got.To)
+	assert.Equal(t, "template", got.Type)
+	assert.Equal(t, "otp_code", got.Template.Name)
+	assert.Equal(t, "ta", got.Template.Language["code"])
+	require.Len(t, got.Template.Components, 2)
+	for _, c := range got.Template.Components {
+		assert.Equal(t, "4821", c.Parameters[0]["text"], c.Type)
+	}
+}
+
+func TestWhatsAppSender_ProviderError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":132001}}`))
+	}))
+	defer srv.Close()
+
+	s := NewWhatsAppSender(config.OTPWhatsApp{Name: "whatsapp", URL: srv.URL, Template: "otp_code"})
+	_, err := s.Send(context.Background(), otpMessage(models.ChannelWhatsApp, "en"))
+	var perr *ProviderError
+	require.True(t, errors.As(err, &perr))
+	assert.Equal(t, http.StatusBadRequest, perr.StatusCode)
+}
+
+func TestSMSSender_Send(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	smsUC := smsMocks.NewMockSMSUsecase(ctrl)
+	smsUC.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req *smsModels.SendRequest) (*smsModels.OutboxMessage, error) {
+		assert.Equal(t, smsModels.KindOTP, req.Kind)
+		assert.Equal(t, "4821", req.Vars["otp"])
+		assert.Equal(t, "5", req.Vars["minutes"])
+		return &smsModels.OutboxMessage{Provider: "msg91", ProviderMessageID: "req-1"}, nil
+	})
+
+	res, err := NewSMSSender(smsUC).Send(context.Background(), otpMessage(models.ChannelSMS, "en"))
+	require.NoError(t, err)
+	assert.Equal(t, &models.SendResult{Channel: models.ChannelSMS, Provider: "msg91", MessageID: "req-1"}, res)
+}
+
+func TestNewFromConfig(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"call-1"}`))
+	}))
+	defer srv.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	r, err := NewFromConfig(config.OTPChannels{
+		Enabled: []string{"sms", "voice"},
+		Voice:   config.OTPVoice{Name: "voice", URL: srv.URL},
+	}, smsMocks.NewMockSMSUsecase(ctrl))
+	require.NoError(t, err)
+	assert.Equal(t, models.ChannelSMS, r.Default())
+	assert.True(t, r.Enabled(models.ChannelVoice))
+	assert.False(t, r.Enabled(models.ChannelWhatsApp))
+
+	res, err := r.Send(context.Background(), otpMessage(models.ChannelVoice, "en"))
+	require.NoError(t, err)
+	assert.Equal(t, "call-1", res.MessageID)
+
+	_, err = r.Send(context.Background(), otpMessage(models.ChannelWhatsApp, "en"))
+	assert.Error(t, err)
+
+	// misconfigured channels fail at startup rather than on the first login
+	_, err = NewFromConfig(config.OTPChannels{Enabled: []string{"sms", "whatsapp"}}, nil)
+	assert.Error(t, err)
+	_, err = NewFromConfig(config.OTPChannels{Default: "voice", Enabled: []string{"sms"}}, nil)
+	assert.Error(t, err)
+	_, err = NewFromConfig(config.OTPChannels{Enabled: []string{"fax"}}, nil)
+	assert.Error(t, err)
+}
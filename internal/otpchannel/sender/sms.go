@@ -0,0 +1,43 @@
+package sender
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"swasthAI/internal/otpchannel/models"
+	"swasthAI/internal/sms"
+	smsModels "swasthAI/internal/sms/models"
+)
+
+// SMSSender sends codes as the DLT registered OTP text message, through
+// the SMS outbox and its provider failover. The outbox keeps the code
+// masked and does not retry it, so a failed send is final.
+type SMSSender struct {
+	smsUC sms.SMSUsecase
+}
+
+func NewSMSSender(smsUC sms.SMSUsecase) *SMSSender {
+	return &SMSSender{smsUC: smsUC}
+}
+
+func (s *SMSSender) Channel() models.Channel {
+	return models.ChannelSMS
+}
+
+func (s *SMSSender) Send(ctx context.Context, msg *models.OTPMessage) (*models.SendResult, error) {
+	out, err := s.smsUC.Send(ctx, &smsModels.SendRequest{
+		To:       msg.To,
+		Kind:     smsModels.KindOTP,
+		Language: msg.Language,
+		Vars: map[string]string{
+			"otp":     msg.Code,
+			"minutes": strconv.Itoa(int(msg.TTL / time.Minute)),
+		},
+		TTL: msg.TTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.SendResult{Channel: models.ChannelSMS, Provider: out.Provider, MessageID: out.ProviderMessageID}, nil
+}
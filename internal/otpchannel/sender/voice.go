@@ -0,0 +1,71 @@
+package sender
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/otpchannel/models"
+)
+
+// VoiceSender asks a telephony gateway to call the user and read the code
+// out, digit by digit, in their language.
+type VoiceSender struct {
+	name       string
+	url        string
+	apiKey     string
+	authHeader string
+	callerID   string
+	repeat     int
+	client     *http.Client
+}
+
+func NewVoiceSender(cfg config.OTPVoice) *VoiceSender {
+	authHeader := cfg.AuthHeader
+	if authHeader == "" {
+		authHeader = "Authorization"
+	}
+	repeat := cfg.Repeat
+	if repeat <= 0 {
+		repeat = 2
+	}
+	return &VoiceSender{
+		name:       cfg.Name,
+		url:        cfg.URL,
+		apiKey:     cfg.APIKey,
+		authHeader: authHeader,
+		callerID:   cfg.CallerID,
+		repeat:     repeat,
+		client:     &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}
+}
+
+func (s *VoiceSender) Channel() models.Channel {
+	return models.ChannelVoice
+}
+
+func (s *VoiceSender) Send(ctx context.Context, msg *models.OTPMessage) (*models.SendResult, error) {
+	script := VoiceScript(msg.Language, msg.Code, s.repeat)
+	payload := map[string]string{
+		"to":       strings.TrimPrefix(msg.To, "+"),
+		"from":     s.callerID,
+		"language": script.Locale,
+		"text":     script.Text,
+	}
+	headers := map[string]string{}
+	if s.apiKey != "" {
+		headers[s.authHeader] = s.apiKey
+	}
+
+	body, err := postJSON(ctx, s.client, s.name, s.url, headers, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SendResult{
+		Channel:   models.ChannelVoice,
+		Provider:  s.name,
+		MessageID: parseID(body, "call_id", "call_sid", "request_uuid", "id"),
+	}, nil
+}
@@ -0,0 +1,60 @@
+package sender
+
+import "strings"
+
+// script is what the voice call says in one language. Digits are spoken
+// as words, one at a time, so text-to-speech does not read "482913" as a
+// number.
+type script struct {
+	locale string // text-to-speech voice
+	intro  string
+	again  string
+	digits [10]string
+}
+
+var scripts = map[string]script{
+	"en": {locale: "en-IN", intro: "Your Swasth AI verification code is", again: "Once again, your code is",
+		digits: [10]string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}},
+	"hi": {locale: "hi-IN", intro: "आपका Swasth AI सत्यापन कोड है", again: "फिर से सुनिए, आपका कोड है",
+		digits: [10]string{"शून्य", "एक", "दो", "तीन", "चार", "पाँच", "छह", "सात", "आठ", "नौ"}},
+	"ta": {locale: "ta-IN", intro: "உங்கள் Swasth AI சரிபார்ப்புக் குறியீடு", again: "மீண்டும், உங்கள் குறியீடு",
+		digits: [10]string{"பூஜ்ஜியம்", "ஒன்று", "இரண்டு", "மூன்று", "நான்கு", "ஐந்து", "ஆறு", "ஏழு", "எட்டு", "ஒன்பது"}},
+	"te": {locale: "te-IN", intro: "మీ Swasth AI ధృవీకరణ కోడ్", again: "మళ్ళీ, మీ కోడ్",
+		digits: [10]string{"సున్నా", "ఒకటి", "రెండు", "మూడు", "నాలుగు", "ఐదు", "ఆరు", "ఏడు", "ఎనిమిది", "తొమ్మిది"}},
+	"bn": {locale: "bn-IN", intro: "আপনার Swasth AI যাচাইকরণ কোড হল", again: "আবার বলছি, আপনার কোড হল",
+		digits: [10]string{"শূন্য", "এক", "দুই", "তিন", "চার", "পাঁচ", "ছয়", "সাত", "আট", "নয়"}},
+	"mr": {locale: "mr-IN", intro: "तुमचा Swasth AI पडताळणी कोड आहे", again: "पुन्हा एकदा, तुमचा कोड आहे",
+		digits: [10]string{"शून्य", "एक", "दोन", "तीन", "चार", "पाच", "सहा", "सात", "आठ", "नऊ"}},
+}
+
+// Script is the text a voice call reads out and the voice to read it with.
+type Script struct {
+	Locale string
+	Text   string
+}
+
+// VoiceScript returns what a call says to deliver code in language, with
+// the code read out repeat times. Unknown languages use English.
+func VoiceScript(language, code string, repeat int) Script {
+	s, ok := scripts[language]
+	if !ok {
+		s = scripts["en"]
+	}
+
+	words := make([]string, 0, len(code))
+	for _, r := range code {
+		if r >= '0' && r <= '9' {
+			words = append(words, s.digits[r-'0'])
+		} else {
+			words = append(words, string(r))
+		}
+	}
+	digits := strings.Join(words, ", ")
+
+	var b strings.Builder
+	b.WriteString(s.intro + ": " + digits + ".")
+	for i := 1; i < repeat; i++ {
+		b.WriteString(" " + s.again + ": " + digits + ".")
+	}
+	return Script{Locale: s.locale, Text: b.String()}
+}
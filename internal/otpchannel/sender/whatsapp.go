@@ -0,0 +1,80 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/otpchannel/models"
+)
+
+// WhatsAppSender sends codes as an authentication template through the
+// WhatsApp Business Cloud API. The template needs an approved translation
+// for every language users may have.
+type WhatsAppSender struct {
+	name     string
+	url      string
+	token    string
+	template string
+	client   *http.Client
+}
+
+func NewWhatsAppSender(cfg config.OTPWhatsApp) *WhatsAppSender {
+	return &WhatsAppSender{
+		name:     cfg.Name,
+		url:      cfg.URL,
+		token:    cfg.Token,
+		template: cfg.Template,
+		client:   &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}
+}
+
+func (s *WhatsAppSender) Channel() models.Channel {
+	return models.ChannelWhatsApp
+}
+
+func (s *WhatsAppSender) Send(ctx context.Context, msg *models.OTPMessage) (*models.SendResult, error) {
+	language := msg.Language
+	if language == "" {
+		language = "en"
+	}
+	code := []map[string]string{{"type": "text", "text": msg.Code}}
+	payload := map[string]any{
+		"messaging_product": "whatsapp",
+		"to":                strings.TrimPrefix(msg.To, "+"),
+		"type":              "template",
+		"template": map[string]any{
+			"name":     s.template,
+			"language": map[string]string{"code": language},
+			"components": []map[string]any{
+				{"type": "body", "parameters": code},
+				// the copy-code button carries the code too
+				{"type": "button", "sub_type": "url", "index": "0", "parameters": code},
+			},
+		},
+	}
+	headers := map[string]string{"Authorization": "Bearer " + s.token}
+
+	body, err := postJSON(ctx, s.client, s.name, s.url, headers, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SendResult{Channel: models.ChannelWhatsApp, Provider: s.name, MessageID: parseMessageID(body)}, nil
+}
+
+// parseMessageID reads the "wamid" of the first message in a Cloud API
+// response.
+func parseMessageID(body []byte) string {
+	var resp struct {
+		Messages []struct {
+			ID string `json:"id"`
+		} `json:"messages"`
+	}
+	if json.Unmarshal(body, &resp) != nil || len(resp.Messages) == 0 {
+		return ""
+	}
+	return resp.Messages[0].ID
+}
@@ -0,0 +1,60 @@
+package patient
+
+import (
+	"context"
+	"swasthAI/internal/auth"
+	"swasthAI/internal/patient/models"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+
+	"github.com/google/uuid"
+)
+
+// ActingFor is the patient a request is made on behalf of, resolved from
+// the X-Acting-For header and the caller's delegation.
+type ActingFor struct {
+	PatientID   uuid.UUID
+	Relation    models.Relation
+	Permissions []models.DelegatedPermission
+}
+
+func (a *ActingFor) Allows(perm models.DelegatedPermission) bool {
+	for _, p := range a.Permissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+type actingForKey struct{}
+
+// WithActingFor returns a copy of ctx carrying the patient acted for.
+func WithActingFor(ctx context.Context, actingFor *ActingFor) context.Context {
+	return context.WithValue(ctx, actingForKey{}, actingFor)
+}
+
+// ActingForFromContext returns the patient the caller acts for, if any.
+// Without one the caller acts for themselves.
+func ActingForFromContext(ctx context.Context) (*ActingFor, bool) {
+	actingFor, ok := ctx.Value(actingForKey{}).(*ActingFor)
+	return actingFor, ok && actingFor != nil
+}
+
+// Subject returns whose data the request is about: the patient acted for,
+// if the delegation allows perm, or else the caller. The second id is the
+// caller.
+func Subject(ctx context.Context, perm models.DelegatedPermission) (uuid.UUID, uuid.UUID, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, appErrors.ErrUnauthorized
+	}
+	actingFor, ok := ActingForFromContext(ctx)
+	if !ok {
+		return identity.UserID, identity.UserID, nil
+	}
+	if !actingFor.Allows(perm) {
+		return uuid.Nil, uuid.Nil, domain_errors.ErrNotDelegated
+	}
+	return actingFor.PatientID, identity.UserID, nil
+}
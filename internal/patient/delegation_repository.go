@@ -0,0 +1,17 @@
+package patient
+
+import (
+	"context"
+	"swasthAI/internal/patient/models"
+
+	"github.com/google/uuid"
+)
+
+type DelegationRepository interface {
+	Create(ctx context.Context, delegation *models.Delegation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Delegation, error)
+	FindActive(ctx context.Context, patientID, userID uuid.UUID) (*models.Delegation, error)
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]*models.Delegation, error)
+	ListForPatient(ctx context.Context, patientID uuid.UUID) ([]*models.Delegation, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
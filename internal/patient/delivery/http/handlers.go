@@ -0,0 +1,155 @@
+package http
+
+import (
+	"net/http"
+
+	"swasthAI/config"
+	"swasthAI/internal/patient"
+	"swasthAI/internal/patient/models"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/http_errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	uc     patient.PatientUsecase
+	logger *logger.Logger
+	Cfg    *config.Config
+}
+
+func NewHandler(uc patient.PatientUsecase, logger *logger.Logger, cfg *config.Config) *Handler {
+	return &Handler{uc: uc, logger: logger, Cfg: cfg}
+}
+
+func (h *Handler) CreateProfile(c echo.Context) error {
+	var input models.CreateProfileInput
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error("failed to read input", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+
+	profile, err := h.uc.CreateProfile(c.Request().Context(), &input)
+	if err != nil {
+		h.logger.Error("failed to create patient", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusCreated, profile)
+}
+
+func (h *Handler) ListProfiles(c echo.Context) error {
+	profiles, err := h.uc.ListProfiles(c.Request().Context(), c.QueryParam("q"))
+	if err != nil {
+		h.logger.Error("failed to list patients", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"patients": profiles,
+	})
+}
+
+func (h *Handler) GetProfile(c echo.Context) error {
+	profile, err := h.uc.GetProfile(c.Request().Context())
+	if err != nil {
+		h.logger.Error("failed to get patient", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, profile)
+}
+
+func (h *Handler) UpdateProfile(c echo.Context) error {
+	var input models.UpdateProfileInput
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error("failed to read input", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+
+	profile, err := h.uc.UpdateProfile(c.Request().Context(), &input)
+	if err != nil {
+		h.logger.Error("failed to update patient", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, profile)
+}
+
+func (h *Handler) ListDelegates(c echo.Context) error {
+	delegations, err := h.uc.ListDelegates(c.Request().Context())
+	if err != nil {
+		h.logger.Error("failed to list delegates", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"delegates": delegations,
+	})
+}
+
+func (h *Handler) AddDelegate(c echo.Context) error {
+	var input models.AddDelegateInput
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error("failed to read input", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+
+	delegation, err := h.uc.AddDelegate(c.Request().Context(), &input)
+	if err != nil {
+		h.logger.Error("failed to add delegate", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusCreated, delegation)
+}
+
+func (h *Handler) RevokeDelegate(c echo.Context) error {
+	delegationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return http_errors.Send(c, domain_errors.ErrDelegationNotFound)
+	}
+
+	if err := h.uc.RevokeDelegate(c.Request().Context(), delegationID); err != nil {
+		h.logger.Error("failed to revoke delegate", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Delegate removed",
+	})
+}
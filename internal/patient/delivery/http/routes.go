@@ -0,0 +1,21 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"swasthAI/internal/middleware"
+)
+
+func (h *Handler) MapPatientRoutes(patients *echo.Group, mw middleware.MiddlewareManager) {
+	patients.Use(mw.RequireAuth)
+	patients.POST("", h.CreateProfile)
+	patients.GET("", h.ListProfiles)
+
+	// the patient is chosen with the X-Acting-For header
+	actingFor := patients.Group("", mw.RequireActingFor)
+	actingFor.GET("/profile", h.GetProfile)
+	actingFor.PUT("/profile", h.UpdateProfile)
+	actingFor.GET("/delegates", h.ListDelegates)
+	actingFor.POST("/delegates", h.AddDelegate)
+	actingFor.DELETE("/delegates/:id", h.RevokeDelegate)
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"swasthAI/pkg/names"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Profile is a patient who may have no login of their own. It is operated
+// by the accounts that hold a Delegation for it.
+type Profile struct {
+	bun.BaseModel `bun:"table:patient_profiles"`
+
+	ID        uuid.UUID `bun:",pk,type:uuid,default:gen_random_uuid()" json:"id"`
+	FirstName string    `bun:",notnull" json:"first_name"`
+	LastName  string    `bun:",nullzero" json:"last_name,omitempty"`
+	FullName  string    `bun:",notnull" json:"full_name"`
+	Language  string    `bun:",notnull" json:"language"`
+	Gender    string    `bun:",nullzero" json:"gender,omitempty"`
+	BirthYear int       `bun:",nullzero" json:"birth_year,omitempty"`
+	Phone     string    `bun:",nullzero" json:"phone,omitempty"` // shared or household phone, if any
+	Village   string    `bun:",nullzero" json:"village,omitempty"`
+	CreatedBy uuid.UUID `bun:",notnull,type:uuid" json:"created_by"`
+	CreatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"updated_at"`
+
+	// Latin forms of the names; empty when their script cannot be romanised
+	FirstNameLatin string `bun:",nullzero" json:"first_name_latin,omitempty"`
+	LastNameLatin  string `bun:",nullzero" json:"last_name_latin,omitempty"`
+	FullNameLatin  string `bun:",nullzero" json:"full_name_latin,omitempty"`
+	NameKey        string `bun:",nullzero" json:"-"` // names.SearchKey of FullNameLatin
+}
+
+func (p *Profile) PrepareSave() {
+	p.FirstName = strings.TrimSpace(p.FirstName)
+	p.LastName = strings.TrimSpace(p.LastName)
+	p.FullName = strings.TrimSpace(p.FirstName + " " + p.LastName)
+	p.FirstNameLatin = names.LatinForm(p.FirstName, p.FirstNameLatin)
+	p.LastNameLatin = names.LatinForm(p.LastName, p.LastNameLatin)
+	p.FullNameLatin = strings.TrimSpace(p.FirstNameLatin + " " + p.LastNameLatin)
+	p.NameKey = names.SearchKey(p.FullNameLatin)
+	p.Phone = strings.TrimSpace(p.Phone)
+	p.UpdatedAt = time.Now().UTC()
+}
+
+// Relation is how a managing account is related to the patient.
+type Relation string
+
+const (
+	RelationFamily    Relation = "family"
+	RelationCaregiver Relation = "caregiver"
+	RelationASHA      Relation = "asha_worker"
+	RelationDoctor    Relation = "doctor"
+)
+
+func (r Relation) IsValid() bool {
+	switch r {
+	case RelationFamily, RelationCaregiver, RelationASHA, RelationDoctor:
+		return true
+	}
+	return false
+}
+
+// DelegatedPermission is what a managing account may do for a patient.
+type DelegatedPermission string
+
+const (
+	DelegateProfileRead  DelegatedPermission = "profile:read"
+	DelegateProfileWrite DelegatedPermission = "profile:write"
+	DelegateVoiceUse     DelegatedPermission = "voice:use"
+	DelegateHistoryRead  DelegatedPermission = "history:read"
+	DelegateManage       DelegatedPermission = "delegation:manage"
+	DelegateConsent      DelegatedPermission = "consent:manage"
+)
+
+// AllDelegatedPermissions is granted to the account that creates a profile.
+var AllDelegatedPermissions = []DelegatedPermission{
+	DelegateProfileRead, DelegateProfileWrite, DelegateVoiceUse, DelegateHistoryRead, DelegateManage, DelegateConsent,
+}
+
+func (p DelegatedPermission) IsValid() bool {
+	for _, known := range AllDelegatedPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Delegation lets a user act for a patient with the listed permissions.
+type Delegation struct {
+	bun.BaseModel `bun:"table:patient_delegations"`
+
+	ID          uuid.UUID             `bun:",pk,type:uuid,default:gen_random_uuid()" json:"id"`
+	PatientID   uuid.UUID             `bun:",notnull,type:uuid" json:"patient_id"`
+	UserID      uuid.UUID             `bun:",notnull,type:uuid" json:"user_id"`
+	Relation    Relation              `bun:",notnull" json:"relation"`
+	Permissions []DelegatedPermission `bun:",array,notnull" json:"permissions"`
+	GrantedBy   uuid.UUID             `bun:",notnull,type:uuid" json:"granted_by"`
+	CreatedAt   time.Time             `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+	RevokedAt   time.Time             `bun:",nullzero" json:"-"`
+}
+
+func (d *Delegation) Allows(perm DelegatedPermission) bool {
+	for _, p := range d.Permissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// ManagedProfile is a profile together with the caller's delegation.
+type ManagedProfile struct {
+	Profile     *Profile              `json:"profile"`
+	Relation    Relation              `json:"relation"`
+	Permissions []DelegatedPermission `json:"permissions"`
+}
+
+// Latin forms are optional; when omitted they are transliterated.
+type CreateProfileInput struct {
+	FirstName      string   `json:"first_name" validate:"required,name,min=2,max=50"`
+	LastName       string   `json:"last_name" validate:"omitempty,name,min=2,max=50"`
+	FirstNameLatin string   `json:"first_name_latin" validate:"omitempty,latinname,min=2,max=50"`
+	LastNameLatin  string   `json:"last_name_latin" validate:"omitempty,latinname,min=2,max=50"`
+	Language       string   `json:"language" validate:"required,alpha,len=2"`
+	Gender         string   `json:"gender" validate:"omitempty,oneof=female male other"`
+	BirthYear      int      `json:"birth_year" validate:"omitempty,gte=1900,lte=2100"`
+	Phone          string   `json:"phone" validate:"omitempty,max=20"`
+	Village        string   `json:"village" validate:"omitempty,max=100"`
+	Relation       Relation `json:"relation" validate:"required"`
+}
+
+type UpdateProfileInput struct {
+	FirstName      string `json:"first_name" validate:"required,name,min=2,max=50"`
+	LastName       string `json:"last_name" validate:"omitempty,name,min=2,max=50"`
+	FirstNameLatin string `json:"first_name_latin" validate:"omitempty,latinname,min=2,max=50"`
+	LastNameLatin  string `json:"last_name_latin" validate:"omitempty,latinname,min=2,max=50"`
+	Language       string `json:"language" validate:"required,alpha,len=2"`
+	Gender         string `json:"gender" validate:"omitempty,oneof=female male other"`
+	BirthYear      int    `json:"birth_year" validate:"omitempty,gte=1900,lte=2100"`
+	Phone          string `json:"phone" validate:"omitempty,max=20"`
+	Village        string `json:"village" validate:"omitempty,max=100"`
+}
+
+type AddDelegateInput struct {
+	Phone       string                `json:"phone" validate:"required,max=20"`
+	Relation    Relation              `json:"relation" validate:"required"`
+	Permissions []DelegatedPermission `json:"permissions" validate:"required,min=1,dive,required"`
+}
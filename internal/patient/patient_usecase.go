@@ -0,0 +1,19 @@
+package patient
+
+import (
+	"context"
+	"swasthAI/internal/patient/models"
+
+	"github.com/google/uuid"
+)
+
+type PatientUsecase interface {
+	ResolveActingFor(ctx context.Context, patientID uuid.UUID) (*ActingFor, error)
+	CreateProfile(ctx context.Context, input *models.CreateProfileInput) (*models.ManagedProfile, error)
+	ListProfiles(ctx context.Context, query string) ([]*models.ManagedProfile, error)
+	GetProfile(ctx context.Context) (*models.Profile, error)
+	UpdateProfile(ctx context.Context, input *models.UpdateProfileInput) (*models.Profile, error)
+	ListDelegates(ctx context.Context) ([]*models.Delegation, error)
+	AddDelegate(ctx context.Context, input *models.AddDelegateInput) (*models.Delegation, error)
+	RevokeDelegate(ctx context.Context, delegationID uuid.UUID) error
+}
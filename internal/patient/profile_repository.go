@@ -0,0 +1,16 @@
+package patient
+
+import (
+	"context"
+	"swasthAI/internal/patient/models"
+
+	"github.com/google/uuid"
+)
+
+type ProfileRepository interface {
+	CreateWithDelegation(ctx context.Context, profile *models.Profile, delegation *models.Delegation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Profile, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Profile, error)
+	SearchByIDs(ctx context.Context, ids []uuid.UUID, text, key string) ([]*models.Profile, error)
+	Update(ctx context.Context, profile *models.Profile) error
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"swasthAI/internal/patient/models"
+	"swasthAI/pkg/domain_errors"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type DelegationRepository struct {
+	db *bun.DB
+}
+
+func NewDelegationRepository(db *bun.DB) *DelegationRepository {
+	return &DelegationRepository{db: db}
+}
+
+func (r *DelegationRepository) Create(ctx context.Context, delegation *models.Delegation) error {
+	_, err := r.db.NewInsert().Model(delegation).Returning("*").Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "delegationRepo.Create.Insert")
+	}
+	return nil
+}
+
+func (r *DelegationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Delegation, error) {
+	delegation := new(models.Delegation)
+	err := r.db.NewSelect().Model(delegation).Where("id = ?", id).Where("revoked_at IS NULL").Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain_errors.ErrDelegationNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "delegationRepo.FindByID.Select")
+	}
+	return delegation, nil
+}
+
+// FindActive returns the user's unrevoked delegation for the patient.
+func (r *DelegationRepository) FindActive(ctx context.Context, patientID, userID uuid.UUID) (*models.Delegation, error) {
+	delegation := new(models.Delegation)
+	err := r.db.NewSelect().
+		Model(delegation).
+		Where("patient_id = ?", patientID).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain_errors.ErrDelegationNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "delegationRepo.FindActive.Select")
+	}
+	return delegation, nil
+}
+
+func (r *DelegationRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]*models.Delegation, error) {
+	var delegations []*models.Delegation
+	err := r.db.NewSelect().
+		Model(&delegations).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Order("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "delegationRepo.ListForUser.Select")
+	}
+	return delegations, nil
+}
+
+func (r *DelegationRepository) ListForPatient(ctx context.Context, patientID uuid.UUID) ([]*models.Delegation, error) {
+	var delegations []*models.Delegation
+	err := r.db.NewSelect().
+		Model(&delegations).
+		Where("patient_id = ?", patientID).
+		Where("revoked_at IS NULL").
+		Order("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "delegationRepo.ListForPatient.Select")
+	}
+	return delegations, nil
+}
+
+func (r *DelegationRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.NewUpdate().
+		Model((*models.Delegation)(nil)).
+		Set("revoked_at = ?", time.Now().UTC()).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "delegationRepo.Revoke.Update")
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain_errors.ErrDelegationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"swasthAI/internal/patient/models"
+	"swasthAI/pkg/domain_errors"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+type ProfileRepository struct {
+	db *bun.DB
+}
+
+func NewProfileRepository(db *bun.DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+// CreateWithDelegation stores a new profile and the creator's delegation in
+// one transaction, so a profile never exists without a manager.
+func (r *ProfileRepository) CreateWithDelegation(ctx context.Context, profile *models.Profile, delegation *models.Delegation) error {
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(profile).Returning("*").Exec(ctx); err != nil {
+			return err
+		}
+		delegation.PatientID = profile.ID
+		_, err := tx.NewInsert().Model(delegation).Returning("*").Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "profileRepo.CreateWithDelegation.Tx")
+	}
+	return nil
+}
+
+func (r *ProfileRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Profile, error) {
+	profile := new(models.Profile)
+	err := r.db.NewSelect().Model(profile).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain_errors.ErrPatientNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "profileRepo.FindByID.Select")
+	}
+	return profile, nil
+}
+
+func (r *ProfileRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Profile, error) {
+	var profiles []*models.Profile
+	if len(ids) == 0 {
+		return profiles, nil
+	}
+	err := r.db.NewSelect().Model(&profiles).Where("id IN (?)", bun.In(ids)).Order("full_name ASC").Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "profileRepo.FindByIDs.Select")
+	}
+	return profiles, nil
+}
+
+// SearchByIDs returns the profiles among ids whose native or Latin name
+// contains text, or whose name key contains key.
+func (r *ProfileRepository) SearchByIDs(ctx context.Context, ids []uuid.UUID, text, key string) ([]*models.Profile, error) {
+	var profiles []*models.Profile
+	if len(ids) == 0 {
+		return profiles, nil
+	}
+	pattern := "%" + likeEscaper.Replace(text) + "%"
+	err := r.db.NewSelect().
+		Model(&profiles).
+		Where("id IN (?)", bun.In(ids)).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			q = q.Where("full_name ILIKE ?", pattern).WhereOr("full_name_latin ILIKE ?", pattern)
+			if key != "" {
+				q = q.WhereOr("name_key LIKE ?", "%"+likeEscaper.Replace(key)+"%")
+			}
+			return q
+		}).
+		Order("full_name ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "profileRepo.SearchByIDs.Select")
+	}
+	return profiles, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *ProfileRepository) Update(ctx context.Context, profile *models.Profile) error {
+	res, err := r.db.NewUpdate().
+		Model(profile).
+		Column("first_name", "last_name", "full_name", "first_name_latin", "last_name_latin", "full_name_latin", "name_key", "language", "gender", "birth_year", "phone", "village", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "profileRepo.Update.Update")
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain_errors.ErrPatientNotFound
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"swasthAI/internal/auth"
+	"swasthAI/internal/patient"
+	"swasthAI/internal/patient/models"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/names"
+	"swasthAI/pkg/phone"
+
+	"github.com/google/uuid"
+)
+
+// maxQueryLength bounds patient name searches.
+const maxQueryLength = 100
+
+type PatientUsecase struct {
+	profileRepo    patient.ProfileRepository
+	delegationRepo patient.DelegationRepository
+	userRepo       auth.UserRepository
+	phones         *phone.Normalizer
+	logger         logger.Logger
+}
+
+func NewPatientUsecase(profileRepo patient.ProfileRepository, delegationRepo patient.DelegationRepository, userRepo auth.UserRepository, phones *phone.Normalizer, logger logger.Logger) *PatientUsecase {
+	return &PatientUsecase{profileRepo: profileRepo, delegationRepo: delegationRepo, userRepo: userRepo, phones: phones, logger: logger}
+}
+
+// ResolveActingFor checks that the caller holds a delegation for the patient.
+// Unknown patients and missing delegations look the same to the caller.
+func (uc *PatientUsecase) ResolveActingFor(ctx context.Context, patientID uuid.UUID) (*patient.ActingFor, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, appErrors.ErrUnauthorized
+	}
+
+	delegation, err := uc.delegationRepo.FindActive(ctx, patientID, identity.UserID)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrDelegationNotFound) {
+			return nil, domain_errors.ErrNotDelegated
+		}
+		uc.logger.Error("failed to find delegation (patientUC.ResolveActingFor.delegationRepo.FindActive)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+
+	return &patient.ActingFor{
+		PatientID:   delegation.PatientID,
+		Relation:    delegation.Relation,
+		Permissions: delegation.Permissions,
+	}, nil
+}
+
+// CreateProfile adds a patient managed by the caller, who gets every
+// delegated permission.
+func (uc *PatientUsecase) CreateProfile(ctx context.Context, input *models.CreateProfileInput) (*models.ManagedProfile, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if !input.Relation.IsValid() {
+		return nil, domain_errors.ErrInvalidRelation
+	}
+	phone, err := uc.optionalPhone(input.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &models.Profile{
+		FirstName:      input.FirstName,
+		LastName:       input.LastName,
+		FirstNameLatin: input.FirstNameLatin,
+		LastNameLatin:  input.LastNameLatin,
+		Language:       input.Language,
+		Gender:         input.Gender,
+		BirthYear:      input.BirthYear,
+		Phone:          phone,
+		Village:        input.Village,
+		CreatedBy:      identity.UserID,
+	}
+	profile.PrepareSave()
+	delegation := &models.Delegation{
+		UserID:      identity.UserID,
+		Relation:    input.Relation,
+		Permissions: slices.Clone(models.AllDelegatedPermissions),
+		GrantedBy:   identity.UserID,
+	}
+
+	if err := uc.profileRepo.CreateWithDelegation(ctx, profile, delegation); err != nil {
+		uc.logger.Error("failed to create patient (patientUC.CreateProfile.profileRepo.CreateWithDelegation)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+
+	return &models.ManagedProfile{
+		Profile:     profile,
+		Relation:    delegation.Relation,
+		Permissions: delegation.Permissions,
+	}, nil
+}
+
+// ListProfiles returns the patients the caller manages. A non-empty query
+// keeps those whose name matches it, typed in the name's own script or in
+// Latin letters with any common spelling.
+func (uc *PatientUsecase) ListProfiles(ctx context.Context, query string) ([]*models.ManagedProfile, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, appErrors.ErrUnauthorized
+	}
+
+	delegations, err := uc.delegationRepo.ListForUser(ctx, identity.UserID)
+	if err != nil {
+		uc.logger.Error("failed to list delegations (patientUC.ListProfiles.delegationRepo.ListForUser)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	byPatient := make(map[uuid.UUID]*models.Delegation, len(delegations))
+	ids := make([]uuid.UUID, 0, len(delegations))
+	for _, d := range delegations {
+		byPatient[d.PatientID] = d
+		ids = append(ids, d.PatientID)
+	}
+
+	var profiles []*models.Profile
+	if query = strings.TrimSpace(query); query == "" {
+		profiles, err = uc.profileRepo.FindByIDs(ctx, ids)
+	} else {
+		if utf8.RuneCountInString(query) > maxQueryLength {
+			return nil, appErrors.ErrInvalidInput
+		}
+		// a name typed in Latin letters is matched by its key, so that
+		// "Murugan" finds the patient written முருகன்
+		profiles, err = uc.profileRepo.SearchByIDs(ctx, ids, query, names.SearchKey(query))
+	}
+	if err != nil {
+		uc.logger.Error("failed to load patients (patientUC.ListProfiles.profileRepo)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+
+	managed := make([]*models.ManagedProfile, 0, len(profiles))
+	for _, p := range profiles {
+		d := byPatient[p.ID]
+		managed = append(managed, &models.ManagedProfile{Profile: p, Relation: d.Relation, Permissions: d.Permissions})
+	}
+	return managed, nil
+}
+
+// GetProfile returns the profile of the patient acted for.
+func (uc *PatientUsecase) GetProfile(ctx context.Context) (*models.Profile, error) {
+	actingFor, err := requireActingFor(ctx, models.DelegateProfileRead)
+	if err != nil {
+		return nil, err
+	}
+	return uc.findProfile(ctx, actingFor.PatientID)
+}
+
+func (uc *PatientUsecase) UpdateProfile(ctx context.Context, input *models.UpdateProfileInput) (*models.Profile, error) {
+	actingFor, err := requireActingFor(ctx, models.DelegateProfileWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	phone, err := uc.optionalPhone(input.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := uc.findProfile(ctx, actingFor.PatientID)
+	if err != nil {
+		return nil, err
+	}
+	profile.FirstName = input.FirstName
+	profile.LastName = input.LastName
+	profile.FirstNameLatin = input.FirstNameLatin
+	profile.LastNameLatin = input.LastNameLatin
+	profile.Language = input.Language
+	profile.Gender = input.Gender
+	profile.BirthYear = input.BirthYear
+	profile.Phone = phone
+	profile.Village = input.Village
+	profile.PrepareSave()
+
+	if err := uc.profileRepo.Update(ctx, profile); err != nil {
+		if errors.Is(err, domain_errors.ErrPatientNotFound) {
+			return nil, domain_errors.ErrPatientNotFound
+		}
+		uc.logger.Error("failed to update patient (patientUC.UpdateProfile.profileRepo.Update)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return profile, nil
+}
+
+// ListDelegates returns every account managing the patient acted for.
+func (uc *PatientUsecase) ListDelegates(ctx context.Context) ([]*models.Delegation, error) {
+	actingFor, err := requireActingFor(ctx, models.DelegateManage)
+	if err != nil {
+		return nil, err
+	}
+
+	delegations, err := uc.delegationRepo.ListForPatient(ctx, actingFor.PatientID)
+	if err != nil {
+		uc.logger.Error("failed to list delegations (patientUC.ListDelegates.delegationRepo.ListForPatient)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return delegations, nil
+}
+
+// AddDelegate lets another registered account act for the patient.
+func (uc *PatientUsecase) AddDelegate(ctx context.Context, input *models.AddDelegateInput) (*models.Delegation, error) {
+	actingFor, err := requireActingFor(ctx, models.DelegateManage)
+	if err != nil {
+		return nil, err
+	}
+	identity, _ := auth.FromContext(ctx)
+
+	if !input.Relation.IsValid() {
+		return nil, domain_errors.ErrInvalidRelation
+	}
+	perms := slices.Clone(input.Permissions)
+	slices.Sort(perms)
+	perms = slices.Compact(perms)
+	for _, perm := range perms {
+		if !perm.IsValid() {
+			return nil, domain_errors.ErrInvalidDelegatedPermission
+		}
+	}
+
+	phone, err := domain_errors.NormalizeUserPhone(uc.phones, input.Phone)
+	if err != nil {
+		return nil, err
+	}
+	delegate, err := uc.userRepo.FindByPhone(ctx, phone)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrUserNotFound) {
+			return nil, domain_errors.ErrUserNotFound
+		}
+		uc.logger.Error("failed to find delegate (patientUC.AddDelegate.userRepo.FindByPhone)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	if delegate == nil {
+		return nil, domain_errors.ErrUserNotFound
+	}
+
+	_, err = uc.delegationRepo.FindActive(ctx, actingFor.PatientID, delegate.ID)
+	if err == nil {
+		return nil, domain_errors.ErrDelegationExists
+	}
+	if !errors.Is(err, domain_errors.ErrDelegationNotFound) {
+		uc.logger.Error("failed to find delegation (patientUC.AddDelegate.delegationRepo.FindActive)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+
+	delegation := &models.Delegation{
+		PatientID:   actingFor.PatientID,
+		UserID:      delegate.ID,
+		Relation:    input.Relation,
+		Permissions: perms,
+		GrantedBy:   identity.UserID,
+	}
+	if err := uc.delegationRepo.Create(ctx, delegation); err != nil {
+		uc.logger.Error("failed to create delegation (patientUC.AddDelegate.delegationRepo.Create)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return delegation, nil
+}
+
+// RevokeDelegate removes an account's access to the patient. The last
+// delegation able to manage others cannot be revoked.
+func (uc *PatientUsecase) RevokeDelegate(ctx context.Context, delegationID uuid.UUID) error {
+	actingFor, err := requireActingFor(ctx, models.DelegateManage)
+	if err != nil {
+		return err
+	}
+
+	delegations, err := uc.delegationRepo.ListForPatient(ctx, actingFor.PatientID)
+	if err != nil {
+		uc.logger.Error("failed to list delegations (patientUC.RevokeDelegate.delegationRepo.ListForPatient)", "error", err)
+		return appErrors.ErrDatabase
+	}
+
+	var target *models.Delegation
+	managers := 0
+	for _, d := range delegations {
+		if d.ID == delegationID {
+			target = d
+		}
+		if d.Allows(models.DelegateManage) {
+			managers++
+		}
+	}
+	if target == nil {
+		return domain_errors.ErrDelegationNotFound
+	}
+	if target.Allows(models.DelegateManage) && managers == 1 {
+		return domain_errors.ErrLastManager
+	}
+
+	if err := uc.delegationRepo.Revoke(ctx, delegationID); err != nil {
+		if errors.Is(err, domain_errors.ErrDelegationNotFound) {
+			return domain_errors.ErrDelegationNotFound
+		}
+		uc.logger.Error("failed to revoke delegation (patientUC.RevokeDelegate.delegationRepo.Revoke)", "error", err)
+		return appErrors.ErrDatabase
+	}
+	return nil
+}
+
+func (uc *PatientUsecase) findProfile(ctx context.Context, id uuid.UUID) (*models.Profile, error) {
+	profile, err := uc.profileRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrPatientNotFound) {
+			return nil, domain_errors.ErrPatientNotFound
+		}
+		uc.logger.Error("failed to find patient (patientUC.findProfile.profileRepo.FindByID)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return profile, nil
+}
+
+// optionalPhone normalizes a patient's phone, which they may not have.
+func (uc *PatientUsecase) optionalPhone(input string) (string, error) {
+	if input == "" {
+		return "", nil
+	}
+	return domain_errors.NormalizeUserPhone(uc.phones, input)
+}
+
+// requireActingFor returns the patient acted for if the caller's delegation
+// allows perm.
+func requireActingFor(ctx context.Context, perm models.DelegatedPermission) (*patient.ActingFor, error) {
+	actingFor, ok := patient.ActingForFromContext(ctx)
+	if !ok {
+		return nil, domain_errors.ErrActingForRequired
+	}
+	if !actingFor.Allows(perm) {
+		return nil, domain_errors.ErrNotDelegated
+	}
+	return actingFor, nil
+}
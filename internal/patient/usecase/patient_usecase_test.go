@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"swasthAI/config"
+	"swasthAI/internal/auth"
+	authMocks "swasthAI/internal/auth/mocks"
+	authModels "swasthAI/internal/auth/models"
+	"swasthAI/internal/patient"
+	mocks "swasthAI/internal/patient/mocks"
+	"swasthAI/internal/patient/models"
+	"swasthAI/pkg/domain_errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/phone"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDeps struct {
+	profileRepo    *mocks.MockProfileRepository
+	delegationRepo *mocks.MockDelegationRepository
+	userRepo       *authMocks.MockUserRepository
+}
+
+func setupTest(t *testing.T) (*PatientUsecase, testDeps, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	deps := testDeps{
+		profileRepo:    mocks.NewMockProfileRepository(ctrl),
+		delegationRepo: mocks.NewMockDelegationRepository(ctrl),
+		userRepo:       authMocks.NewMockUserRepository(ctrl),
+	}
+	log, _ := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	phones, err := phone.New(config.Phone{DefaultRegion: "IN"})
+	require.NoError(t, err)
+	return NewPatientUsecase(deps.profileRepo, deps.delegationRepo, deps.userRepo, phones, *log), deps, ctrl
+}
+
+func callerContext() (context.Context, uuid.UUID) {
+	userID := uuid.New()
+	return auth.WithIdentity(context.Background(), &auth.Identity{UserID: userID}), userID
+}
+
+func actingForContext(perms ...models.DelegatedPermission) (context.Context, uuid.UUID) {
+	ctx, _ := callerContext()
+	patientID := uuid.New()
+	return patient.WithActingFor(ctx, &patient.ActingFor{
+		PatientID:   patientID,
+		Relation:    models.RelationCaregiver,
+		Permissions: perms,
+	}), patientID
+}
+
+func TestPatientUsecase_CreateProfile_GrantsCreatorEverything(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, userID := callerContext()
+	deps.profileRepo.EXPECT().CreateWithDelegation(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, p *models.Profile, d *models.Delegation) error {
+			assert.Equal(t, userID, p.CreatedBy)
+			assert.Equal(t, "Sita Devi", p.FullName)
+			assert.Equal(t, userID, d.UserID)
+			assert.ElementsMatch(t, models.AllDelegatedPermissions, d.Permissions)
+			return nil
+		})
+
+	managed, err := uc.CreateProfile(ctx, &models.CreateProfileInput{
+		FirstName: "Sita",
+		LastName:  "Devi",
+		Language:  "hi",
+		Relation:  models.RelationFamily,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.RelationFamily, managed.Relation)
+}
+
+func TestPatientUsecase_CreateProfile_TransliteratesName(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, _ := callerContext()
+	deps.profileRepo.EXPECT().CreateWithDelegation(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, p *models.Profile, d *models.Delegation) error {
+			assert.Equal(t, "सीता देवी", p.FullName)
+			assert.Equal(t, "Seeta Devi", p.FullNameLatin)
+			assert.Equal(t, "sit tevi", p.NameKey)
+			return nil
+		})
+
+	_, err := uc.CreateProfile(ctx, &models.CreateProfileInput{
+		FirstName: "सीता",
+		LastName:  "देवी",
+		Language:  "hi",
+		Relation:  models.RelationFamily,
+	})
+	require.NoError(t, err)
+}
+
+func TestPatientUsecase_ListProfiles_Search(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, userID := callerContext()
+	patientID := uuid.New()
+	delegation := &models.Delegation{PatientID: patientID, UserID: userID, Relation: models.RelationASHA}
+	deps.delegationRepo.EXPECT().ListForUser(ctx, userID).Return([]*models.Delegation{delegation}, nil)
+	deps.profileRepo.EXPECT().SearchByIDs(ctx, []uuid.UUID{patientID}, "Seetha", "sit").
+		Return([]*models.Profile{{ID: patientID, FirstName: "सीता"}}, nil)
+
+	managed, err := uc.ListProfiles(ctx, " Seetha ")
+	require.NoError(t, err)
+	require.Len(t, managed, 1)
+	assert.Equal(t, models.RelationASHA, managed[0].Relation)
+}
+
+func TestPatientUsecase_CreateProfile_InvalidRelation(t *testing.T) {
+	uc, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, _ := callerContext()
+	_, err := uc.CreateProfile(ctx, &models.CreateProfileInput{FirstName: "Sita", Relation: "neighbour"})
+	assert.ErrorIs(t, err, domain_errors.ErrInvalidRelation)
+}
+
+func TestPatientUsecase_ResolveActingFor_NotDelegated(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, userID := callerContext()
+	patientID := uuid.New()
+	deps.delegationRepo.EXPECT().FindActive(ctx, patientID, userID).Return(nil, domain_errors.ErrDelegationNotFound)
+
+	_, err := uc.ResolveActingFor(ctx, patientID)
+	assert.ErrorIs(t, err, domain_errors.ErrNotDelegated)
+}
+
+func TestPatientUsecase_GetProfile_RequiresActingFor(t *testing.T) {
+	uc, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, _ := callerContext()
+	_, err := uc.GetProfile(ctx)
+	assert.ErrorIs(t, err, domain_errors.ErrActingForRequired)
+
+	ctx, _ = actingForContext(models.DelegateVoiceUse)
+	_, err = uc.GetProfile(ctx)
+	assert.ErrorIs(t, err, domain_errors.ErrNotDelegated)
+}
+
+func TestPatientUsecase_AddDelegate_AlreadyDelegated(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, patientID := actingForContext(models.DelegateManage)
+	delegate := &authModels.User{ID: uuid.New(), Phone: "+919876543210"}
+	deps.userRepo.EXPECT().FindByPhone(ctx, delegate.Phone).Return(delegate, nil)
+	deps.delegationRepo.EXPECT().FindActive(ctx, patientID, delegate.ID).Return(&models.Delegation{}, nil)
+
+	_, err := uc.AddDelegate(ctx, &models.AddDelegateInput{
+		Phone:       "098765 43210",
+		Relation:    models.RelationASHA,
+		Permissions: []models.DelegatedPermission{models.DelegateVoiceUse},
+	})
+	assert.ErrorIs(t, err, domain_errors.ErrDelegationExists)
+}
+
+func TestPatientUsecase_RevokeDelegate_KeepsLastManager(t *testing.T) {
+	uc, deps, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx, patientID := actingForContext(models.DelegateManage)
+	manager := &models.Delegation{ID: uuid.New(), PatientID: patientID, Permissions: models.AllDelegatedPermissions}
+	helper := &models.Delegation{ID: uuid.New(), PatientID: patientID, Permissions: []models.DelegatedPermission{models.DelegateVoiceUse}}
+	deps.delegationRepo.EXPECT().ListForPatient(ctx, patientID).Return([]*models.Delegation{manager, helper}, nil).Times(2)
+	deps.delegationRepo.EXPECT().Revoke(ctx, helper.ID).Return(nil)
+
+	assert.ErrorIs(t, uc.RevokeDelegate(ctx, manager.ID), domain_errors.ErrLastManager)
+	assert.NoError(t, uc.RevokeDelegate(ctx, helper.ID))
+}
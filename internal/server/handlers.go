@@ -3,44 +3,298 @@ package server
 import (
 	"context"
 	"net/http"
+
+	abuseModels "swasthAI/internal/abuse/models"
+	abuseRepository "swasthAI/internal/abuse/repository"
+	abuseUsecase "swasthAI/internal/abuse/usecase"
+	adminUsecase "swasthAI/internal/admin/usecase"
+	auditModels "swasthAI/internal/audit/models"
+	auditRepository "swasthAI/internal/audit/repository"
+	auditUsecase "swasthAI/internal/audit/usecase"
 	"swasthAI/internal/auth/models"
 	"swasthAI/internal/auth/repository"
 	"swasthAI/internal/auth/usecase"
+	consentModels "swasthAI/internal/consent/models"
+	consentRepository "swasthAI/internal/consent/repository"
+	consentTextRegistry "swasthAI/internal/consent/texts"
+	consentUsecase "swasthAI/internal/consent/usecase"
+	healthModels "swasthAI/internal/health/models"
+	healthRepository "swasthAI/internal/health/repository"
+	healthUsecase "swasthAI/internal/health/usecase"
 	"swasthAI/internal/middleware"
+	otpSender "swasthAI/internal/otpchannel/sender"
+	patientModels "swasthAI/internal/patient/models"
+	patientRepository "swasthAI/internal/patient/repository"
+	patientUsecase "swasthAI/internal/patient/usecase"
+	smsModels "swasthAI/internal/sms/models"
+	smsRepository "swasthAI/internal/sms/repository"
+	smsSender "swasthAI/internal/sms/sender"
+	"swasthAI/internal/sms/templates"
+	smsUsecase "swasthAI/internal/sms/usecase"
+	voiceAIClient "swasthAI/internal/voice/aiclient"
+	voiceModels "swasthAI/internal/voice/models"
+	voiceRepository "swasthAI/internal/voice/repository"
+	voiceUsecase "swasthAI/internal/voice/usecase"
+	"swasthAI/pkg/jwtkeys"
+	"swasthAI/pkg/phone"
 
+	abuseHandler "swasthAI/internal/abuse/delivery/http"
+	adminHandler "swasthAI/internal/admin/delivery/http"
+	auditHandler "swasthAI/internal/audit/delivery/http"
 	authHandler "swasthAI/internal/auth/delivery/http"
+	consentHandler "swasthAI/internal/consent/delivery/http"
+	healthHandler "swasthAI/internal/health/delivery/http"
+	patientHandler "swasthAI/internal/patient/delivery/http"
+	smsHandler "swasthAI/internal/sms/delivery/http"
+	voiceHandler "swasthAI/internal/voice/delivery/http"
 
 	"github.com/labstack/echo/v4"
 )
 
-func (s *Server) MapHandlers(e *echo.Echo) error {
+var auditAppendOnlySQL = []string{
+	`CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+	BEGIN
+		RAISE EXCEPTION 'audit_events is append-only';
+	END
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events`,
+	`CREATE TRIGGER audit_events_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_events
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only()`,
+}
+
+// MapHandlers wires the services and routes onto e and starts the
+// background workers, which stop when ctx is cancelled.
+func (s *Server) MapHandlers(ctx context.Context, e *echo.Echo) error {
+	ipExtractor, err := middleware.IPExtractor(s.cfg.Server)
+	if err != nil {
+		return err
+	}
+	e.IPExtractor = ipExtractor
+
 	//init repos
 	authRepo := repository.NewUserRepository(s.db, *s.logger)
 	otpRepo := repository.NewOTPRepository(s.db)
+	refreshRepo := repository.NewRefreshTokenRepository(s.db)
+	sessionRepo := repository.NewLoginSessionRepository(s.db)
+	erasureRepo := repository.NewErasureRepository(s.db)
+	outboxRepo := smsRepository.NewOutboxRepository(s.db)
+	profileRepo := patientRepository.NewProfileRepository(s.db)
+	delegationRepo := patientRepository.NewDelegationRepository(s.db)
+	consentRepo := consentRepository.NewConsentRepository(s.db)
+	healthRepo := healthRepository.NewHealthProfileRepository(s.db)
+	blockRepo := abuseRepository.NewBlockRepository(s.db)
+	auditRepo := auditRepository.NewAuditRepository(s.db)
+	counterStore, err := abuseRepository.NewCounterStore(s.cfg.Abuse, s.db)
+	if err != nil {
+		return err
+	}
+	voiceSessionRepo, err := voiceRepository.NewSessionRepository(s.cfg, s.db)
+	if err != nil {
+		return err
+	}
+
+	//init token keys
+	keys, err := jwtkeys.New(s.cfg.JWT)
+	if err != nil {
+		return err
+	}
+
+	//init phone number rules
+	phones, err := phone.New(s.cfg.Phone)
+	if err != nil {
+		return err
+	}
+
+	//init sms gateways
+	sender, err := smsSender.NewFromConfig(s.cfg.SMS, s.logger)
+	if err != nil {
+		return err
+	}
+	smsTemplates, err := templates.NewRegistry(s.cfg.SMS)
+	if err != nil {
+		return err
+	}
+
+	//init consent texts
+	consentTexts, err := consentTextRegistry.NewRegistry()
+	if err != nil {
+		return err
+	}
 
 	//init usecases
-	authUC := usecase.NewAuthUsecase(authRepo, otpRepo, *s.cfg, *s.logger)
+	smsUC := smsUsecase.NewSMSUsecase(outboxRepo, sender, smsTemplates, s.cfg.SMS.Outbox, s.logger)
+	auditUC := auditUsecase.NewAuditUsecase(auditRepo, *s.logger)
+	otpRouter, err := otpSender.NewFromConfig(s.cfg.OTP.Channels, smsUC)
+	if err != nil {
+		return err
+	}
+	abuseUC := abuseUsecase.NewAbuseUsecase(counterStore, blockRepo, keys, s.cfg.Abuse, *s.logger)
+	adminUC := adminUsecase.NewAdminUsecase(authRepo, refreshRepo, sessionRepo, auditUC, *s.logger)
+	consentUC := consentUsecase.NewConsentUsecase(consentRepo, consentTexts, *s.logger)
+	healthUC := healthUsecase.NewHealthUsecase(healthRepo, auditUC, *s.logger)
+	patientUC := patientUsecase.NewPatientUsecase(profileRepo, delegationRepo, authRepo, phones, *s.logger)
+	authUC := usecase.NewAuthUsecase(authRepo, otpRepo, refreshRepo, sessionRepo, erasureRepo, voiceSessionRepo, otpRouter, abuseUC, auditUC, keys, phones, *s.cfg, *s.logger)
+	if err := voiceUsecase.ValidateConfig(s.cfg.Voice); err != nil {
+		return err
+	}
+	aiClient := voiceAIClient.New(s.cfg.Voice.AI, s.logger)
+	voiceUC := voiceUsecase.NewVoiceUsecase(s.logger, voiceSessionRepo, consentUC, healthUC, aiClient, s.cfg)
 
 	//init handlers
-	authHandler := authHandler.NewHandler(authUC, s.logger, s.cfg)
+	authHandler := authHandler.NewHandler(authUC, keys, s.logger, s.cfg)
+	smsHandler := smsHandler.NewHandler(smsUC, s.logger, s.cfg)
+	adminHandler := adminHandler.NewHandler(adminUC, s.logger, s.cfg)
+	patientHandler := patientHandler.NewHandler(patientUC, s.logger, s.cfg)
+	consentHandler := consentHandler.NewHandler(consentUC, s.logger, s.cfg)
+	healthHandler := healthHandler.NewHandler(healthUC, s.logger, s.cfg)
+	abuseHandler := abuseHandler.NewHandler(abuseUC, s.logger, s.cfg)
+	auditHandler := auditHandler.NewHandler(auditUC, s.logger, s.cfg)
+	voiceHandler := voiceHandler.NewHandler(voiceUC, s.logger, s.cfg)
 
 	//create tables
-	ctx := context.Background()
 	if _, err := s.db.NewCreateTable().Model((*models.User)(nil)).IfNotExists().Exec(ctx); err != nil {
 		s.logger.Error(err)
 	}
+	if _, err := s.db.NewCreateIndex().Model((*models.User)(nil)).Index("users_purge_at_idx").Column("purge_at").Where("purge_at IS NOT NULL").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateTable().Model((*models.ErasureReceipt)(nil)).IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateTable().Model((*models.RoleChange)(nil)).IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*models.RoleChange)(nil)).Index("role_changes_user_id_idx").Column("user_id", "created_at").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
 	if _, err := s.db.NewCreateTable().Model((*models.OTP)(nil)).IfNotExists().Exec(ctx); err != nil {
 		s.logger.Error(err)
 	}
+	if _, err := s.db.NewCreateIndex().Model((*models.OTP)(nil)).Index("otps_phone_created_at_idx").Column("phone", "created_at").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateTable().Model((*models.RefreshToken)(nil)).IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*models.RefreshToken)(nil)).Index("refresh_tokens_family_id_idx").Column("family_id").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*models.RefreshToken)(nil)).Index("refresh_tokens_user_id_idx").Column("user_id").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateTable().Model((*models.LoginSession)(nil)).IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*models.LoginSession)(nil)).Index("user_sessions_user_id_idx").Column("user_id").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateTable().Model((*patientModels.Profile)(nil)).IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateTable().Model((*patientModels.Delegation)(nil)).IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*patientModels.Delegation)(nil)).Index("patient_delegations_active_idx").Unique().Column("patient_id", "user_id").Where("revoked_at IS NULL").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*patientModels.Delegation)(nil)).Index("patient_delegations_user_id_idx").Column("user_id").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateTable().Model((*consentModels.ConsentRecord)(nil)).IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*consentModels.ConsentRecord)(nil)).Index("consent_records_subject_idx").Column("subject_id", "purpose", "created_at").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	// the unique (subject_id, version) constraint also indexes latest-version lookups
+	if _, err := s.db.NewCreateTable().Model((*healthModels.HealthProfile)(nil)).IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateTable().Model((*abuseModels.Block)(nil)).IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*abuseModels.Block)(nil)).Index("abuse_blocks_subject_idx").Column("dimension", "value", "expires_at").Where("lifted_at IS NULL").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if s.cfg.Abuse.Store == "postgres" {
+		if _, err := s.db.NewCreateTable().Model((*abuseModels.Counter)(nil)).IfNotExists().Exec(ctx); err != nil {
+			s.logger.Error(err)
+		}
+		if _, err := s.db.NewCreateIndex().Model((*abuseModels.Counter)(nil)).Index("abuse_counters_expires_at_idx").Column("expires_at").IfNotExists().Exec(ctx); err != nil {
+			s.logger.Error(err)
+		}
+	}
+	if s.cfg.Voice.Store == "postgres" {
+		if _, err := s.db.NewCreateTable().Model((*voiceModels.VoiceSession)(nil)).IfNotExists().Exec(ctx); err != nil {
+			s.logger.Error(err)
+		}
+		if _, err := s.db.NewCreateIndex().Model((*voiceModels.VoiceSession)(nil)).Index("voice_sessions_status_idx").Column("status").IfNotExists().Exec(ctx); err != nil {
+			s.logger.Error(err)
+		}
+		if _, err := s.db.NewCreateIndex().Model((*voiceModels.VoiceSession)(nil)).Index("voice_sessions_user_id_status_idx").Column("user_id", "status").IfNotExists().Exec(ctx); err != nil {
+			s.logger.Error(err)
+		}
+	}
+	if _, err := s.db.NewCreateTable().Model((*auditModels.Event)(nil)).IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*auditModels.Event)(nil)).Index("audit_events_actor_id_idx").Column("actor_id", "seq").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*auditModels.Event)(nil)).Index("audit_events_subject_id_idx").Column("subject_id", "seq").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	// the table only ever grows; updates and deletes are refused
+	for _, stmt := range auditAppendOnlySQL {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			s.logger.Error(err)
+		}
+	}
+	if _, err := s.db.NewCreateTable().Model((*smsModels.OutboxMessage)(nil)).IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*smsModels.OutboxMessage)(nil)).Index("sms_outbox_status_next_attempt_idx").Column("status", "next_attempt_at").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+	if _, err := s.db.NewCreateIndex().Model((*smsModels.OutboxMessage)(nil)).Index("sms_outbox_provider_message_idx").Column("provider", "provider_message_id").IfNotExists().Exec(ctx); err != nil {
+		s.logger.Error(err)
+	}
+
+	//start background workers
+	s.goWorker(ctx, smsUC.Run)
+	s.goWorker(ctx, authUC.RunPurge)
+	s.goWorker(ctx, abuseUC.Run)
+	s.goWorker(ctx, voiceUC.Run)
+	s.goWorker(ctx, auditUC.Run)
+	s.goWorker(ctx, aiClient.Run)
 
 	//init middleware
-	mw := middleware.NewMiddlewareManager(authUC, *s.cfg, s.logger)
+	mw := middleware.NewMiddlewareManager(authUC, patientUC, auditUC, keys, *s.cfg, s.logger)
+	e.Use(mw.RequestIDMiddleware)
 	e.Use(mw.LoggerMiddleware)
+	e.Use(mw.ClientInfoMiddleware)
 	v1 := e.Group("/api/v1")
 
 	health := v1.Group("/health")
 	authGroup := v1.Group("/auth")
 	authHandler.MapAuthRoutes(authGroup, *mw)
+	authHandler.MapWellKnownRoutes(e)
+	smsGroup := v1.Group("/sms")
+	smsHandler.MapSMSRoutes(smsGroup)
+	adminGroup := v1.Group("/admin")
+	adminHandler.MapAdminRoutes(adminGroup, *mw)
+	auditGroup := v1.Group("/admin/audit")
+	auditHandler.MapAuditRoutes(auditGroup, *mw)
+	abuseGroup := v1.Group("/admin/abuse/blocks")
+	abuseHandler.MapAbuseRoutes(abuseGroup, *mw)
+	patientGroup := v1.Group("/patients")
+	patientHandler.MapPatientRoutes(patientGroup, *mw)
+	consentGroup := v1.Group("/consents")
+	consentHandler.MapConsentRoutes(consentGroup, *mw)
+	healthProfileGroup := v1.Group("/user/health-profile")
+	healthHandler.MapHealthRoutes(healthProfileGroup, *mw)
+	voiceGroup := v1.Group("/voice")
+	voiceHandler.MapVoiceRoutes(voiceGroup, *mw)
 
 	health.GET("", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, map[string]string{"status": "OK"})
@@ -1,7 +1,14 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
 	"swasthAI/config"
+	"swasthAI/migrations"
 	"swasthAI/pkg/logger"
 
 	"github.com/labstack/echo/v4"
@@ -13,13 +20,62 @@ type Server struct {
 	logger *logger.Logger
 	db     *bun.DB
 	cfg    *config.Config
+
+	workers sync.WaitGroup
 }
 
 func NewServer(logger *logger.Logger, db *bun.DB, cfg *config.Config) *Server {
 	return &Server{e: echo.New(), logger: logger, db: db, cfg: cfg}
 }
 
-func (s *Server) Run() error {
-	s.MapHandlers(s.e)
-	return s.e.Start(s.cfg.Server.Port)
+// Run serves until ctx is cancelled. It then stops accepting connections,
+// lets requests in flight finish, and only after that stops the background
+// workers and waits for them, so that work queued by the last requests is
+// not lost.
+func (s *Server) Run(ctx context.Context) error {
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer func() {
+		stopWorkers()
+		s.workers.Wait()
+	}()
+	// existing tables are upgraded before the missing ones are created
+	group, err := migrations.Run(ctx, s.db)
+	if err != nil {
+		return err
+	}
+	if !group.IsZero() {
+		s.logger.Infof("applied migrations %s", group)
+	}
+	if err := s.MapHandlers(workerCtx, s.e); err != nil {
+		return err
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- s.e.Start(s.cfg.Server.Port) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(s.cfg.Server.ShutdownTimeout)*time.Second)
+	defer cancel()
+	if err := s.e.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// goWorker runs a background worker until ctx is cancelled; Run waits for
+// it before returning.
+func (s *Server) goWorker(ctx context.Context, run func(context.Context)) {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		run(ctx)
+	}()
 }
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"swasthAI/config"
+	"swasthAI/internal/sms"
+	"swasthAI/internal/sms/models"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/http_errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	uc     sms.SMSUsecase
+	logger *logger.Logger
+	Cfg    *config.Config
+}
+
+func NewHandler(uc sms.SMSUsecase, logger *logger.Logger, cfg *config.Config) *Handler {
+	return &Handler{uc: uc, logger: logger, Cfg: cfg}
+}
+
+// DeliveryReport receives carrier delivery receipts from a provider. The
+// provider must present the shared callback token as a header or query param.
+func (h *Handler) DeliveryReport(c echo.Context) error {
+	token := c.Request().Header.Get("X-Callback-Token")
+	if token == "" {
+		token = c.QueryParam("token")
+	}
+	expected := h.Cfg.SMS.CallbackToken
+	if expected == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		return http_errors.Send(c, domain_errors.ErrInvalidCallbackToken)
+	}
+
+	var report models.DeliveryReport
+	if err := utils.ReadRequest(c, &report); err != nil {
+		h.logger.Error("failed to read delivery report", "error", err)
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+	report.Provider = c.Param("provider")
+
+	if err := h.uc.HandleDeliveryReport(c.Request().Context(), &report); err != nil {
+		h.logger.Error("failed to handle delivery report", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
@@ -0,0 +1,7 @@
+package http
+
+import "github.com/labstack/echo/v4"
+
+func (h *Handler) MapSMSRoutes(sms *echo.Group) {
+	sms.POST("/delivery-report/:provider", h.DeliveryReport)
+}
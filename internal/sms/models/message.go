@@ -0,0 +1,16 @@
+package models
+
+// Message is a single outbound SMS.
+type Message struct {
+	To         string `json:"to" validate:"required,e164"`
+	Body       string `json:"body" validate:"required"`
+	SenderID   string `json:"sender_id,omitempty"`
+	TemplateID string `json:"template_id,omitempty"`
+	EntityID   string `json:"entity_id,omitempty"`
+}
+
+// SendResult identifies the provider that accepted a message.
+type SendResult struct {
+	Provider  string `json:"provider"`
+	MessageID string `json:"message_id,omitempty"`
+}
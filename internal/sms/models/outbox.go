@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"   // waiting for its next attempt
+	OutboxSending   OutboxStatus = "sending"   // claimed by a dispatcher
+	OutboxSent      OutboxStatus = "sent"      // accepted by a provider
+	OutboxDelivered OutboxStatus = "delivered" // delivery confirmed by the carrier
+	OutboxFailed    OutboxStatus = "failed"    // gave up or carrier reported failure
+	OutboxExpired   OutboxStatus = "expired"   // no longer worth sending, e.g. a stale OTP
+)
+
+// OutboxMessage is a persisted outbound SMS. Every message is written here
+// before it is handed to a provider so failed sends can be retried.
+type OutboxMessage struct {
+	bun.BaseModel `bun:"table:sms_outbox"`
+
+	ID                uuid.UUID    `bun:",pk,type:uuid,default:uuid_generate_v4()" json:"id"`
+	Kind              Kind         `bun:",notnull" json:"kind"`
+	Language          string       `bun:",notnull" json:"language"`
+	To                string       `bun:"recipient,notnull" json:"to"`
+	SenderID          string       `bun:",notnull" json:"sender_id"`
+	TemplateID        string       `bun:",notnull" json:"template_id"`
+	EntityID          string       `bun:",notnull" json:"entity_id"`
+	Body              string       `bun:",notnull" json:"-"`
+	Status            OutboxStatus `bun:",notnull" json:"status"`
+	Attempts          int          `bun:",notnull,default:0" json:"attempts"`
+	NextAttemptAt     time.Time    `bun:",notnull" json:"next_attempt_at"`
+	ExpiresAt         time.Time    `bun:",nullzero" json:"expires_at,omitempty"`
+	LastError         string       `bun:",nullzero" json:"last_error,omitempty"`
+	Provider          string       `bun:",nullzero" json:"provider,omitempty"`
+	ProviderMessageID string       `bun:",nullzero" json:"provider_message_id,omitempty"`
+	SentAt            time.Time    `bun:",nullzero" json:"sent_at,omitempty"`
+	DeliveredAt       time.Time    `bun:",nullzero" json:"delivered_at,omitempty"`
+	CreatedAt         time.Time    `bun:",nullzero,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt         time.Time    `bun:",nullzero,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// Message converts the outbox entry into what providers send.
+func (m *OutboxMessage) Message() *Message {
+	return &Message{
+		To:         m.To,
+		Body:       m.Body,
+		SenderID:   m.SenderID,
+		TemplateID: m.TemplateID,
+		EntityID:   m.EntityID,
+	}
+}
+
+// SendRequest asks for a templated message to be sent.
+type SendRequest struct {
+	To       string
+	Kind     Kind
+	Language string
+	Vars     map[string]string
+	TTL      time.Duration // zero means the message never expires
+}
+
+// DeliveryReport is a carrier delivery receipt forwarded by a provider.
+type DeliveryReport struct {
+	Provider    string    `json:"-"`
+	MessageID   string    `json:"message_id" form:"message_id" validate:"required"`
+	Status      string    `json:"status" form:"status" validate:"required"`
+	Error       string    `json:"error,omitempty" form:"error"`
+	DeliveredAt time.Time `json:"delivered_at,omitempty" form:"delivered_at"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind identifies the purpose of a message. Every kind has one DLT
+// registered template per supported language.
+type Kind string
+
+const (
+	KindOTP                 Kind = "otp"
+	KindAppointmentReminder Kind = "appointment_reminder"
+	KindReferral            Kind = "referral"
+)
+
+// DLTVariable is the placeholder TRAI DLT uses for variable content.
+const DLTVariable = "{#var#}"
+
+// redacted replaces secret variables in the stored copy of a message.
+const redacted = "******"
+
+// Template is a DLT registered message body. Variables names the values
+// substituted, in order, for each {#var#} placeholder in Body. Secrets are
+// the variables that must never be stored, such as an OTP.
+type Template struct {
+	Kind          Kind     `json:"kind"`
+	Language      string   `json:"language"`
+	DLTTemplateID string   `json:"dlt_template_id"`
+	EntityID      string   `json:"entity_id"`
+	SenderID      string   `json:"sender_id"`
+	Body          string   `json:"body"`
+	Variables     []string `json:"variables"`
+	Secrets       []string `json:"secrets,omitempty"`
+}
+
+// Render fills the placeholders from vars. Every variable must be present.
+func (t *Template) Render(vars map[string]string) (string, error) {
+	parts := strings.Split(t.Body, DLTVariable)
+	if len(parts)-1 != len(t.Variables) {
+		return "", fmt.Errorf("template %s/%s: %d placeholders but %d variables", t.Kind, t.Language, len(parts)-1, len(t.Variables))
+	}
+
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for i, name := range t.Variables {
+		v, ok := vars[name]
+		if !ok {
+			return "", fmt.Errorf("template %s/%s: missing variable %q", t.Kind, t.Language, name)
+		}
+		b.WriteString(v)
+		b.WriteString(parts[i+1])
+	}
+	return b.String(), nil
+}
+
+// Redact returns a copy of vars with the secret variables masked, to render
+// the body that is stored.
+func (t *Template) Redact(vars map[string]string) map[string]string {
+	if len(t.Secrets) == 0 {
+		return vars
+	}
+	out := make(map[string]string, len(vars))
+	for k, v := range vars {
+		out[k] = v
+	}
+	for _, name := range t.Secrets {
+		if _, ok := out[name]; ok {
+			out[name] = redacted
+		}
+	}
+	return out
+}
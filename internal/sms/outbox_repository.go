@@ -0,0 +1,18 @@
+package sms
+
+import (
+	"context"
+	"swasthAI/internal/sms/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxRepository interface {
+	Create(ctx context.Context, msg *models.OutboxMessage) error
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error)
+	MarkSent(ctx context.Context, id uuid.UUID, provider, providerMessageID string) error
+	MarkRetry(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id uuid.UUID, status models.OutboxStatus, lastError string) error
+	UpdateDeliveryStatus(ctx context.Context, provider, providerMessageID string, status models.OutboxStatus, at time.Time, lastError string) error
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"swasthAI/internal/sms/models"
+	"swasthAI/pkg/domain_errors"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+// claimLease is how long a claimed message may stay in "sending" before
+// another dispatcher assumes the claimer died and picks it up again.
+const claimLease = 2 * time.Minute
+
+type OutboxRepository struct {
+	db *bun.DB
+}
+
+func NewOutboxRepository(db *bun.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) Create(ctx context.Context, msg *models.OutboxMessage) error {
+	_, err := r.db.NewInsert().Model(msg).Returning("*").Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "outboxRepo.Create.Insert")
+	}
+	return nil
+}
+
+// ClaimDue locks up to limit messages that are due, marks them as sending and
+// counts the attempt. Rows locked by another dispatcher are skipped.
+func (r *OutboxRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error) {
+	var msgs []models.OutboxMessage
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.NewSelect().
+			Model(&msgs).
+			Where("(status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at <= ?)",
+				models.OutboxPending, now, models.OutboxSending, now.Add(-claimLease)).
+			OrderExpr("next_attempt_at ASC").
+			Limit(limit).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+		if err != nil || len(msgs) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(msgs))
+		for i := range msgs {
+			ids[i] = msgs[i].ID
+			msgs[i].Status = models.OutboxSending
+			msgs[i].Attempts++
+			msgs[i].UpdatedAt = now
+		}
+		_, err = tx.NewUpdate().
+			Model((*models.OutboxMessage)(nil)).
+			Set("status = ?", models.OutboxSending).
+			Set("attempts = attempts + 1").
+			Set("updated_at = ?", now).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "outboxRepo.ClaimDue.Tx")
+	}
+	return msgs, nil
+}
+
+func (r *OutboxRepository) MarkSent(ctx context.Context, id uuid.UUID, provider, providerMessageID string) error {
+	now := time.Now().UTC()
+	_, err := r.db.NewUpdate().
+		Model((*models.OutboxMessage)(nil)).
+		Set("status = ?", models.OutboxSent).
+		Set("provider = ?", provider).
+		Set("provider_message_id = NULLIF(?, '')", providerMessageID).
+		Set("last_error = NULL").
+		Set("sent_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "outboxRepo.MarkSent.Update")
+	}
+	return nil
+}
+
+func (r *OutboxRepository) MarkRetry(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.OutboxMessage)(nil)).
+		Set("status = ?", models.OutboxPending).
+		Set("next_attempt_at = ?", nextAttemptAt).
+		Set("last_error = ?", lastError).
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "outboxRepo.MarkRetry.Update")
+	}
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, status models.OutboxStatus, lastError string) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.OutboxMessage)(nil)).
+		Set("status = ?", status).
+		Set("last_error = NULLIF(?, '')", lastError).
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "outboxRepo.MarkFailed.Update")
+	}
+	return nil
+}
+
+func (r *OutboxRepository) UpdateDeliveryStatus(ctx context.Context, provider, providerMessageID string, status models.OutboxStatus, at time.Time, lastError string) error {
+	q := r.db.NewUpdate().
+		Model((*models.OutboxMessage)(nil)).
+		Set("status = ?", status).
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("provider = ?", provider).
+		Where("provider_message_id = ?", providerMessageID)
+	if status == models.OutboxDelivered {
+		q = q.Set("delivered_at = ?", at)
+	}
+	if lastError != "" {
+		q = q.Set("last_error = ?", lastError)
+	}
+
+	res, err := q.Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "outboxRepo.UpdateDeliveryStatus.Update")
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain_errors.ErrSMSMessageNotFound
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ProviderError is returned when a gateway rejects or fails to accept a message.
+type ProviderError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+	Retryable  bool
+	Err        error
+}
+
+func (e *ProviderError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("sms provider %s: %v", e.Provider, e.Err)
+	}
+	return fmt.Sprintf("sms provider %s: status %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether sending again to the same provider may succeed.
+// Transport errors are retryable unless the caller's context is done.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var pErr *ProviderError
+	if errors.As(err, &pErr) {
+		return pErr.Retryable
+	}
+	return true
+}
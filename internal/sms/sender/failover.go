@@ -0,0 +1,90 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"swasthAI/internal/sms"
+	"swasthAI/internal/sms/models"
+	"swasthAI/pkg/logger"
+)
+
+type provider struct {
+	sender     sms.SMSSender
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+}
+
+// FailoverSender tries each provider in order, retrying retryable errors
+// with exponential backoff before moving on to the next provider.
+type FailoverSender struct {
+	providers []provider
+	logger    *logger.Logger
+}
+
+func NewFailoverSender(logger *logger.Logger) *FailoverSender {
+	return &FailoverSender{logger: logger}
+}
+
+// Add appends a provider. A zero timeout leaves the caller's deadline in place.
+func (f *FailoverSender) Add(s sms.SMSSender, timeout time.Duration, maxRetries int, backoff time.Duration) {
+	f.providers = append(f.providers, provider{sender: s, timeout: timeout, maxRetries: maxRetries, backoff: backoff})
+}
+
+func (f *FailoverSender) Name() string {
+	return "failover"
+}
+
+func (f *FailoverSender) Send(ctx context.Context, msg *models.Message) (*models.SendResult, error) {
+	if len(f.providers) == 0 {
+		return nil, errors.New("no sms providers configured")
+	}
+
+	var errs []error
+	for _, p := range f.providers {
+		res, err := f.sendWithRetry(ctx, p, msg)
+		if err == nil {
+			return res, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+		f.logger.Warn("sms provider failed, trying next", "provider", p.sender.Name(), "error", err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (f *FailoverSender) sendWithRetry(ctx context.Context, p provider, msg *models.Message) (*models.SendResult, error) {
+	var err error
+	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(p.backoff << (attempt - 1)):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		var res *models.SendResult
+		res, err = f.sendOnce(ctx, p, msg)
+		if err == nil {
+			return res, nil
+		}
+		if !IsRetryable(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+func (f *FailoverSender) sendOnce(ctx context.Context, p provider, msg *models.Message) (*models.SendResult, error) {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	return p.sender.Send(ctx, msg)
+}
@@ -0,0 +1,80 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/sms/models"
+	"swasthAI/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger(t *testing.T) *logger.Logger {
+	log, err := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	require.NoError(t, err)
+	return log
+}
+
+func TestHTTPSender_Send(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("authkey"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Write([]byte(`{"request_id":"req-1"}`))
+	}))
+	defer srv.Close()
+
+	s := NewHTTPSender(config.SMSProvider{Name: "msg91", URL: srv.URL, APIKey: "secret", AuthHeader: "authkey", SenderID: "SWASTH"})
+	res, err := s.Send(context.Background(), &models.Message{To: "+919876543210", Body: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", res.MessageID)
+	assert.Equal(t, "919876543210", got["to"])
+	assert.Equal(t, "SWASTH", got["sender"])
+}
+
+func TestFailoverSender_RetriesThenFailsOver(t *testing.T) {
+	var primaryCalls atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+
+	backup := NewFakeSender("backup")
+	f := NewFailoverSender(testLogger(t))
+	f.Add(NewHTTPSender(config.SMSProvider{Name: "primary", URL: primary.URL}), time.Second, 2, time.Millisecond)
+	f.Add(backup, time.Second, 0, 0)
+
+	res, err := f.Send(context.Background(), &models.Message{To: "+919876543210", Body: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "backup", res.Provider)
+	assert.Equal(t, int32(3), primaryCalls.Load())
+	assert.Len(t, backup.Messages(), 1)
+}
+
+func TestFailoverSender_NoRetryOnClientError(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	f := NewFailoverSender(testLogger(t))
+	f.Add(NewHTTPSender(config.SMSProvider{Name: "primary", URL: srv.URL}), time.Second, 3, time.Millisecond)
+
+	_, err := f.Send(context.Background(), &models.Message{To: "+919876543210", Body: "hello"})
+	var pErr *ProviderError
+	require.True(t, errors.As(err, &pErr))
+	assert.Equal(t, http.StatusBadRequest, pErr.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
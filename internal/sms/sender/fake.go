@@ -0,0 +1,50 @@
+package sender
+
+import (
+	"context"
+	"sync"
+
+	"swasthAI/internal/sms/models"
+
+	"github.com/google/uuid"
+)
+
+// FakeSender records messages in memory. It is meant for tests.
+type FakeSender struct {
+	name     string
+	mu       sync.Mutex
+	messages []models.Message
+	err      error
+}
+
+func NewFakeSender(name string) *FakeSender {
+	return &FakeSender{name: name}
+}
+
+func (s *FakeSender) Name() string {
+	return s.name
+}
+
+func (s *FakeSender) Send(ctx context.Context, msg *models.Message) (*models.SendResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.messages = append(s.messages, *msg)
+	return &models.SendResult{Provider: s.name, MessageID: uuid.NewString()}, nil
+}
+
+// FailWith makes every following Send return err. A nil err restores success.
+func (s *FakeSender) FailWith(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// Messages returns a copy of every message sent so far.
+func (s *FakeSender) Messages() []models.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.Message(nil), s.messages...)
+}
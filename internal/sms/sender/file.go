@@ -0,0 +1,51 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"swasthAI/internal/sms/models"
+
+	"github.com/google/uuid"
+)
+
+// FileSender writes messages to a file or stdout instead of delivering them.
+// It is meant for local development.
+type FileSender struct {
+	name string
+	mu   sync.Mutex
+	w    io.Writer
+}
+
+// NewFileSender appends to the file at path, or writes to stdout if path is empty.
+func NewFileSender(name, path string) (*FileSender, error) {
+	if path == "" {
+		return &FileSender{name: name, w: os.Stdout}, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSender{name: name, w: f}, nil
+}
+
+func (s *FileSender) Name() string {
+	return s.name
+}
+
+func (s *FileSender) Send(ctx context.Context, msg *models.Message) (*models.SendResult, error) {
+	id := uuid.NewString()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := fmt.Fprintf(s.w, "[SMS] %s id=%s to=%s sender=%s template=%s body=%q\n",
+		time.Now().UTC().Format(time.RFC3339), id, msg.To, msg.SenderID, msg.TemplateID, msg.Body)
+	if err != nil {
+		return nil, &ProviderError{Provider: s.name, Err: err}
+	}
+	return &models.SendResult{Provider: s.name, MessageID: id}, nil
+}
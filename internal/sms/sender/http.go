@@ -0,0 +1,139 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/sms/models"
+)
+
+// HTTPSender posts messages to a REST gateway such as MSG91 or Gupshup.
+type HTTPSender struct {
+	name       string
+	url        string
+	format     string
+	apiKey     string
+	authHeader string
+	senderID   string
+	client     *http.Client
+}
+
+func NewHTTPSender(cfg config.SMSProvider) *HTTPSender {
+	authHeader := cfg.AuthHeader
+	if authHeader == "" {
+		authHeader = "Authorization"
+	}
+	format := cfg.Format
+	if format == "" {
+		format = "json"
+	}
+	return &HTTPSender{
+		name:       cfg.Name,
+		url:        cfg.URL,
+		format:     format,
+		apiKey:     cfg.APIKey,
+		authHeader: authHeader,
+		senderID:   cfg.SenderID,
+		client:     &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}
+}
+
+func (s *HTTPSender) Name() string {
+	return s.name
+}
+
+func (s *HTTPSender) Send(ctx context.Context, msg *models.Message) (*models.SendResult, error) {
+	req, err := s.newRequest(ctx, msg)
+	if err != nil {
+		return nil, &ProviderError{Provider: s.name, Err: err}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, &ProviderError{Provider: s.name, Retryable: ctx.Err() == nil, Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &ProviderError{
+			Provider:   s.name,
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			Retryable:  resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests,
+		}
+	}
+
+	return &models.SendResult{Provider: s.name, MessageID: parseMessageID(body)}, nil
+}
+
+func (s *HTTPSender) newRequest(ctx context.Context, msg *models.Message) (*http.Request, error) {
+	senderID := msg.SenderID
+	if senderID == "" {
+		senderID = s.senderID
+	}
+	fields := map[string]string{
+		"sender":  senderID,
+		"to":      strings.TrimPrefix(msg.To, "+"),
+		"message": msg.Body,
+	}
+	if msg.TemplateID != "" {
+		fields["template_id"] = msg.TemplateID
+	}
+	if msg.EntityID != "" {
+		fields["entity_id"] = msg.EntityID
+	}
+
+	var (
+		body        io.Reader
+		contentType string
+	)
+	switch s.format {
+	case "form":
+		form := url.Values{}
+		for k, v := range fields {
+			form.Set(k, v)
+		}
+		body = strings.NewReader(form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	default:
+		payload, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(payload)
+		contentType = "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if s.apiKey != "" {
+		req.Header.Set(s.authHeader, s.apiKey)
+	}
+	return req, nil
+}
+
+// parseMessageID extracts the gateway's message reference, which providers
+// return under different keys.
+func parseMessageID(body []byte) string {
+	var resp map[string]any
+	if json.Unmarshal(body, &resp) != nil {
+		return ""
+	}
+	for _, key := range []string{"message_id", "messageId", "request_id", "id"} {
+		if id, ok := resp[key].(string); ok && id != "" {
+			return id
+		}
+	}
+	return ""
+}
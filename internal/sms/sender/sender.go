@@ -0,0 +1,40 @@
+package sender
+
+import (
+	"fmt"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/sms"
+	"swasthAI/pkg/logger"
+)
+
+// NewFromConfig builds a FailoverSender over the configured providers.
+func NewFromConfig(cfg config.SMS, logger *logger.Logger) (*FailoverSender, error) {
+	if len(cfg.Providers) == 0 {
+		return nil, fmt.Errorf("sms: no providers configured")
+	}
+
+	f := NewFailoverSender(logger)
+	for _, p := range cfg.Providers {
+		var (
+			s   sms.SMSSender
+			err error
+		)
+		switch p.Type {
+		case "http":
+			s = NewHTTPSender(p)
+		case "file":
+			s, err = NewFileSender(p.Name, p.Path)
+		case "fake":
+			s = NewFakeSender(p.Name)
+		default:
+			err = fmt.Errorf("unknown provider type %q", p.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sms: provider %s: %w", p.Name, err)
+		}
+		f.Add(s, time.Duration(p.Timeout)*time.Second, p.MaxRetries, time.Duration(p.RetryBackoff)*time.Millisecond)
+	}
+	return f, nil
+}
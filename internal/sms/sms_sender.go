@@ -0,0 +1,11 @@
+package sms
+
+import (
+	"context"
+	"swasthAI/internal/sms/models"
+)
+
+type SMSSender interface {
+	Name() string
+	Send(ctx context.Context, msg *models.Message) (*models.SendResult, error)
+}
@@ -0,0 +1,13 @@
+package sms
+
+import (
+	"context"
+	"swasthAI/internal/sms/models"
+)
+
+type SMSUsecase interface {
+	Send(ctx context.Context, req *models.SendRequest) (*models.OutboxMessage, error)
+	Enqueue(ctx context.Context, req *models.SendRequest) (*models.OutboxMessage, error)
+	DispatchDue(ctx context.Context) (int, error)
+	HandleDeliveryReport(ctx context.Context, report *models.DeliveryReport) error
+}
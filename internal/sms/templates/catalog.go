@@ -0,0 +1,49 @@
+package templates
+
+import "swasthAI/internal/sms/models"
+
+// catalog holds the message bodies exactly as registered on the DLT portal.
+// Editing a body requires registering it again, otherwise carriers drop it.
+var catalog = []models.Template{
+	// OTP
+	{Kind: models.KindOTP, Language: "en", Variables: []string{"otp", "minutes"}, Secrets: []string{"otp"},
+		Body: "{#var#} is your Swasth AI verification code. It is valid for {#var#} minutes. Do not share it with anyone."},
+	{Kind: models.KindOTP, Language: "hi", Variables: []string{"otp", "minutes"}, Secrets: []string{"otp"},
+		Body: "{#var#} आपका Swasth AI सत्यापन कोड है। यह {#var#} मिनट तक मान्य है। इसे किसी के साथ साझा न करें।"},
+	{Kind: models.KindOTP, Language: "ta", Variables: []string{"otp", "minutes"}, Secrets: []string{"otp"},
+		Body: "{#var#} உங்கள் Swasth AI சரிபார்ப்புக் குறியீடு. இது {#var#} நிமிடங்களுக்கு செல்லுபடியாகும். இதை யாருடனும் பகிர வேண்டாம்."},
+	{Kind: models.KindOTP, Language: "te", Variables: []string{"otp", "minutes"}, Secrets: []string{"otp"},
+		Body: "{#var#} మీ Swasth AI ధృవీకరణ కోడ్. ఇది {#var#} నిమిషాల పాటు చెల్లుతుంది. దీన్ని ఎవరితోనూ పంచుకోవద్దు."},
+	{Kind: models.KindOTP, Language: "bn", Variables: []string{"otp", "minutes"}, Secrets: []string{"otp"},
+		Body: "{#var#} আপনার Swasth AI যাচাইকরণ কোড। এটি {#var#} মিনিটের জন্য বৈধ। এটি কারও সাথে শেয়ার করবেন না।"},
+	{Kind: models.KindOTP, Language: "mr", Variables: []string{"otp", "minutes"}, Secrets: []string{"otp"},
+		Body: "{#var#} हा तुमचा Swasth AI पडताळणी कोड आहे. तो {#var#} मिनिटांसाठी वैध आहे. तो कोणालाही सांगू नका."},
+
+	// Appointment reminder
+	{Kind: models.KindAppointmentReminder, Language: "en", Variables: []string{"name", "doctor", "time"},
+		Body: "Dear {#var#}, this is a reminder of your appointment with Dr. {#var#} on {#var#}. - Swasth AI"},
+	{Kind: models.KindAppointmentReminder, Language: "hi", Variables: []string{"name", "doctor", "time"},
+		Body: "प्रिय {#var#}, डॉ. {#var#} के साथ {#var#} को आपकी अपॉइंटमेंट है। - Swasth AI"},
+	{Kind: models.KindAppointmentReminder, Language: "ta", Variables: []string{"name", "doctor", "time"},
+		Body: "அன்புள்ள {#var#}, டாக்டர் {#var#} உடன் {#var#} அன்று உங்கள் சந்திப்பு உள்ளது. - Swasth AI"},
+	{Kind: models.KindAppointmentReminder, Language: "te", Variables: []string{"name", "doctor", "time"},
+		Body: "ప్రియమైన {#var#}, డాక్టర్ {#var#} తో {#var#} న మీ అపాయింట్‌మెంట్ ఉంది. - Swasth AI"},
+	{Kind: models.KindAppointmentReminder, Language: "bn", Variables: []string{"name", "doctor", "time"},
+		Body: "প্রিয় {#var#}, ডা. {#var#}-এর সাথে {#var#} তারিখে আপনার অ্যাপয়েন্টমেন্ট আছে। - Swasth AI"},
+	{Kind: models.KindAppointmentReminder, Language: "mr", Variables: []string{"name", "doctor", "time"},
+		Body: "प्रिय {#var#}, डॉ. {#var#} यांच्यासोबत {#var#} रोजी तुमची अपॉइंटमेंट आहे. - Swasth AI"},
+
+	// Referral
+	{Kind: models.KindReferral, Language: "en", Variables: []string{"name", "facility"},
+		Body: "Dear {#var#}, you have been referred to {#var#}. Please visit at the earliest and carry this message. - Swasth AI"},
+	{Kind: models.KindReferral, Language: "hi", Variables: []string{"name", "facility"},
+		Body: "प्रिय {#var#}, आपको {#var#} के लिए रेफर किया गया है। कृपया जल्द से जल्द जाएँ और यह संदेश साथ रखें। - Swasth AI"},
+	{Kind: models.KindReferral, Language: "ta", Variables: []string{"name", "facility"},
+		Body: "அன்புள்ள {#var#}, நீங்கள் {#var#} க்கு பரிந்துரைக்கப்பட்டுள்ளீர்கள். விரைவில் சென்று இந்தச் செய்தியைக் காட்டவும். - Swasth AI"},
+	{Kind: models.KindReferral, Language: "te", Variables: []string{"name", "facility"},
+		Body: "ప్రియమైన {#var#}, మిమ్మల్ని {#var#} కు రిఫర్ చేశారు. దయచేసి త్వరగా వెళ్లి ఈ సందేశాన్ని చూపించండి. - Swasth AI"},
+	{Kind: models.KindReferral, Language: "bn", Variables: []string{"name", "facility"},
+		Body: "প্রিয় {#var#}, আপনাকে {#var#}-এ রেফার করা হয়েছে। অনুগ্রহ করে দ্রুত যান এবং এই বার্তাটি সঙ্গে রাখুন। - Swasth AI"},
+	{Kind: models.KindReferral, Language: "mr", Variables: []string{"name", "facility"},
+		Body: "प्रिय {#var#}, तुम्हाला {#var#} येथे रेफर करण्यात आले आहे. कृपया लवकरात लवकर भेट द्या आणि हा संदेश सोबत ठेवा. - Swasth AI"},
+}
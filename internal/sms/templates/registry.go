@@ -0,0 +1,71 @@
+package templates
+
+import (
+	"fmt"
+
+	"swasthAI/config"
+	"swasthAI/internal/sms/models"
+)
+
+type key struct {
+	kind     models.Kind
+	language string
+}
+
+// Registry resolves the DLT template for a message kind and language.
+type Registry struct {
+	templates       map[key]models.Template
+	defaultLanguage string
+}
+
+// NewRegistry loads the built-in catalog and attaches the DLT template IDs,
+// entity ID and sender header from config.
+func NewRegistry(cfg config.SMS) (*Registry, error) {
+	defaultLanguage := cfg.DefaultLanguage
+	if defaultLanguage == "" {
+		defaultLanguage = "en"
+	}
+
+	r := &Registry{templates: make(map[key]models.Template, len(catalog)), defaultLanguage: defaultLanguage}
+	for _, t := range catalog {
+		t.EntityID = cfg.DLT.EntityID
+		t.SenderID = cfg.DLT.SenderID
+		t.DLTTemplateID = cfg.DLT.TemplateIDs[fmt.Sprintf("%s_%s", t.Kind, t.Language)]
+		if _, err := t.Render(placeholderVars(t)); err != nil {
+			return nil, err
+		}
+		r.templates[key{t.Kind, t.Language}] = t
+	}
+
+	for _, kind := range []models.Kind{models.KindOTP, models.KindAppointmentReminder, models.KindReferral} {
+		if _, ok := r.templates[key{kind, defaultLanguage}]; !ok {
+			return nil, fmt.Errorf("sms templates: no %s template for default language %q", kind, defaultLanguage)
+		}
+	}
+	return r, nil
+}
+
+// Get returns the template for kind in language, falling back to the
+// default language when no translation is registered.
+func (r *Registry) Get(kind models.Kind, language string) (models.Template, error) {
+	if t, ok := r.templates[key{kind, language}]; ok {
+		return t, nil
+	}
+	if t, ok := r.templates[key{kind, r.defaultLanguage}]; ok {
+		return t, nil
+	}
+	return models.Template{}, fmt.Errorf("sms templates: unknown kind %q", kind)
+}
+
+// DefaultLanguage is used when the recipient's language is unknown.
+func (r *Registry) DefaultLanguage() string {
+	return r.defaultLanguage
+}
+
+func placeholderVars(t models.Template) map[string]string {
+	vars := make(map[string]string, len(t.Variables))
+	for _, v := range t.Variables {
+		vars[v] = v
+	}
+	return vars
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/sms"
+	"swasthAI/internal/sms/models"
+	"swasthAI/internal/sms/templates"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/logger"
+)
+
+const maxRetryBackoff = time.Hour
+
+type SMSUsecase struct {
+	outboxRepo sms.OutboxRepository
+	sender     sms.SMSSender
+	templates  *templates.Registry
+	cfg        config.SMSOutbox
+	logger     *logger.Logger
+}
+
+func NewSMSUsecase(outboxRepo sms.OutboxRepository, sender sms.SMSSender, templates *templates.Registry, cfg config.SMSOutbox, logger *logger.Logger) *SMSUsecase {
+	return &SMSUsecase{outboxRepo: outboxRepo, sender: sender, templates: templates, cfg: cfg, logger: logger}
+}
+
+// Send stores the message in the outbox and delivers it right away. If the
+// delivery fails the provider error is returned and the message stays in
+// the outbox for the dispatcher to retry until it expires.
+//
+// A message with secret variables, such as an OTP, is stored with them
+// masked. It cannot be sent again from the outbox, so its first failure is
+// final.
+func (u *SMSUsecase) Send(ctx context.Context, req *models.SendRequest) (*models.OutboxMessage, error) {
+	msg, body, err := u.render(req, models.OutboxSending)
+	if err != nil {
+		return nil, err
+	}
+	msg.Attempts = 1
+
+	if err := u.outboxRepo.Create(ctx, msg); err != nil {
+		u.logger.Error("failed to store sms (smsUC.Send.outboxRepo.Create)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+
+	if err := u.deliver(ctx, msg, body); err != nil {
+		return msg, err
+	}
+	return msg, nil
+}
+
+// Enqueue stores the message for the dispatcher without sending it.
+// Messages with secret variables cannot be queued.
+func (u *SMSUsecase) Enqueue(ctx context.Context, req *models.SendRequest) (*models.OutboxMessage, error) {
+	msg, body, err := u.render(req, models.OutboxPending)
+	if err != nil {
+		return nil, err
+	}
+	if body != msg.Body {
+		u.logger.Error("sms with secret variables cannot be queued (smsUC.Enqueue)", "kind", req.Kind)
+		return nil, appErrors.ErrInternal
+	}
+
+	if err := u.outboxRepo.Create(ctx, msg); err != nil {
+		u.logger.Error("failed to store sms (smsUC.Enqueue.outboxRepo.Create)", "error", err)
+		return nil, appErrors.ErrDatabase
+	}
+	return msg, nil
+}
+
+// DispatchDue sends one batch of due messages and returns how many were claimed.
+func (u *SMSUsecase) DispatchDue(ctx context.Context) (int, error) {
+	msgs, err := u.outboxRepo.ClaimDue(ctx, time.Now().UTC(), u.batchSize())
+	if err != nil {
+		u.logger.Error("failed to claim sms (smsUC.DispatchDue.outboxRepo.ClaimDue)", "error", err)
+		return 0, appErrors.ErrDatabase
+	}
+
+	for i := range msgs {
+		if err := u.deliver(ctx, &msgs[i], msgs[i].Body); err != nil {
+			u.logger.Warn("sms delivery failed", "id", msgs[i].ID, "attempt", msgs[i].Attempts, "error", err)
+		}
+	}
+	return len(msgs), nil
+}
+
+// Run dispatches due messages every poll interval until ctx is cancelled.
+func (u *SMSUsecase) Run(ctx context.Context) {
+	interval := time.Duration(u.cfg.PollInterval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				n, err := u.DispatchDue(ctx)
+				if err != nil || n < u.batchSize() {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (u *SMSUsecase) HandleDeliveryReport(ctx context.Context, report *models.DeliveryReport) error {
+	status, ok := normalizeDeliveryStatus(report.Status)
+	if !ok {
+		return domain_errors.ErrUnknownDeliveryStatus
+	}
+	if status == "" {
+		// intermediate states such as "submitted" carry no new information
+		return nil
+	}
+
+	at := report.DeliveredAt
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+
+	err := u.outboxRepo.UpdateDeliveryStatus(ctx, report.Provider, report.MessageID, status, at, report.Error)
+	if err != nil {
+		if errors.Is(err, domain_errors.ErrSMSMessageNotFound) {
+			return domain_errors.ErrSMSMessageNotFound
+		}
+		u.logger.Error("failed to update delivery status (smsUC.HandleDeliveryReport.outboxRepo.UpdateDeliveryStatus)", "error", err)
+		return appErrors.ErrDatabase
+	}
+	return nil
+}
+
+// render returns the outbox entry to store and the body to send. They only
+// differ when the template has secret variables, which the stored body
+// has masked.
+func (u *SMSUsecase) render(req *models.SendRequest, status models.OutboxStatus) (*models.OutboxMessage, string, error) {
+	language := req.Language
+	if language == "" {
+		language = u.templates.DefaultLanguage()
+	}
+	tmpl, err := u.templates.Get(req.Kind, language)
+	if err != nil {
+		u.logger.Error("unknown sms template (smsUC.render.templates.Get)", "error", err)
+		return nil, "", appErrors.ErrInternal.WithCause(err)
+	}
+	body, err := tmpl.Render(req.Vars)
+	if err != nil {
+		u.logger.Error("failed to render sms template (smsUC.render.Render)", "error", err)
+		return nil, "", appErrors.ErrInternal.WithCause(err)
+	}
+	stored, err := tmpl.Render(tmpl.Redact(req.Vars))
+	if err != nil {
+		u.logger.Error("failed to render sms template (smsUC.render.Render)", "error", err)
+		return nil, "", appErrors.ErrInternal.WithCause(err)
+	}
+
+	now := time.Now().UTC()
+	msg := &models.OutboxMessage{
+		Kind:          tmpl.Kind,
+		Language:      tmpl.Language,
+		To:            req.To,
+		SenderID:      tmpl.SenderID,
+		TemplateID:    tmpl.DLTTemplateID,
+		EntityID:      tmpl.EntityID,
+		Body:          stored,
+		Status:        status,
+		NextAttemptAt: now,
+	}
+	if req.TTL > 0 {
+		msg.ExpiresAt = now.Add(req.TTL)
+	}
+	return msg, body, nil
+}
+
+// deliver hands a claimed message to the provider, with body in place of
+// the stored one, and records the outcome. A message whose stored body is
+// masked is not retried.
+func (u *SMSUsecase) deliver(ctx context.Context, msg *models.OutboxMessage, body string) error {
+	now := time.Now().UTC()
+	if !msg.ExpiresAt.IsZero() && !now.Before(msg.ExpiresAt) {
+		u.markFailed(ctx, msg, models.OutboxExpired, "")
+		return nil
+	}
+
+	out := msg.Message()
+	out.Body = body
+	res, sendErr := u.sender.Send(ctx, out)
+	if sendErr == nil {
+		if err := u.outboxRepo.MarkSent(ctx, msg.ID, res.Provider, res.MessageID); err != nil {
+			u.logger.Error("failed to mark sms sent (smsUC.deliver.outboxRepo.MarkSent)", "error", err)
+		}
+		msg.Status = models.OutboxSent
+		msg.Provider = res.Provider
+		msg.ProviderMessageID = res.MessageID
+		return nil
+	}
+
+	next := now.Add(u.backoff(msg.Attempts))
+	switch {
+	case body != msg.Body, msg.Attempts >= u.maxAttempts():
+		u.markFailed(ctx, msg, models.OutboxFailed, sendErr.Error())
+	case !msg.ExpiresAt.IsZero() && !next.Before(msg.ExpiresAt):
+		u.markFailed(ctx, msg, models.OutboxExpired, sendErr.Error())
+	default:
+		if err := u.outboxRepo.MarkRetry(ctx, msg.ID, next, sendErr.Error()); err != nil {
+			u.logger.Error("failed to schedule sms retry (smsUC.deliver.outboxRepo.MarkRetry)", "error", err)
+		}
+		msg.Status = models.OutboxPending
+		msg.NextAttemptAt = next
+	}
+	msg.LastError = sendErr.Error()
+	return sendErr
+}
+
+func (u *SMSUsecase) markFailed(ctx context.Context, msg *models.OutboxMessage, status models.OutboxStatus, lastError string) {
+	if err := u.outboxRepo.MarkFailed(ctx, msg.ID, status, lastError); err != nil {
+		u.logger.Error("failed to mark sms failed (smsUC.markFailed.outboxRepo.MarkFailed)", "error", err)
+	}
+	msg.Status = status
+}
+
+// backoff doubles the configured delay after every attempt, capped at an hour.
+func (u *SMSUsecase) backoff(attempts int) time.Duration {
+	d := time.Duration(u.cfg.RetryBackoff) * time.Second
+	if d <= 0 {
+		d = 30 * time.Second
+	}
+	for i := 1; i < attempts && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxRetryBackoff)
+}
+
+func (u *SMSUsecase) maxAttempts() int {
+	if u.cfg.MaxAttempts <= 0 {
+		return 5
+	}
+	return u.cfg.MaxAttempts
+}
+
+func (u *SMSUsecase) batchSize() int {
+	if u.cfg.BatchSize <= 0 {
+		return 50
+	}
+	return u.cfg.BatchSize
+}
+
+// normalizeDeliveryStatus maps provider specific receipt states onto outbox
+// states. An empty status with ok set means the report is informational.
+func normalizeDeliveryStatus(status string) (models.OutboxStatus, bool) {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "delivered", "delivrd", "success":
+		return models.OutboxDelivered, true
+	case "failed", "undelivered", "undeliv", "rejected", "rejectd", "expired", "dnd":
+		return models.OutboxFailed, true
+	case "sent", "submitted", "accepted", "queued", "pending":
+		return "", true
+	}
+	return "", false
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+	mocks "swasthAI/internal/sms/mocks"
+	"swasthAI/internal/sms/models"
+	"swasthAI/internal/sms/sender"
+	"swasthAI/internal/sms/templates"
+	"swasthAI/pkg/domain_errors"
+	"swasthAI/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTest(t *testing.T) (*SMSUsecase, *mocks.MockOutboxRepository, *sender.FakeSender, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockOutboxRepository(ctrl)
+	fake := sender.NewFakeSender("fake")
+
+	smsCfg := config.SMS{
+		DefaultLanguage: "en",
+		DLT: config.SMSDLT{
+			EntityID:    "1101000000000000000",
+			SenderID:    "SWASTH",
+			TemplateIDs: map[string]string{"otp_hi": "1107000000000000001"},
+		},
+		Outbox: config.SMSOutbox{MaxAttempts: 3, RetryBackoff: 30},
+	}
+	registry, err := templates.NewRegistry(smsCfg)
+	require.NoError(t, err)
+	log, _ := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+
+	return NewSMSUsecase(repo, fake, registry, smsCfg.Outbox, log), repo, fake, ctrl
+}
+
+func TestSMSUsecase_Send_RendersDLTTemplate(t *testing.T) {
+	uc, repo, fake, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	repo.EXPECT().MarkSent(ctx, gomock.Any(), "fake", gomock.Any()).Return(nil)
+
+	msg, err := uc.Send(ctx, &models.SendRequest{
+		To:       "+919876543210",
+		Kind:     models.KindOTP,
+		Language: "hi",
+		Vars:     map[string]string{"otp": "482913", "minutes": "5"},
+		TTL:      5 * time.Minute,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.OutboxSent, msg.Status)
+
+	sent := fake.Messages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "1107000000000000001", sent[0].TemplateID)
+	assert.Equal(t, "1101000000000000000", sent[0].EntityID)
+	assert.Equal(t, "SWASTH", sent[0].SenderID)
+	assert.Equal(t, "482913 आपका Swasth AI सत्यापन कोड है। यह 5 मिनट तक मान्य है। इसे किसी के साथ साझा न करें।", sent[0].Body)
+}
+
+func TestSMSUsecase_Send_FallsBackToDefaultLanguage(t *testing.T) {
+	uc, repo, fake, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	repo.EXPECT().MarkSent(ctx, gomock.Any(), "fake", gomock.Any()).Return(nil)
+
+	msg, err := uc.Send(ctx, &models.SendRequest{
+		To:       "+919876543210",
+		Kind:     models.KindReferral,
+		Language: "gu",
+		Vars:     map[string]string{"name": "Ramesh", "facility": "PHC Rampur"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "en", msg.Language)
+	assert.Contains(t, fake.Messages()[0].Body, "referred to PHC Rampur")
+}
+
+func TestSMSUsecase_Send_ProviderFailureSchedulesRetry(t *testing.T) {
+	uc, repo, fake, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	providerErr := errors.New("gateway down")
+	fake.FailWith(providerErr)
+
+	repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	repo.EXPECT().MarkRetry(ctx, gomock.Any(), gomock.Any(), providerErr.Error()).Return(nil)
+
+	msg, err := uc.Send(ctx, &models.SendRequest{
+		To:   "+919876543210",
+		Kind: models.KindAppointmentReminder,
+		Vars: map[string]string{"name": "Ramesh", "doctor": "Mehta", "time": "12 Nov 10:30"},
+	})
+	assert.ErrorIs(t, err, providerErr)
+	assert.Equal(t, models.OutboxPending, msg.Status)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), msg.NextAttemptAt, 2*time.Second)
+}
+
+func TestSMSUsecase_Send_DoesNotStoreOTP(t *testing.T) {
+	uc, repo, fake, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	var stored *models.OutboxMessage
+	repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msg *models.OutboxMessage) error {
+		copied := *msg
+		stored = &copied
+		return nil
+	})
+	repo.EXPECT().MarkSent(ctx, gomock.Any(), "fake", gomock.Any()).Return(nil)
+
+	msg, err := uc.Send(ctx, &models.SendRequest{
+		To:   "+919876543210",
+		Kind: models.KindOTP,
+		Vars: map[string]string{"otp": "482913", "minutes": "5"},
+		TTL:  5 * time.Minute,
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, stored)
+	assert.NotContains(t, stored.Body, "482913")
+	assert.Equal(t, "****** is your Swasth AI verification code. It is valid for 5 minutes. Do not share it with anyone.", stored.Body)
+	assert.NotContains(t, msg.Body, "482913")
+	assert.Contains(t, fake.Messages()[0].Body, "482913")
+}
+
+func TestSMSUsecase_Send_OTPFailureIsFinal(t *testing.T) {
+	uc, repo, fake, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	fake.FailWith(errors.New("gateway down"))
+
+	// the stored copy has no code to retry with
+	repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	repo.EXPECT().MarkFailed(ctx, gomock.Any(), models.OutboxFailed, "gateway down").Return(nil)
+
+	msg, err := uc.Send(ctx, &models.SendRequest{
+		To:   "+919876543210",
+		Kind: models.KindOTP,
+		Vars: map[string]string{"otp": "482913", "minutes": "5"},
+		TTL:  5 * time.Minute,
+	})
+	assert.Error(t, err)
+	assert.Equal(t, models.OutboxFailed, msg.Status)
+}
+
+func TestSMSUsecase_Enqueue_RejectsOTP(t *testing.T) {
+	uc, _, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	_, err := uc.Enqueue(context.Background(), &models.SendRequest{
+		To:   "+919876543210",
+		Kind: models.KindOTP,
+		Vars: map[string]string{"otp": "482913", "minutes": "5"},
+	})
+	assert.Error(t, err)
+}
+
+func TestSMSUsecase_DispatchDue_GivesUpAfterMaxAttempts(t *testing.T) {
+	uc, repo, fake, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	fake.FailWith(errors.New("gateway down"))
+
+	claimed := []models.OutboxMessage{{To: "+919876543210", Body: "hi", Attempts: 3, Status: models.OutboxSending}}
+	repo.EXPECT().ClaimDue(ctx, gomock.Any(), 50).Return(claimed, nil)
+	repo.EXPECT().MarkFailed(ctx, claimed[0].ID, models.OutboxFailed, "gateway down").Return(nil)
+
+	n, err := uc.DispatchDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestSMSUsecase_DispatchDue_DropsExpired(t *testing.T) {
+	uc, repo, fake, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	claimed := []models.OutboxMessage{{To: "+919876543210", Body: "otp", Attempts: 2, ExpiresAt: time.Now().Add(-time.Second)}}
+	repo.EXPECT().ClaimDue(ctx, gomock.Any(), 50).Return(claimed, nil)
+	repo.EXPECT().MarkFailed(ctx, claimed[0].ID, models.OutboxExpired, "").Return(nil)
+
+	_, err := uc.DispatchDue(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, fake.Messages())
+}
+
+func TestSMSUsecase_HandleDeliveryReport(t *testing.T) {
+	uc, repo, _, ctrl := setupTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	repo.EXPECT().UpdateDeliveryStatus(ctx, "msg91", "req-1", models.OutboxDelivered, gomock.Any(), "").Return(nil)
+	assert.NoError(t, uc.HandleDeliveryReport(ctx, &models.DeliveryReport{Provider: "msg91", MessageID: "req-1", Status: "DELIVRD"}))
+
+	// intermediate states are acknowledged without touching the outbox
+	assert.NoError(t, uc.HandleDeliveryReport(ctx, &models.DeliveryReport{Provider: "msg91", MessageID: "req-1", Status: "submitted"}))
+
+	assert.Equal(t, domain_errors.ErrUnknownDeliveryStatus, uc.HandleDeliveryReport(ctx, &models.DeliveryReport{Provider: "msg91", MessageID: "req-1", Status: "weird"}))
+}
@@ -0,0 +1,13 @@
+package voice
+
+import (
+	"context"
+	"swasthAI/internal/voice/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// AIClient opens the AI service's side of a voice session.
+type AIClient interface {
+	Connect(ctx context.Context, req *models.AISessionRequest) (*websocket.Conn, error)
+}
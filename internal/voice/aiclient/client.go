@@ -0,0 +1,282 @@
+// Package aiclient opens sessions on the AI service. Each session starts
+// with the REST handshake at /internal/ai/session/start, which names the
+// socket to stream on. Sessions are spread over the configured endpoints,
+// failures are retried with jittered backoff, and a circuit breaker per
+// endpoint makes callers fail fast while an endpoint is down.
+package aiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/voice/models"
+	"swasthAI/pkg/logger"
+
+	"github.com/gorilla/websocket"
+)
+
+const handshakePath = "/internal/ai/session/start"
+
+// ErrUnavailable is returned without contacting the AI service when every
+// endpoint is failing its health check or has an open breaker.
+var ErrUnavailable = errors.New("aiclient: no AI service endpoint available")
+
+// EndpointError is returned when an endpoint fails the handshake or the
+// dial.
+type EndpointError struct {
+	Endpoint   string
+	StatusCode int // of the handshake; 0 for transport and dial errors
+	Body       string
+	Retryable  bool
+	Err        error
+}
+
+func (e *EndpointError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("AI service %s: %v", e.Endpoint, e.Err)
+	}
+	return fmt.Sprintf("AI service %s: status %d: %s", e.Endpoint, e.StatusCode, e.Body)
+}
+
+func (e *EndpointError) Unwrap() error {
+	return e.Err
+}
+
+type endpoint struct {
+	base    string
+	breaker *breaker
+	healthy atomic.Bool
+}
+
+type Client struct {
+	endpoints []*endpoint
+	next      atomic.Uint64
+
+	http        *http.Client
+	dialer      *websocket.Dialer
+	timeout     time.Duration
+	maxAttempts int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	healthEvery time.Duration
+	healthPath  string
+
+	logger *logger.Logger
+	now    func() time.Time
+}
+
+func New(cfg config.VoiceAI, logger *logger.Logger) *Client {
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	c := &Client{
+		http:        &http.Client{Timeout: timeout},
+		dialer:      &websocket.Dialer{HandshakeTimeout: timeout, Proxy: http.ProxyFromEnvironment},
+		timeout:     timeout,
+		maxAttempts: max(cfg.MaxAttempts, 1),
+		backoffBase: time.Duration(cfg.BackoffBase) * time.Millisecond,
+		backoffMax:  time.Duration(cfg.BackoffMax) * time.Millisecond,
+		healthEvery: time.Duration(cfg.HealthInterval) * time.Second,
+		healthPath:  cfg.HealthPath,
+		logger:      logger,
+		now:         time.Now,
+	}
+	for _, base := range cfg.Endpoints {
+		ep := &endpoint{
+			base:    strings.TrimSuffix(base, "/"),
+			breaker: newBreaker(cfg.BreakerThreshold, time.Duration(cfg.BreakerCooldown)*time.Second),
+		}
+		ep.healthy.Store(true)
+		c.endpoints = append(c.endpoints, ep)
+	}
+	return c
+}
+
+// Connect starts the session on an endpoint and dials the socket it names.
+// Retryable failures are tried again, on the next endpoint in turn.
+func (c *Client) Connect(ctx context.Context, req *models.AISessionRequest) (*websocket.Conn, error) {
+	var lastErr error
+	for attempt := 0; attempt < c.maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(c.backoff(attempt)):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		ep := c.pick()
+		if ep == nil {
+			if lastErr != nil {
+				return nil, errors.Join(ErrUnavailable, lastErr)
+			}
+			return nil, ErrUnavailable
+		}
+		conn, err := c.connect(ctx, ep, req)
+		if err == nil {
+			ep.breaker.success()
+			return conn, nil
+		}
+		lastErr = err
+
+		var epErr *EndpointError
+		retryable := errors.As(err, &epErr) && epErr.Retryable && ctx.Err() == nil
+		switch {
+		case retryable:
+			ep.breaker.failure(c.now())
+			c.logger.Warn("AI service endpoint failed (aiclient.Connect)", "endpoint", ep.base, "attempt", attempt+1, "error", err)
+		case ctx.Err() != nil:
+			ep.breaker.release(c.now())
+			return nil, err
+		default:
+			// the endpoint answered; the request itself was refused
+			ep.breaker.success()
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// pick returns the next endpoint in turn that is healthy and whose breaker
+// lets a request through.
+func (c *Client) pick() *endpoint {
+	n := uint64(len(c.endpoints))
+	if n == 0 {
+		return nil
+	}
+	start := c.next.Add(1) - 1
+	now := c.now()
+	for i := uint64(0); i < n; i++ {
+		ep := c.endpoints[(start+i)%n]
+		if ep.healthy.Load() && ep.breaker.allow(now) {
+			return ep
+		}
+	}
+	return nil
+}
+
+// backoff is the wait before the given attempt: the base doubled per
+// attempt up to the maximum, half of it random so that sessions started
+// together do not retry together.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.backoffBase << (attempt - 1)
+	if d > c.backoffMax || d <= 0 {
+		d = c.backoffMax
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+func (c *Client) connect(ctx context.Context, ep *endpoint, req *models.AISessionRequest) (*websocket.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	session, err := c.handshake(ctx, ep, req)
+	if err != nil {
+		return nil, err
+	}
+	conn, res, err := c.dialer.DialContext(ctx, session.WSURL, nil)
+	if err != nil {
+		epErr := &EndpointError{Endpoint: ep.base, Retryable: true, Err: err}
+		if res != nil {
+			epErr.StatusCode = res.StatusCode
+		}
+		return nil, epErr
+	}
+	return conn, nil
+}
+
+func (c *Client) handshake(ctx context.Context, ep *endpoint, req *models.AISessionRequest) (*models.AISessionResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.base+handshakePath, bytes.NewReader(body))
+	if err != nil {
+		return nil, &EndpointError{Endpoint: ep.base, Err: err}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	res, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, &EndpointError{Endpoint: ep.base, Retryable: true, Err: err}
+	}
+	defer res.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, &EndpointError{
+			Endpoint:   ep.base,
+			StatusCode: res.StatusCode,
+			Body:       string(data),
+			Retryable:  res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests,
+		}
+	}
+
+	var session models.AISessionResponse
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, &EndpointError{Endpoint: ep.base, StatusCode: res.StatusCode, Retryable: true, Err: err}
+	}
+	if u, err := url.Parse(session.WSURL); err != nil || (u.Scheme != "ws" && u.Scheme != "wss") {
+		return nil, &EndpointError{Endpoint: ep.base, StatusCode: res.StatusCode, Retryable: true, Err: fmt.Errorf("invalid ws_url %q", session.WSURL)}
+	}
+	return &session, nil
+}
+
+// Run checks the endpoints' health every health interval until ctx is
+// cancelled. Without an interval it returns at once.
+func (c *Client) Run(ctx context.Context) {
+	if c.healthEvery <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.healthEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.checkHealth(ctx)
+		}
+	}
+}
+
+func (c *Client) checkHealth(ctx context.Context) {
+	for _, ep := range c.endpoints {
+		healthy := c.probe(ctx, ep)
+		if ep.healthy.Swap(healthy) != healthy {
+			if healthy {
+				c.logger.Info("AI service endpoint is healthy again", "endpoint", ep.base)
+			} else {
+				c.logger.Warn("AI service endpoint failed its health check", "endpoint", ep.base)
+			}
+		}
+	}
+}
+
+func (c *Client) probe(ctx context.Context, ep *endpoint) bool {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ep.base+c.healthPath, nil)
+	if err != nil {
+		return false
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return false
+	}
+	res.Body.Close()
+	return res.StatusCode >= 200 && res.StatusCode <= 299
+}
@@ -0,0 +1,225 @@
+package aiclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/voice/models"
+	"swasthAI/pkg/logger"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// aiService fakes one AI service endpoint. status, when set, is answered to
+// the handshake instead of starting the session.
+type aiService struct {
+	srv        *httptest.Server
+	status     atomic.Int32
+	healthy    atomic.Bool
+	handshakes atomic.Int32
+	last       atomic.Pointer[models.AISessionRequest]
+}
+
+func newAIService(t *testing.T) *aiService {
+	s := &aiService{}
+	s.healthy.Store(true)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+handshakePath, func(w http.ResponseWriter, r *http.Request) {
+		s.handshakes.Add(1)
+		if status := s.status.Load(); status != 0 {
+			w.WriteHeader(int(status))
+			return
+		}
+		var req models.AISessionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		s.last.Store(&req)
+		json.NewEncoder(w).Encode(models.AISessionResponse{
+			SessionID: req.SessionID,
+			WSURL:     "ws" + strings.TrimPrefix(s.srv.URL, "http") + "/session/" + req.SessionID + "/ws",
+		})
+	})
+	mux.HandleFunc("GET /session/{id}/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(r.PathValue("id")))
+		conn.Close()
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		if !s.healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	s.srv = httptest.NewServer(mux)
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+func newClient(t *testing.T, services ...*aiService) *Client {
+	log, err := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	require.NoError(t, err)
+	cfg := config.VoiceAI{
+		Timeout:          2,
+		MaxAttempts:      3,
+		BackoffBase:      1,
+		BackoffMax:       5,
+		BreakerThreshold: 2,
+		BreakerCooldown:  30,
+		HealthPath:       "/health",
+	}
+	for _, s := range services {
+		cfg.Endpoints = append(cfg.Endpoints, s.srv.URL+"/")
+	}
+	return New(cfg, log)
+}
+
+func connect(c *Client, id string) (*websocket.Conn, error) {
+	return c.Connect(context.Background(), &models.AISessionRequest{SessionID: id, Language: "hi", Model: "mistral-7b"})
+}
+
+// sessionOf reads the session ID the fake service greets a socket with.
+func sessionOf(t *testing.T, conn *websocket.Conn) string {
+	defer conn.Close()
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestConnect_Handshake(t *testing.T) {
+	ai := newAIService(t)
+	c := newClient(t, ai)
+
+	conn, err := connect(c, "vsn_a")
+	require.NoError(t, err)
+	assert.Equal(t, "vsn_a", sessionOf(t, conn))
+	assert.Equal(t, &models.AISessionRequest{SessionID: "vsn_a", Language: "hi", Model: "mistral-7b"}, ai.last.Load())
+}
+
+func TestConnect_RoundRobin(t *testing.T) {
+	a, b := newAIService(t), newAIService(t)
+	c := newClient(t, a, b)
+
+	for i := 0; i < 4; i++ {
+		conn, err := connect(c, "vsn_a")
+		require.NoError(t, err)
+		conn.Close()
+	}
+	assert.Equal(t, int32(2), a.handshakes.Load())
+	assert.Equal(t, int32(2), b.handshakes.Load())
+}
+
+func TestConnect_RetriesOnNextEndpoint(t *testing.T) {
+	down, up := newAIService(t), newAIService(t)
+	down.status.Store(http.StatusServiceUnavailable)
+	c := newClient(t, down, up)
+
+	conn, err := connect(c, "vsn_a")
+	require.NoError(t, err)
+	assert.Equal(t, "vsn_a", sessionOf(t, conn))
+	assert.Equal(t, int32(1), down.handshakes.Load())
+}
+
+func TestConnect_RefusedRequestIsNotRetried(t *testing.T) {
+	ai := newAIService(t)
+	ai.status.Store(http.StatusBadRequest)
+	c := newClient(t, ai)
+
+	_, err := connect(c, "vsn_a")
+	var epErr *EndpointError
+	require.ErrorAs(t, err, &epErr)
+	assert.Equal(t, http.StatusBadRequest, epErr.StatusCode)
+	assert.Equal(t, int32(1), ai.handshakes.Load())
+
+	// nor does it count against the endpoint
+	ai.status.Store(0)
+	conn, err := connect(c, "vsn_b")
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestConnect_BreakerFailsFast(t *testing.T) {
+	ai := newAIService(t)
+	ai.status.Store(http.StatusBadGateway)
+	c := newClient(t, ai)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	// two failures open the breaker, the third attempt is not even made
+	_, err := connect(c, "vsn_a")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, int32(2), ai.handshakes.Load())
+
+	_, err = connect(c, "vsn_b")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, int32(2), ai.handshakes.Load())
+
+	// after the cooldown one trial goes through and closes it again
+	ai.status.Store(0)
+	now = now.Add(31 * time.Second)
+	conn, err := connect(c, "vsn_c")
+	require.NoError(t, err)
+	conn.Close()
+	conn, err = connect(c, "vsn_d")
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestConnect_BadWSURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"session_id":"vsn_a","ws_url":"http://ai-service/ws"}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := newClient(t)
+	c.endpoints = []*endpoint{{base: srv.URL, breaker: newBreaker(5, time.Minute)}}
+	c.endpoints[0].healthy.Store(true)
+
+	_, err := connect(c, "vsn_a")
+	var epErr *EndpointError
+	require.ErrorAs(t, err, &epErr)
+	assert.True(t, epErr.Retryable)
+}
+
+func TestCheckHealth(t *testing.T) {
+	a, b := newAIService(t), newAIService(t)
+	c := newClient(t, a, b)
+
+	a.healthy.Store(false)
+	c.checkHealth(context.Background())
+	for i := 0; i < 3; i++ {
+		conn, err := connect(c, "vsn_a")
+		require.NoError(t, err)
+		conn.Close()
+	}
+	assert.Equal(t, int32(0), a.handshakes.Load())
+
+	b.healthy.Store(false)
+	c.checkHealth(context.Background())
+	_, err := connect(c, "vsn_a")
+	assert.True(t, errors.Is(err, ErrUnavailable))
+
+	a.healthy.Store(true)
+	c.checkHealth(context.Background())
+	conn, err := connect(c, "vsn_a")
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestBackoff(t *testing.T) {
+	c := &Client{backoffBase: 100 * time.Millisecond, backoffMax: time.Second}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 10: time.Second} {
+		d := c.backoff(attempt)
+		assert.GreaterOrEqual(t, d, want/2, attempt)
+		assert.LessOrEqual(t, d, want, attempt)
+	}
+}
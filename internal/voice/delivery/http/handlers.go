@@ -0,0 +1,136 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"swasthAI/config"
+	"swasthAI/internal/middleware"
+	"swasthAI/internal/voice"
+	"swasthAI/internal/voice/models"
+	"swasthAI/internal/voice/protocol"
+	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/http_errors"
+	"swasthAI/pkg/logger"
+	"swasthAI/pkg/utils"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	uc       voice.VoiceUsecase
+	upgrader websocket.Upgrader
+	logger   *logger.Logger
+	Cfg      *config.Config
+}
+
+func NewHandler(uc voice.VoiceUsecase, logger *logger.Logger, cfg *config.Config) *Handler {
+	h := &Handler{uc: uc, logger: logger, Cfg: cfg}
+	h.upgrader = websocket.Upgrader{
+		CheckOrigin: h.checkOrigin,
+		// browsers drop the connection unless the offered token
+		// subprotocol is answered
+		Subprotocols: []string{middleware.BearerSubprotocol},
+	}
+	return h
+}
+
+func (h *Handler) StartSession(c echo.Context) error {
+	var input models.StartSessionRequest
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error("failed to read input", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+
+	resp, err := h.uc.StartSession(c.Request().Context(), &input)
+	if err != nil {
+		h.logger.Error("failed to start voice session", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SessionWebSocket upgrades the caller's connection to a session they
+// started and relays it until either side hangs up. A client resuming the
+// session passes the last sequence number it received as last_seq.
+func (h *Handler) SessionWebSocket(c echo.Context) error {
+	ctx := c.Request().Context()
+	sessionID := c.Param("id")
+
+	var lastSeq uint64
+	if v := c.QueryParam("last_seq"); v != "" {
+		seq, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return http_errors.Send(c, appErrors.ErrInvalidInput)
+		}
+		lastSeq = seq
+	}
+
+	if err := h.uc.AuthorizeSession(ctx, sessionID); err != nil {
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// the upgrader has already written the error response
+		h.logger.Warn("failed to upgrade voice session", "session_id", sessionID, "error", err)
+		return nil
+	}
+	h.uc.HandleClientWebSocket(ctx, conn, sessionID, lastSeq)
+	return nil
+}
+
+func (h *Handler) EndSession(c echo.Context) error {
+	var input models.EndSessionRequest
+	if err := utils.ReadRequest(c, &input); err != nil {
+		h.logger.Error("failed to read input", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInvalidInput)
+	}
+
+	if err := h.uc.EndSession(c.Request().Context(), input.SessionID); err != nil {
+		h.logger.Error("failed to end voice session", "error", err)
+		if appErr, ok := err.(*appErrors.AppError); ok {
+			return http_errors.Send(c, appErr)
+		}
+		return http_errors.Send(c, appErrors.ErrInternal)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Session ended successfully",
+	})
+}
+
+// Protocol serves the WebSocket message catalogue as a JSON Schema.
+func (h *Handler) Protocol(c echo.Context) error {
+	return c.JSON(http.StatusOK, protocol.Schema())
+}
+
+// checkOrigin admits browser origins from the voice config. Native apps
+// send no Origin header and are allowed; their token is checked as usual.
+func (h *Handler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range h.Cfg.Voice.AllowedOrigins {
+		if strings.EqualFold(origin, allowed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"swasthAI/config"
+	"swasthAI/internal/middleware"
+	mock_voice "swasthAI/internal/voice/mocks"
+	"swasthAI/pkg/domain_errors"
+	"swasthAI/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupServer(t *testing.T) (*httptest.Server, *mock_voice.MockVoiceUsecase) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	cfg := &config.Config{Voice: config.Voice{AllowedOrigins: []string{"https://app.arogyasahayak.com"}}}
+	log, err := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	require.NoError(t, err)
+
+	uc := mock_voice.NewMockVoiceUsecase(ctrl)
+	h := NewHandler(uc, log, cfg)
+	e := echo.New()
+	e.GET("/voice/session/:id/ws", h.SessionWebSocket)
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+	return srv, uc
+}
+
+func dial(srv *httptest.Server, sessionID, origin string) (*websocket.Conn, *http.Response, error) {
+	return dialQuery(srv, sessionID, "", origin)
+}
+
+func dialQuery(srv *httptest.Server, sessionID, query, origin string) (*websocket.Conn, *http.Response, error) {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/voice/session/" + sessionID + "/ws"
+	if query != "" {
+		url += "?" + query
+	}
+	header := http.Header{}
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	header.Set("Sec-WebSocket-Protocol", middleware.BearerSubprotocol+", token")
+	return websocket.DefaultDialer.Dial(url, header)
+}
+
+func TestSessionWebSocket_Upgrades(t *testing.T) {
+	srv, uc := setupServer(t)
+
+	handled := make(chan struct{})
+	uc.EXPECT().AuthorizeSession(gomock.Any(), "vsn_abc123").Return(nil)
+	uc.EXPECT().HandleClientWebSocket(gomock.Any(), gomock.Any(), "vsn_abc123", uint64(0)).Do(func(ctx context.Context, conn *websocket.Conn, sessionID string, lastSeq uint64) {
+		conn.Close()
+		close(handled)
+	})
+
+	conn, _, err := dial(srv, "vsn_abc123", "https://app.arogyasahayak.com")
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, middleware.BearerSubprotocol, conn.Subprotocol())
+	<-handled
+}
+
+func TestSessionWebSocket_NativeClientWithoutOrigin(t *testing.T) {
+	srv, uc := setupServer(t)
+
+	handled := make(chan struct{})
+	uc.EXPECT().AuthorizeSession(gomock.Any(), "vsn_abc123").Return(nil)
+	uc.EXPECT().HandleClientWebSocket(gomock.Any(), gomock.Any(), "vsn_abc123", uint64(0)).Do(func(ctx context.Context, conn *websocket.Conn, sessionID string, lastSeq uint64) {
+		conn.Close()
+		close(handled)
+	})
+
+	conn, _, err := dial(srv, "vsn_abc123", "")
+	require.NoError(t, err)
+	defer conn.Close()
+	<-handled
+}
+
+func TestSessionWebSocket_Resume(t *testing.T) {
+	srv, uc := setupServer(t)
+
+	handled := make(chan struct{})
+	uc.EXPECT().AuthorizeSession(gomock.Any(), "vsn_abc123").Return(nil)
+	uc.EXPECT().HandleClientWebSocket(gomock.Any(), gomock.Any(), "vsn_abc123", uint64(42)).Do(func(ctx context.Context, conn *websocket.Conn, sessionID string, lastSeq uint64) {
+		conn.Close()
+		close(handled)
+	})
+
+	conn, _, err := dialQuery(srv, "vsn_abc123", "last_seq=42", "https://app.arogyasahayak.com")
+	require.NoError(t, err)
+	defer conn.Close()
+	<-handled
+}
+
+func TestSessionWebSocket_RejectsInvalidLastSeq(t *testing.T) {
+	srv, _ := setupServer(t)
+
+	_, resp, err := dialQuery(srv, "vsn_abc123", "last_seq=-1", "https://app.arogyasahayak.com")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestSessionWebSocket_RejectsForeignOrigin(t *testing.T) {
+	srv, uc := setupServer(t)
+
+	uc.EXPECT().AuthorizeSession(gomock.Any(), "vsn_abc123").Return(nil)
+
+	_, resp, err := dial(srv, "vsn_abc123", "https://evil.example")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestSessionWebSocket_RejectsOtherUsersSession(t *testing.T) {
+	srv, uc := setupServer(t)
+
+	uc.EXPECT().AuthorizeSession(gomock.Any(), "vsn_abc123").Return(domain_errors.ErrSessionNotFound)
+
+	_, resp, err := dial(srv, "vsn_abc123", "https://app.arogyasahayak.com")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
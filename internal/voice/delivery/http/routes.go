@@ -0,0 +1,16 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"swasthAI/internal/middleware"
+)
+
+func (h *Handler) MapVoiceRoutes(voice *echo.Group, mw middleware.MiddlewareManager) {
+	// X-Acting-For starts a session on behalf of a patient
+	voice.Use(mw.RequireAuth, mw.ActingFor)
+	voice.POST("/session/start", h.StartSession)
+	voice.GET("/session/:id/ws", h.SessionWebSocket)
+	voice.POST("/session/end", h.EndSession)
+	voice.GET("/protocol", h.Protocol)
+}
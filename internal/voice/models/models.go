@@ -2,63 +2,128 @@
 package models
 
 import (
-	"sync"
+	"errors"
 	"time"
 
-	"github.com/gorilla/websocket"
+	"github.com/uptrace/bun"
+)
+
+var (
+	// ErrTransition is returned when a session cannot move to the requested
+	// status from the one it is in.
+	ErrTransition = errors.New("voice session: status transition not allowed")
+	// ErrSessionLimit is returned when a session cannot be created because
+	// its user already holds as many live sessions as allowed.
+	ErrSessionLimit = errors.New("voice session: too many live sessions")
+	// ErrSessionExists is returned when a session is created under an ID
+	// already in use.
+	ErrSessionExists = errors.New("voice session: ID already in use")
 )
 
 type StartSessionRequest struct {
-	Language    string `json:"language"`
-	Model       string `json:"model"`
-	SessionType string `json:"session_type"`
+	Language    string `json:"language" validate:"required,max=10"`
+	Model       string `json:"model" validate:"max=64"`
+	SessionType string `json:"session_type" validate:"omitempty,oneof=voice text"`
+	// protocol versions the client speaks; empty means version 1
+	ProtocolVersions []int `json:"protocol_versions" validate:"max=8"`
 }
 
 type StartSessionResponse struct {
-	SessionID string `json:"session_id"`
-	WSURL     string `json:"ws_url"`
+	SessionID       string `json:"session_id"`
+	WSURL           string `json:"ws_url"`
+	ProtocolVersion int    `json:"protocol_version"`
 }
 
 type EndSessionRequest struct {
-	SessionID string `json:"session_id"`
+	SessionID string `json:"session_id" validate:"required"`
 }
 
-// Internal session state
-type VoiceSession struct {
-	SessionID string
-	UserID    string
-	Language  string
-	Model     string
-	CreatedAt time.Time
-	ExpiresAt time.Time
-	AiWSConn  *websocket.Conn
-	Status    string
+// SessionStatus is where a session is in its lifecycle:
+// pending → active → ending → closed. A session that is never connected
+// goes straight from pending to ending.
+type SessionStatus string
+
+const (
+	StatusPending SessionStatus = "pending" // started, client not connected yet
+	StatusActive  SessionStatus = "active"  // client connected and relayed
+	StatusEnding  SessionStatus = "ending"  // sockets being closed
+	StatusClosed  SessionStatus = "closed"
+)
+
+var sessionTransitions = map[SessionStatus][]SessionStatus{
+	StatusPending: {StatusActive, StatusEnding},
+	StatusActive:  {StatusEnding},
+	StatusEnding:  {StatusClosed},
 }
 
-// WebSocket transport
-type WSMessage struct {
-	Type string `json:"type"`
+// CanBecome reports whether a session may move from s to next.
+func (s SessionStatus) CanBecome(next SessionStatus) bool {
+	for _, allowed := range sessionTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
 }
 
-// Client → Server
-type TextMessageInput struct {
-	Content string `json:"content,omitempty"`
+// Sources returns the statuses a session may move to next from.
+func (next SessionStatus) Sources() []SessionStatus {
+	var from []SessionStatus
+	for s := range sessionTransitions {
+		if s.CanBecome(next) {
+			from = append(from, s)
+		}
+	}
+	return from
 }
 
-type EndOfInput struct{}
+// Live reports whether a session in status s still holds connections.
+func (s SessionStatus) Live() bool {
+	return s == StatusPending || s == StatusActive
+}
 
-// Server → Client
-type Transcript struct {
-	Text string `json:"text,omitempty"`
+// LiveStatuses are the statuses for which Live is true.
+var LiveStatuses = []SessionStatus{StatusPending, StatusActive}
+
+// ActiveStatuses are the live statuses and StatusEnding, whose sockets are
+// still being closed; closed sessions are only waiting to be deleted.
+var ActiveStatuses = []SessionStatus{StatusPending, StatusActive, StatusEnding}
+
+// VoiceSession is the metadata of a session, shared by every instance. The
+// connections themselves stay with the instance named by Node.
+type VoiceSession struct {
+	bun.BaseModel `bun:"table:voice_sessions"`
+
+	SessionID string        `bun:",pk" json:"session_id"`
+	UserID    string        `bun:",notnull" json:"user_id"`
+	PatientID string        `bun:",nullzero" json:"patient_id,omitempty"`
+	Language  string        `bun:",notnull" json:"language"`
+	Model     string        `bun:",nullzero" json:"model,omitempty"`
+	CreatedAt time.Time     `bun:",notnull" json:"created_at"`
+	ExpiresAt time.Time     `bun:",notnull" json:"expires_at"`
+	Status    SessionStatus `bun:",notnull" json:"status"`
+
+	ProtocolVersion int    `bun:",notnull" json:"protocol_version"`
+	EndReason       string `bun:",nullzero" json:"end_reason,omitempty"` // why the session is ending, see protocol.EndReason*
+	Node            string `bun:",notnull" json:"node"`                  // instance holding the connections
 }
 
-type AIText struct {
-	Text string `json:"ai_text,omitempty"`
+// AISessionRequest asks the AI service to start its pipeline for a session.
+type AISessionRequest struct {
+	SessionID string `json:"session_id"`
+	Language  string `json:"language"`
+	Model     string `json:"model,omitempty"`
 }
 
-type EndOfResponse struct{}
+// AISessionResponse names the socket the AI service streams the session on.
+type AISessionResponse struct {
+	SessionID string `json:"session_id"`
+	WSURL     string `json:"ws_url"`
+}
 
-type SessionStrore struct {
-	sessions map[string]*VoiceSession
-	mu       sync.RWMutex
+// AIMessage is a text frame exchanged with the AI service.
+type AIMessage struct {
+	Type    string `json:"type"`
+	Text    string `json:"text,omitempty"`
+	Content string `json:"content,omitempty"` // text_message
 }
@@ -0,0 +1,158 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrBadMessage  = errors.New("voice protocol: malformed message")
+	ErrUnknownType = errors.New("voice protocol: unknown message type")
+	ErrVersion     = errors.New("voice protocol: unsupported version")
+)
+
+// clientTypes are the text messages a client may send.
+var clientTypes = map[MessageType]bool{
+	TypeEndOfInput:  true,
+	TypeTextMessage: true,
+	TypeAck:         true,
+}
+
+// DecodeClient parses a client text frame. The envelope is returned even
+// when its type is unknown, so the error can name it.
+func DecodeClient(data []byte, version int) (*Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Type == "" {
+		return nil, ErrBadMessage
+	}
+	if env.Version != version {
+		return &env, fmt.Errorf("%w: %d", ErrVersion, env.Version)
+	}
+	if !clientTypes[env.Type] {
+		return &env, fmt.Errorf("%w: %q", ErrUnknownType, env.Type)
+	}
+	return &env, nil
+}
+
+// DecodePayload unmarshals the envelope's payload into v. A missing
+// payload leaves v unchanged.
+func (e *Envelope) DecodePayload(v any) error {
+	if len(e.Payload) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return ErrBadMessage
+	}
+	return nil
+}
+
+// AudioHeaderSize is the length of the header in front of every ai_audio
+// binary frame: version (1 byte), seq (8 bytes) and turn (4 bytes), big
+// endian.
+const AudioHeaderSize = 13
+
+// AudioHeader sequences an ai_audio binary frame.
+type AudioHeader struct {
+	Version int
+	Seq     uint64
+	Turn    uint32
+}
+
+// DecodeAudio splits an ai_audio binary frame into its header and audio.
+func DecodeAudio(frame []byte) (AudioHeader, []byte, error) {
+	if len(frame) < AudioHeaderSize {
+		return AudioHeader{}, nil, ErrBadMessage
+	}
+	h := AudioHeader{
+		Version: int(frame[0]),
+		Seq:     binary.BigEndian.Uint64(frame[1:9]),
+		Turn:    binary.BigEndian.Uint32(frame[9:13]),
+	}
+	return h, frame[AudioHeaderSize:], nil
+}
+
+// Sequencer stamps the messages the server sends on one session with the
+// negotiated version, the next sequence number and the current turn. It is
+// safe for concurrent use; messages are numbered in the order they are
+// encoded.
+type Sequencer struct {
+	version int
+	now     func() time.Time
+
+	mu       sync.Mutex
+	seq      uint64
+	turn     uint32
+	turnOpen bool // the user is still giving input for turn
+}
+
+func NewSequencer(version int) *Sequencer {
+	return &Sequencer{version: version, now: time.Now}
+}
+
+// Input records user input and returns its turn. Input after EndInput
+// starts the next turn.
+func (s *Sequencer) Input() uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.turnOpen {
+		s.turn++
+		s.turnOpen = true
+	}
+	return s.turn
+}
+
+// EndInput closes the current turn to further input and returns it.
+func (s *Sequencer) EndInput() uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.turnOpen {
+		s.turn++
+	}
+	s.turnOpen = false
+	return s.turn
+}
+
+// Event encodes a text frame of type t carrying payload.
+func (s *Sequencer) Event(t MessageType, payload any) ([]byte, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	return json.Marshal(&Envelope{
+		Version:   s.version,
+		Type:      t,
+		Seq:       s.seq,
+		Turn:      s.turn,
+		Timestamp: s.now().UTC(),
+		Payload:   raw,
+	})
+}
+
+// Last is the sequence number of the last message encoded.
+func (s *Sequencer) Last() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seq
+}
+
+// Audio encodes an ai_audio binary frame.
+func (s *Sequencer) Audio(audio []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+
+	frame := make([]byte, AudioHeaderSize+len(audio))
+	frame[0] = byte(s.version)
+	binary.BigEndian.PutUint64(frame[1:9], s.seq)
+	binary.BigEndian.PutUint32(frame[9:13], s.turn)
+	copy(frame[AudioHeaderSize:], audio)
+	return frame
+}
@@ -0,0 +1,140 @@
+// Package protocol defines the messages exchanged with clients over a
+// voice session WebSocket.
+//
+// Text frames carry a JSON Envelope. Audio goes in binary frames: the
+// client sends raw audio chunks, the server prefixes each TTS chunk with
+// an AudioHeader so that it is sequenced like every other server message.
+package protocol
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Protocol versions. A client lists the versions it speaks when starting a
+// session and the server picks the highest one it shares.
+const (
+	Version1 = 1
+
+	CurrentVersion = Version1
+)
+
+// SupportedVersions are the versions this server speaks, newest first.
+var SupportedVersions = []int{Version1}
+
+// Negotiate picks the highest version offered by the client that the
+// server supports. A client that offers none is assumed to speak Version1.
+func Negotiate(offered []int) (int, bool) {
+	if len(offered) == 0 {
+		return Version1, true
+	}
+	for _, v := range SupportedVersions {
+		for _, o := range offered {
+			if o == v {
+				return v, true
+			}
+		}
+	}
+	return 0, false
+}
+
+type MessageType string
+
+// Client → server
+const (
+	TypeAudioChunk  MessageType = "audio_chunk" // binary frame
+	TypeEndOfInput  MessageType = "end_of_input"
+	TypeTextMessage MessageType = "text_message"
+	TypeAck         MessageType = "ack"
+)
+
+// Server → client
+const (
+	TypePartialTranscript MessageType = "partial_transcript"
+	TypeFinalTranscript   MessageType = "final_transcript"
+	TypeAIText            MessageType = "ai_text"
+	TypeAIAudio           MessageType = "ai_audio" // binary frame
+	TypeEndOfResponse     MessageType = "end_of_response"
+	TypeError             MessageType = "error"
+	TypeSessionEnding     MessageType = "session_ending"
+	TypeSessionResumed    MessageType = "session_resumed"
+)
+
+// Envelope wraps every text frame. Seq counts the sender's messages from 1,
+// binary ones included, so that a receiver can tell what it missed. Turn
+// numbers the user's utterances; server messages carry the turn they
+// answer.
+type Envelope struct {
+	Version   int             `json:"v"`
+	Type      MessageType     `json:"type"`
+	Seq       uint64          `json:"seq"`
+	Turn      uint32          `json:"turn,omitempty"`
+	Timestamp time.Time       `json:"ts"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// TextMessage is a typed query sent instead of speech.
+type TextMessage struct {
+	Content string `json:"content"`
+}
+
+// Ack tells the server the client received every message up to Seq, so
+// they need not be kept for a resume.
+type Ack struct {
+	Seq uint64 `json:"seq"`
+}
+
+// EndOfInput marks that the user stopped speaking.
+type EndOfInput struct{}
+
+// Transcript is the payload of partial_transcript and final_transcript.
+type Transcript struct {
+	Text string `json:"text"`
+}
+
+// AIText is a streamed piece of the AI's answer.
+type AIText struct {
+	Text string `json:"text"`
+}
+
+// EndOfResponse marks that the AI finished answering the turn.
+type EndOfResponse struct{}
+
+// Error codes sent to the client.
+const (
+	ErrCodeBadMessage    = "bad_message"
+	ErrCodeUnknownType   = "unknown_type"
+	ErrCodeVersion       = "unsupported_version"
+	ErrCodeAIUnavailable = "ai_unavailable"
+)
+
+// Error reports a problem with the session. Fatal errors are followed by
+// session_ending.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Fatal   bool   `json:"fatal,omitempty"`
+}
+
+// Reasons a session ends.
+const (
+	EndReasonClosed        = "closed"         // ended by the user
+	EndReasonExpired       = "expired"        // reached its maximum duration
+	EndReasonIdle          = "idle"           // no audio or messages for too long
+	EndReasonReplaced      = "replaced"       // the user started another session
+	EndReasonAIUnavailable = "ai_unavailable" // the AI service went away
+	EndReasonDisconnected  = "disconnected"   // the client did not come back in time
+)
+
+// SessionEnding is the last message of a session before the socket closes.
+type SessionEnding struct {
+	Reason string `json:"reason"`
+}
+
+// SessionResumed follows the messages replayed to a client that
+// reconnected. Complete is false when some of what it missed was no
+// longer kept.
+type SessionResumed struct {
+	Replayed int  `json:"replayed"`
+	Complete bool `json:"complete"`
+}
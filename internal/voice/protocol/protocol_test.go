@@ -0,0 +1,122 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	v, ok := Negotiate(nil)
+	assert.True(t, ok)
+	assert.Equal(t, Version1, v)
+
+	v, ok = Negotiate([]int{3, 2, 1})
+	assert.True(t, ok)
+	assert.Equal(t, Version1, v)
+
+	_, ok = Negotiate([]int{7})
+	assert.False(t, ok)
+}
+
+func TestDecodeClient(t *testing.T) {
+	env, err := DecodeClient([]byte(`{"v":1,"type":"text_message","seq":4,"payload":{"content":"Show my blood report"}}`), Version1)
+	require.NoError(t, err)
+	var msg TextMessage
+	require.NoError(t, env.DecodePayload(&msg))
+	assert.Equal(t, "Show my blood report", msg.Content)
+	assert.Equal(t, uint64(4), env.Seq)
+
+	_, err = DecodeClient([]byte(`{"v":1,"type":"ai_text"}`), Version1)
+	assert.True(t, errors.Is(err, ErrUnknownType), err)
+
+	_, err = DecodeClient([]byte(`{"v":2,"type":"end_of_input"}`), Version1)
+	assert.True(t, errors.Is(err, ErrVersion), err)
+
+	// the unversioned messages of the original protocol are not accepted
+	_, err = DecodeClient([]byte(`{"type":"end_of_input"}`), Version1)
+	assert.True(t, errors.Is(err, ErrVersion), err)
+
+	_, err = DecodeClient([]byte(`not json`), Version1)
+	assert.Equal(t, ErrBadMessage, err)
+}
+
+func TestSequencer_Event(t *testing.T) {
+	s := NewSequencer(Version1)
+	assert.Equal(t, uint32(1), s.Input())
+	assert.Equal(t, uint32(1), s.Input())
+
+	frame, err := s.Event(TypeFinalTranscript, Transcript{Text: "show me my blood report"})
+	require.NoError(t, err)
+	var env Envelope
+	require.NoError(t, json.Unmarshal(frame, &env))
+	assert.Equal(t, Version1, env.Version)
+	assert.Equal(t, TypeFinalTranscript, env.Type)
+	assert.Equal(t, uint64(1), env.Seq)
+	assert.Equal(t, uint32(1), env.Turn)
+	assert.False(t, env.Timestamp.IsZero())
+	assert.JSONEq(t, `{"text":"show me my blood report"}`, string(env.Payload))
+
+	// the next utterance is a new turn
+	assert.Equal(t, uint32(1), s.EndInput())
+	assert.Equal(t, uint32(2), s.Input())
+	// a text message on its own is a whole turn
+	assert.Equal(t, uint32(2), s.EndInput())
+	assert.Equal(t, uint32(3), s.EndInput())
+}
+
+func TestSequencer_Audio(t *testing.T) {
+	s := NewSequencer(Version1)
+	s.Input()
+	_, err := s.Event(TypeAIText, AIText{Text: "Here's what"})
+	require.NoError(t, err)
+
+	h, audio, err := DecodeAudio(s.Audio([]byte{0xAA, 0xBB}))
+	require.NoError(t, err)
+	assert.Equal(t, AudioHeader{Version: Version1, Seq: 2, Turn: 1}, h)
+	assert.Equal(t, []byte{0xAA, 0xBB}, audio)
+
+	_, _, err = DecodeAudio([]byte{1, 2})
+	assert.Equal(t, ErrBadMessage, err)
+}
+
+func TestSequencer_ConcurrentSeqIsUnique(t *testing.T) {
+	s := NewSequencer(Version1)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := map[uint64]bool{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h, _, _ := DecodeAudio(s.Audio(nil))
+			mu.Lock()
+			seen[h.Seq] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, seen, 50)
+}
+
+func TestSchema(t *testing.T) {
+	doc := Schema()
+	_, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	messages := doc["messages"].(map[string]any)
+	assert.Len(t, messages, len(Catalogue))
+	for _, typ := range []MessageType{TypeAudioChunk, TypeEndOfInput, TypeTextMessage, TypePartialTranscript,
+		TypeFinalTranscript, TypeAIText, TypeAIAudio, TypeEndOfResponse, TypeError, TypeSessionEnding} {
+		assert.Contains(t, messages, string(typ))
+	}
+
+	errSchema := messages[string(TypeError)].(map[string]any)["payload"].(map[string]any)
+	assert.Equal(t, []string{"code", "message"}, errSchema["required"])
+	envelope := doc["envelope"].(map[string]any)
+	assert.Equal(t, []string{"v", "type", "seq", "ts"}, envelope["required"])
+}
@@ -0,0 +1,112 @@
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Directions a message travels in.
+const (
+	FromClient = "client_to_server"
+	FromServer = "server_to_client"
+)
+
+// Message describes one entry of the catalogue.
+type Message struct {
+	Type        MessageType
+	Direction   string
+	Binary      bool
+	Description string
+	Payload     any // zero value of the payload type; nil for binary frames
+}
+
+// Catalogue lists every message of the protocol.
+var Catalogue = []Message{
+	{Type: TypeAudioChunk, Direction: FromClient, Binary: true, Description: "Raw 16 kHz mono PCM or Opus audio"},
+	{Type: TypeEndOfInput, Direction: FromClient, Payload: EndOfInput{}, Description: "The user stopped speaking; starts a new turn"},
+	{Type: TypeTextMessage, Direction: FromClient, Payload: TextMessage{}, Description: "A typed query instead of speech; starts a new turn"},
+	{Type: TypeAck, Direction: FromClient, Payload: Ack{}, Description: "Everything up to seq arrived and need not be replayed"},
+	{Type: TypePartialTranscript, Direction: FromServer, Payload: Transcript{}, Description: "Speech recognised so far"},
+	{Type: TypeFinalTranscript, Direction: FromServer, Payload: Transcript{}, Description: "The whole utterance once the user paused"},
+	{Type: TypeAIText, Direction: FromServer, Payload: AIText{}, Description: "A streamed piece of the AI's answer"},
+	{Type: TypeAIAudio, Direction: FromServer, Binary: true, Description: "TTS audio behind a 13 byte header: version, seq and turn"},
+	{Type: TypeEndOfResponse, Direction: FromServer, Payload: EndOfResponse{}, Description: "The AI finished answering the turn"},
+	{Type: TypeError, Direction: FromServer, Payload: Error{}, Description: "A message was rejected or the session failed"},
+	{Type: TypeSessionEnding, Direction: FromServer, Payload: SessionEnding{}, Description: "Last message before the server closes the socket"},
+	{Type: TypeSessionResumed, Direction: FromServer, Payload: SessionResumed{}, Description: "Sent after replaying what a reconnected client missed"},
+}
+
+// Schema returns the catalogue as a JSON Schema document: the envelope,
+// and for every message type its direction, frame kind and payload.
+func Schema() map[string]any {
+	messages := make(map[string]any, len(Catalogue))
+	for _, m := range Catalogue {
+		entry := map[string]any{
+			"direction":   m.Direction,
+			"frame":       "text",
+			"description": m.Description,
+		}
+		if m.Binary {
+			entry["frame"] = "binary"
+		} else {
+			entry["payload"] = typeSchema(reflect.TypeOf(m.Payload))
+		}
+		messages[string(m.Type)] = entry
+	}
+
+	return map[string]any{
+		"$schema":            "https://json-schema.org/draft/2020-12/schema",
+		"title":              "Voice session protocol",
+		"version":            CurrentVersion,
+		"supported_versions": SupportedVersions,
+		"envelope":           typeSchema(reflect.TypeOf(Envelope{})),
+		"audio_header": map[string]any{
+			"size":   AudioHeaderSize,
+			"fields": []string{"version:uint8", "seq:uint64", "turn:uint32"},
+			"order":  "big-endian",
+		},
+		"messages": messages,
+	}
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+func typeSchema(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawType:
+		return map[string]any{"type": "object"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Struct:
+		properties := map[string]any{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			properties[name] = typeSchema(f.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		return map[string]any{"type": "object", "properties": properties, "required": required, "additionalProperties": false}
+	}
+	return map[string]any{}
+}
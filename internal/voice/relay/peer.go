@@ -0,0 +1,258 @@
+// Package relay moves frames between the two WebSocket connections of a
+// voice session. Each connection gets a Peer with its own read and write
+// pump, so that every connection has exactly one reader and one writer.
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"swasthAI/config"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrOverflow = errors.New("voice relay: send queue overflowed")
+	ErrClosed   = errors.New("voice relay: connection closed")
+)
+
+// OverflowPolicy says what happens when a peer's send queue is full.
+type OverflowPolicy string
+
+const (
+	// DropOldestAudio discards the oldest queued audio frame. On a slow
+	// link a gap in speech beats an ever growing delay.
+	DropOldestAudio OverflowPolicy = "drop_oldest"
+	// Disconnect closes the connection.
+	Disconnect OverflowPolicy = "disconnect"
+)
+
+type Config struct {
+	QueueSize      int
+	Overflow       OverflowPolicy
+	PingInterval   time.Duration
+	PongTimeout    time.Duration // a peer silent for longer is dropped
+	WriteTimeout   time.Duration
+	MaxMessageSize int64
+}
+
+func NewConfig(cfg config.Voice) Config {
+	return Config{
+		QueueSize:      cfg.QueueSize,
+		Overflow:       OverflowPolicy(cfg.Overflow),
+		PingInterval:   time.Duration(cfg.PingInterval) * time.Second,
+		PongTimeout:    time.Duration(cfg.PongTimeout) * time.Second,
+		WriteTimeout:   time.Duration(cfg.WriteTimeout) * time.Second,
+		MaxMessageSize: cfg.MaxMessageSize,
+	}
+}
+
+// Validate rejects settings the pumps cannot run with, such as a zero ping
+// interval, which would panic, or an empty queue, which would overflow on
+// the first frame.
+func (c Config) Validate() error {
+	switch {
+	case c.QueueSize < 1:
+		return fmt.Errorf("voice.queuesize must be at least 1, got %d", c.QueueSize)
+	case c.Overflow != DropOldestAudio && c.Overflow != Disconnect:
+		return fmt.Errorf("voice.overflow must be %q or %q, got %q", DropOldestAudio, Disconnect, c.Overflow)
+	case c.PingInterval <= 0:
+		return fmt.Errorf("voice.pinginterval must be positive, got %s", c.PingInterval)
+	case c.PongTimeout <= c.PingInterval:
+		// a healthy peer would be dropped between two pings
+		return fmt.Errorf("voice.pongtimeout (%s) must be longer than voice.pinginterval (%s)", c.PongTimeout, c.PingInterval)
+	case c.WriteTimeout <= 0:
+		return fmt.Errorf("voice.writetimeout must be positive, got %s", c.WriteTimeout)
+	case c.MaxMessageSize <= 0:
+		return fmt.Errorf("voice.maxmessagesize must be positive, got %d", c.MaxMessageSize)
+	}
+	return nil
+}
+
+// Peer owns one connection. Frames read from it arrive on Incoming;
+// frames passed to Send are queued and written by the write pump, which
+// also sends keepalive pings.
+type Peer struct {
+	conn *websocket.Conn
+	cfg  Config
+	out  *queue
+	in   chan Frame
+
+	closing   chan struct{}
+	closeOnce sync.Once
+	mu        sync.Mutex
+	err       error
+
+	wg   sync.WaitGroup
+	done chan struct{}
+}
+
+func NewPeer(conn *websocket.Conn, cfg Config) *Peer {
+	return &Peer{
+		conn:    conn,
+		cfg:     cfg,
+		out:     newQueue(cfg.QueueSize, cfg.Overflow),
+		in:      make(chan Frame),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start runs the pumps until Close is called, ctx is cancelled or the
+// connection fails.
+func (p *Peer) Start(ctx context.Context) {
+	p.wg.Add(2)
+	go p.readPump()
+	go p.writePump(ctx)
+	go func() {
+		p.wg.Wait()
+		close(p.done)
+	}()
+}
+
+// Incoming delivers the frames read from the connection. It is closed when
+// the read pump stops.
+func (p *Peer) Incoming() <-chan Frame {
+	return p.in
+}
+
+// Send queues f for writing. If the queue overflows under the Disconnect
+// policy, or has no audio left to drop, the peer is closed with
+// ErrOverflow.
+func (p *Peer) Send(f Frame) error {
+	select {
+	case <-p.closing:
+		return ErrClosed
+	default:
+	}
+	if err := p.out.push(f); err != nil {
+		p.Close(err)
+		return err
+	}
+	return nil
+}
+
+// Close stops the peer. Frames already queued are still written, within one
+// write timeout, before the close frame. err is the reason reported by
+// Err; nil is a normal close. Only the first call has an effect.
+func (p *Peer) Close(err error) {
+	p.closeOnce.Do(func() {
+		p.mu.Lock()
+		p.err = err
+		p.mu.Unlock()
+		close(p.closing)
+	})
+}
+
+// Done is closed once both pumps have stopped and the connection is
+// closed.
+func (p *Peer) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err is why the peer closed.
+func (p *Peer) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// Dropped is the number of audio frames discarded by DropOldestAudio.
+func (p *Peer) Dropped() int {
+	return p.out.droppedCount()
+}
+
+func (p *Peer) readPump() {
+	defer p.wg.Done()
+	defer close(p.in)
+
+	p.conn.SetReadLimit(p.cfg.MaxMessageSize)
+	p.extendReadDeadline()
+	p.conn.SetPongHandler(func(string) error {
+		p.extendReadDeadline()
+		return nil
+	})
+
+	for {
+		msgType, data, err := p.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				err = ErrClosed
+			}
+			p.Close(err)
+			return
+		}
+		p.extendReadDeadline()
+
+		select {
+		case p.in <- Frame{Type: msgType, Data: data}:
+		case <-p.closing:
+			return
+		}
+	}
+}
+
+func (p *Peer) extendReadDeadline() {
+	p.conn.SetReadDeadline(time.Now().Add(p.cfg.PongTimeout))
+}
+
+func (p *Peer) writePump(ctx context.Context) {
+	defer p.wg.Done()
+	defer p.conn.Close()
+
+	ping := time.NewTicker(p.cfg.PingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-p.out.ready:
+			for _, f := range p.out.drain() {
+				p.conn.SetWriteDeadline(time.Now().Add(p.cfg.WriteTimeout))
+				if err := p.conn.WriteMessage(f.Type, f.Data); err != nil {
+					p.Close(err)
+					return
+				}
+			}
+		case <-ping.C:
+			if err := p.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(p.cfg.WriteTimeout)); err != nil {
+				p.Close(err)
+				return
+			}
+		case <-ctx.Done():
+			p.Close(ctx.Err())
+			p.flush()
+			return
+		case <-p.closing:
+			p.flush()
+			return
+		}
+	}
+}
+
+// flush writes what is still queued and the close frame.
+func (p *Peer) flush() {
+	deadline := time.Now().Add(p.cfg.WriteTimeout)
+	p.conn.SetWriteDeadline(deadline)
+	for _, f := range p.out.drain() {
+		if p.conn.WriteMessage(f.Type, f.Data) != nil {
+			return
+		}
+	}
+	p.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode(p.Err()), ""), deadline)
+}
+
+func closeCode(err error) int {
+	switch {
+	case err == nil:
+		return websocket.CloseNormalClosure
+	case errors.Is(err, ErrOverflow):
+		return websocket.ClosePolicyViolation
+	case errors.Is(err, context.Canceled):
+		return websocket.CloseGoingAway
+	}
+	return websocket.CloseInternalServerErr
+}
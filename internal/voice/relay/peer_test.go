@@ -0,0 +1,215 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	return Config{
+		QueueSize:      8,
+		Overflow:       DropOldestAudio,
+		PingInterval:   time.Hour,
+		PongTimeout:    time.Hour,
+		WriteTimeout:   time.Second,
+		MaxMessageSize: 1 << 16,
+	}
+}
+
+// pair returns the server and client ends of a WebSocket connection.
+func pair(t *testing.T) (server, client *websocket.Conn) {
+	conns := make(chan *websocket.Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		require.NoError(t, err)
+		conns <- conn
+	}))
+	t.Cleanup(srv.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return <-conns, client
+}
+
+func waitDone(t *testing.T, p *Peer) {
+	select {
+	case <-p.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("peer did not stop")
+	}
+}
+
+func TestPeer_RoundTrip(t *testing.T) {
+	server, client := pair(t)
+	p := NewPeer(server, testConfig())
+	p.Start(context.Background())
+
+	require.NoError(t, client.WriteMessage(websocket.BinaryMessage, []byte{1, 2, 3}))
+	f := <-p.Incoming()
+	assert.Equal(t, Frame{Type: websocket.BinaryMessage, Data: []byte{1, 2, 3}}, f)
+
+	require.NoError(t, p.Send(Frame{Type: websocket.TextMessage, Data: []byte(`{"type":"ai_text"}`)}))
+	msgType, data, err := client.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.TextMessage, msgType)
+	assert.Equal(t, `{"type":"ai_text"}`, string(data))
+
+	// a normal close from the other side closes Incoming
+	client.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	_, ok := <-p.Incoming()
+	assert.False(t, ok)
+	waitDone(t, p)
+	assert.ErrorIs(t, p.Err(), ErrClosed)
+}
+
+func TestPeer_CloseFlushesQueue(t *testing.T) {
+	server, client := pair(t)
+	p := NewPeer(server, testConfig())
+
+	// queued before the pumps run, so all of it waits for the flush
+	for i := 0; i < 3; i++ {
+		require.NoError(t, p.Send(Frame{Type: websocket.TextMessage, Data: []byte{'a' + byte(i)}}))
+	}
+	p.Close(nil)
+	p.Start(context.Background())
+	waitDone(t, p)
+
+	for _, want := range []string{"a", "b", "c"} {
+		_, data, err := client.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, want, string(data))
+	}
+	_, _, err := client.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), err)
+	assert.ErrorIs(t, p.Send(Frame{Type: websocket.TextMessage}), ErrClosed)
+}
+
+func TestPeer_ContextCancel(t *testing.T) {
+	server, client := pair(t)
+	p := NewPeer(server, testConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	p.Start(ctx)
+
+	cancel()
+	waitDone(t, p)
+	assert.ErrorIs(t, p.Err(), context.Canceled)
+
+	_, _, err := client.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+}
+
+func TestPeer_DisconnectOnOverflow(t *testing.T) {
+	server, client := pair(t)
+	cfg := testConfig()
+	cfg.QueueSize = 2
+	cfg.Overflow = Disconnect
+	p := NewPeer(server, cfg)
+
+	require.NoError(t, p.Send(Frame{Type: websocket.BinaryMessage, Data: []byte{1}}))
+	require.NoError(t, p.Send(Frame{Type: websocket.BinaryMessage, Data: []byte{2}}))
+	assert.ErrorIs(t, p.Send(Frame{Type: websocket.BinaryMessage, Data: []byte{3}}), ErrOverflow)
+	p.Start(context.Background())
+	waitDone(t, p)
+	assert.ErrorIs(t, p.Err(), ErrOverflow)
+
+	// what was queued is still delivered, then the policy violation
+	for i := 0; i < 2; i++ {
+		_, _, err := client.ReadMessage()
+		require.NoError(t, err)
+	}
+	_, _, err := client.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), err)
+}
+
+func TestPeer_DropsSilentConnection(t *testing.T) {
+	server, _ := pair(t)
+	cfg := testConfig()
+	cfg.PingInterval = 20 * time.Millisecond
+	cfg.PongTimeout = 100 * time.Millisecond
+	p := NewPeer(server, cfg)
+	p.Start(context.Background())
+
+	// the client never reads, so it never answers a ping
+	waitDone(t, p)
+	var netErr net.Error
+	require.True(t, errors.As(p.Err(), &netErr), p.Err())
+	assert.True(t, netErr.Timeout())
+}
+
+func TestPeer_PongsKeepConnectionAlive(t *testing.T) {
+	server, client := pair(t)
+	cfg := testConfig()
+	cfg.PingInterval = 20 * time.Millisecond
+	cfg.PongTimeout = 100 * time.Millisecond
+	p := NewPeer(server, cfg)
+	p.Start(context.Background())
+
+	// reading lets the client answer pings
+	go func() {
+		for {
+			if _, _, err := client.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-p.Done():
+		t.Fatalf("peer closed: %v", p.Err())
+	case <-time.After(400 * time.Millisecond):
+	}
+	p.Close(nil)
+	waitDone(t, p)
+	assert.NoError(t, p.Err())
+}
+
+func TestQueue_DropOldestAudio(t *testing.T) {
+	q := newQueue(3, DropOldestAudio)
+	require.NoError(t, q.push(Frame{Type: websocket.BinaryMessage, Data: []byte{1}}))
+	require.NoError(t, q.push(Frame{Type: websocket.TextMessage, Data: []byte("t")}))
+	require.NoError(t, q.push(Frame{Type: websocket.BinaryMessage, Data: []byte{2}}))
+	require.NoError(t, q.push(Frame{Type: websocket.BinaryMessage, Data: []byte{3}}))
+
+	assert.Equal(t, []Frame{
+		{Type: websocket.TextMessage, Data: []byte("t")},
+		{Type: websocket.BinaryMessage, Data: []byte{2}},
+		{Type: websocket.BinaryMessage, Data: []byte{3}},
+	}, q.drain())
+	assert.Equal(t, 1, q.droppedCount())
+
+	// text is never dropped
+	q = newQueue(1, DropOldestAudio)
+	require.NoError(t, q.push(Frame{Type: websocket.TextMessage}))
+	assert.ErrorIs(t, q.push(Frame{Type: websocket.BinaryMessage}), ErrOverflow)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := NewConfig(config.Voice{QueueSize: 256, Overflow: "drop_oldest", PingInterval: 20, PongTimeout: 45, WriteTimeout: 10, MaxMessageSize: 1 << 20})
+	require.NoError(t, valid.Validate())
+
+	for name, breakIt := range map[string]func(*Config){
+		"no queue":         func(c *Config) { c.QueueSize = 0 },
+		"unknown overflow": func(c *Config) { c.Overflow = "block" },
+		"no ping interval": func(c *Config) { c.PingInterval = 0 },
+		"pong before ping": func(c *Config) { c.PongTimeout = c.PingInterval },
+		"no write timeout": func(c *Config) { c.WriteTimeout = 0 },
+		"no message size":  func(c *Config) { c.MaxMessageSize = 0 },
+	} {
+		cfg := valid
+		breakIt(&cfg)
+		assert.Error(t, cfg.Validate(), name)
+	}
+}
@@ -0,0 +1,80 @@
+package relay
+
+import (
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// Frame is one WebSocket message.
+type Frame struct {
+	Type int // websocket.TextMessage or websocket.BinaryMessage
+	Data []byte
+}
+
+func (f Frame) isAudio() bool {
+	return f.Type == websocket.BinaryMessage
+}
+
+// queue is a bounded FIFO of frames waiting to be written. ready holds a
+// token whenever the queue may be non-empty.
+type queue struct {
+	mu     sync.Mutex
+	frames []Frame
+	size   int
+	policy OverflowPolicy
+	ready  chan struct{}
+	// dropped counts audio frames discarded by DropOldestAudio
+	dropped int
+}
+
+func newQueue(size int, policy OverflowPolicy) *queue {
+	return &queue{size: size, policy: policy, ready: make(chan struct{}, 1)}
+}
+
+// push appends f, applying the overflow policy when the queue is full.
+// Text frames are never dropped: if nothing can make room, the queue has
+// overflowed.
+func (q *queue) push(f Frame) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.frames) >= q.size {
+		if q.policy != DropOldestAudio || !q.dropOldestAudio() {
+			return ErrOverflow
+		}
+	}
+	q.frames = append(q.frames, f)
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (q *queue) dropOldestAudio() bool {
+	for i, f := range q.frames {
+		if f.isAudio() {
+			q.frames = append(q.frames[:i], q.frames[i+1:]...)
+			q.dropped++
+			return true
+		}
+	}
+	return false
+}
+
+// drain removes and returns every queued frame.
+func (q *queue) drain() []Frame {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	frames := q.frames
+	q.frames = nil
+	return frames
+}
+
+func (q *queue) droppedCount() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
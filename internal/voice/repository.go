@@ -3,14 +3,28 @@ package voice
 import (
 	"context"
 	"swasthAI/internal/voice/models"
-
-	"github.com/google/uuid"
 )
 
+// SessionRepository stores session metadata. Sessions are returned as
+// copies; changes are only seen by others once saved.
 type SessionRepository interface {
-	CreateSession(ctx context.Context, session *models.VoiceSession) error
-	GetSession(ctx context.Context, id uuid.UUID) (*models.VoiceSession, error)
+	// CreateSession saves a new session unless its user already holds limit
+	// live sessions, in which case it fails with models.ErrSessionLimit.
+	// The check and the insert are atomic; a limit of 0 means no limit. An
+	// existing session is never overwritten: its ID fails with
+	// models.ErrSessionExists.
+	CreateSession(ctx context.Context, session *models.VoiceSession, limit int) error
+	GetSession(ctx context.Context, id string) (*models.VoiceSession, error)
 	UpdateSession(ctx context.Context, session *models.VoiceSession) error
-	DeleteSession(ctx context.Context, id uuid.UUID) error
+	// Transition atomically moves a session to next, recording endReason
+	// when it is not empty, and returns the session as saved. It fails
+	// with models.ErrTransition when the current status does not allow it.
+	Transition(ctx context.Context, id string, next models.SessionStatus, endReason string) (*models.VoiceSession, error)
+	DeleteSession(ctx context.Context, id string) error
+	// DeleteUserSessions deletes every session of a user, whatever its
+	// status, and returns how many there were. It erases an account.
+	DeleteUserSessions(ctx context.Context, userID string) (int, error)
+	// ListActiveSessions returns the sessions in one of
+	// models.ActiveStatuses.
 	ListActiveSessions(ctx context.Context) ([]models.VoiceSession, error)
 }
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"swasthAI/internal/voice/models"
+	"swasthAI/pkg/domain_errors"
+)
+
+// InMemorySessionRepository keeps sessions in process memory. They are lost
+// on restart and not shared between instances, so it suits a single
+// instance or local development.
+type InMemorySessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[string]models.VoiceSession
+}
+
+func NewInMemorySessionRepository() *InMemorySessionRepository {
+	return &InMemorySessionRepository{sessions: make(map[string]models.VoiceSession)}
+}
+
+func (sr *InMemorySessionRepository) CreateSession(ctx context.Context, session *models.VoiceSession, limit int) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	if _, ok := sr.sessions[session.SessionID]; ok {
+		return models.ErrSessionExists
+	}
+	if limit > 0 {
+		live := 0
+		for _, s := range sr.sessions {
+			if s.UserID == session.UserID && s.Status.Live() {
+				live++
+			}
+		}
+		if live >= limit {
+			return models.ErrSessionLimit
+		}
+	}
+	sr.sessions[session.SessionID] = *session
+	return nil
+}
+
+func (sr *InMemorySessionRepository) GetSession(ctx context.Context, id string) (*models.VoiceSession, error) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+	session, ok := sr.sessions[id]
+	if !ok {
+		return nil, domain_errors.ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+func (sr *InMemorySessionRepository) UpdateSession(ctx context.Context, session *models.VoiceSession) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	if _, ok := sr.sessions[session.SessionID]; !ok {
+		return domain_errors.ErrSessionNotFound
+	}
+	sr.sessions[session.SessionID] = *session
+	return nil
+}
+
+func (sr *InMemorySessionRepository) Transition(ctx context.Context, id string, next models.SessionStatus, endReason string) (*models.VoiceSession, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	session, ok := sr.sessions[id]
+	if !ok {
+		return nil, domain_errors.ErrSessionNotFound
+	}
+	if !session.Status.CanBecome(next) {
+		return nil, models.ErrTransition
+	}
+	session.Status = next
+	if endReason != "" {
+		session.EndReason = endReason
+	}
+	sr.sessions[id] = session
+	return &session, nil
+}
+
+func (sr *InMemorySessionRepository) DeleteSession(ctx context.Context, id string) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	delete(sr.sessions, id)
+	return nil
+}
+
+func (sr *InMemorySessionRepository) DeleteUserSessions(ctx context.Context, userID string) (int, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	deleted := 0
+	for id, session := range sr.sessions {
+		if session.UserID == userID {
+			delete(sr.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (sr *InMemorySessionRepository) ListActiveSessions(ctx context.Context) ([]models.VoiceSession, error) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+	sessions := make([]models.VoiceSession, 0, len(sr.sessions))
+	for _, session := range sr.sessions {
+		if slices.Contains(models.ActiveStatuses, session.Status) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
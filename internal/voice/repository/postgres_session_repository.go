@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"swasthAI/internal/voice/models"
+	"swasthAI/pkg/domain_errors"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+// PostgresSessionRepository keeps sessions in the voice_sessions table,
+// shared by every instance of the service.
+type PostgresSessionRepository struct {
+	db *bun.DB
+}
+
+func NewPostgresSessionRepository(db *bun.DB) *PostgresSessionRepository {
+	return &PostgresSessionRepository{db: db}
+}
+
+func (r *PostgresSessionRepository) CreateSession(ctx context.Context, session *models.VoiceSession, limit int) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if limit > 0 {
+			// serialises the creates of one user, so that two of them
+			// cannot both see a free slot
+			if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "voice_sessions:"+session.UserID); err != nil {
+				return errors.Wrap(err, "voiceSessionRepo.CreateSession.Lock")
+			}
+			live, err := tx.NewSelect().
+				Model((*models.VoiceSession)(nil)).
+				Where("user_id = ?", session.UserID).
+				Where("status IN (?)", bun.In(models.LiveStatuses)).
+				Count(ctx)
+			if err != nil {
+				return errors.Wrap(err, "voiceSessionRepo.CreateSession.Count")
+			}
+			if live >= limit {
+				return models.ErrSessionLimit
+			}
+		}
+		res, err := tx.NewInsert().Model(session).On("CONFLICT (session_id) DO NOTHING").Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "voiceSessionRepo.CreateSession.Insert")
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return models.ErrSessionExists
+		}
+		return nil
+	})
+}
+
+func (r *PostgresSessionRepository) GetSession(ctx context.Context, id string) (*models.VoiceSession, error) {
+	session := new(models.VoiceSession)
+	err := r.db.NewSelect().Model(session).Where("session_id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain_errors.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "voiceSessionRepo.GetSession.Select")
+	}
+	return session, nil
+}
+
+func (r *PostgresSessionRepository) UpdateSession(ctx context.Context, session *models.VoiceSession) error {
+	res, err := r.db.NewUpdate().Model(session).WherePK().Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "voiceSessionRepo.UpdateSession.Update")
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain_errors.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (r *PostgresSessionRepository) Transition(ctx context.Context, id string, next models.SessionStatus, endReason string) (*models.VoiceSession, error) {
+	session := new(models.VoiceSession)
+	q := r.db.NewUpdate().
+		Model(session).
+		Set("status = ?", next).
+		Where("session_id = ?", id).
+		Where("status IN (?)", bun.In(next.Sources())).
+		Returning("*")
+	if endReason != "" {
+		q = q.Set("end_reason = ?", endReason)
+	}
+	err := q.Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		// either gone or in a status that does not allow next
+		if _, err := r.GetSession(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrTransition
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "voiceSessionRepo.Transition.Update")
+	}
+	return session, nil
+}
+
+func (r *PostgresSessionRepository) DeleteSession(ctx context.Context, id string) error {
+	_, err := r.db.NewDelete().Model((*models.VoiceSession)(nil)).Where("session_id = ?", id).Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "voiceSessionRepo.DeleteSession.Delete")
+	}
+	return nil
+}
+
+func (r *PostgresSessionRepository) DeleteUserSessions(ctx context.Context, userID string) (int, error) {
+	res, err := r.db.NewDelete().Model((*models.VoiceSession)(nil)).Where("user_id = ?", userID).Exec(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "voiceSessionRepo.DeleteUserSessions.Delete")
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+func (r *PostgresSessionRepository) ListActiveSessions(ctx context.Context) ([]models.VoiceSession, error) {
+	var sessions []models.VoiceSession
+	err := r.db.NewSelect().
+		Model(&sessions).
+		Where("status IN (?)", bun.In(models.ActiveStatuses)).
+		Order("created_at").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "voiceSessionRepo.ListActiveSessions.Select")
+	}
+	return sessions, nil
+}
@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/voice/models"
+	"swasthAI/pkg/domain_errors"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisSessionPrefix = "voice:session:"
+	redisSessionIndex  = "voice:sessions"
+	redisUserPrefix    = "voice:user:"
+	// a session key outlives ExpiresAt by this much, so that sessions of a
+	// crashed instance disappear on their own
+	redisSessionGrace = 10 * time.Minute
+	redisTxAttempts   = 5
+)
+
+// RedisSessionRepository keeps each session as a JSON value with a TTL,
+// plus a set of session IDs and one per user.
+type RedisSessionRepository struct {
+	client redis.UniversalClient
+}
+
+func NewRedisSessionRepository(client redis.UniversalClient) *RedisSessionRepository {
+	return &RedisSessionRepository{client: client}
+}
+
+// NewRedisClient connects to the server in cfg.
+func NewRedisClient(cfg config.RedisConfig) *redis.Client {
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	opts := &redis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  timeout,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+		PoolSize:     cfg.PoolSize,
+	}
+	if cfg.TLS {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return redis.NewClient(opts)
+}
+
+func redisSessionKey(id string) string {
+	return redisSessionPrefix + id
+}
+
+// redisUserKey is the set of the sessions of a user. It takes the TTL of
+// the session last added, which being the newest expires last.
+func redisUserKey(userID string) string {
+	return redisUserPrefix + userID + ":sessions"
+}
+
+func (r *RedisSessionRepository) CreateSession(ctx context.Context, session *models.VoiceSession, limit int) error {
+	data, ttl, err := encodeSession(session)
+	if err != nil {
+		return errors.Wrap(err, "voiceSessionRepo.CreateSession.Marshal")
+	}
+	key := redisSessionKey(session.SessionID)
+	userKey := redisUserKey(session.UserID)
+	for attempt := 0; attempt < redisTxAttempts; attempt++ {
+		// watching the user's set and the new key fails the transaction if
+		// another session of theirs, or one with the same ID, was created in
+		// between
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			n, err := tx.Exists(ctx, key).Result()
+			if err != nil {
+				return errors.Wrap(err, "Exists")
+			}
+			if n > 0 {
+				return models.ErrSessionExists
+			}
+			var gone []any
+			if limit > 0 {
+				live, expired, err := r.countLive(ctx, tx, userKey)
+				if err != nil {
+					return err
+				}
+				if live >= limit {
+					return models.ErrSessionLimit
+				}
+				gone = expired
+			}
+			var created *redis.BoolCmd
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				created = pipe.SetNX(ctx, key, data, ttl)
+				pipe.SAdd(ctx, redisSessionIndex, session.SessionID)
+				if len(gone) > 0 {
+					pipe.SRem(ctx, userKey, gone...)
+				}
+				pipe.SAdd(ctx, userKey, session.SessionID)
+				pipe.PExpire(ctx, userKey, ttl)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if !created.Val() {
+				return models.ErrSessionExists
+			}
+			return nil
+		}, userKey, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil && !errors.Is(err, models.ErrSessionLimit) && !errors.Is(err, models.ErrSessionExists) {
+			return errors.Wrap(err, "voiceSessionRepo.CreateSession.Exec")
+		}
+		return err
+	}
+	return errors.Wrap(redis.TxFailedErr, "voiceSessionRepo.CreateSession")
+}
+
+// countLive returns how many sessions in the user set at userKey are live,
+// and the ones that expired.
+func (r *RedisSessionRepository) countLive(ctx context.Context, tx *redis.Tx, userKey string) (int, []any, error) {
+	ids, err := tx.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "Members")
+	}
+	if len(ids) == 0 {
+		return 0, nil, nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = redisSessionKey(id)
+	}
+	values, err := tx.MGet(ctx, keys...).Result()
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "Get")
+	}
+
+	live := 0
+	var gone []any
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			gone = append(gone, ids[i])
+			continue
+		}
+		session, err := decodeSession([]byte(data))
+		if err != nil {
+			return 0, nil, err
+		}
+		if session.Status.Live() {
+			live++
+		}
+	}
+	return live, gone, nil
+}
+
+func (r *RedisSessionRepository) GetSession(ctx context.Context, id string) (*models.VoiceSession, error) {
+	data, err := r.client.Get(ctx, redisSessionKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, domain_errors.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "voiceSessionRepo.GetSession.Get")
+	}
+	return decodeSession(data)
+}
+
+func (r *RedisSessionRepository) UpdateSession(ctx context.Context, session *models.VoiceSession) error {
+	_, err := r.modify(ctx, session.SessionID, func(*models.VoiceSession) (*models.VoiceSession, error) {
+		return session, nil
+	})
+	return err
+}
+
+func (r *RedisSessionRepository) Transition(ctx context.Context, id string, next models.SessionStatus, endReason string) (*models.VoiceSession, error) {
+	return r.modify(ctx, id, func(session *models.VoiceSession) (*models.VoiceSession, error) {
+		if !session.Status.CanBecome(next) {
+			return nil, models.ErrTransition
+		}
+		session.Status = next
+		if endReason != "" {
+			session.EndReason = endReason
+		}
+		return session, nil
+	})
+}
+
+// modify saves what change makes of the stored session, retrying when
+// another instance changed it in between.
+func (r *RedisSessionRepository) modify(ctx context.Context, id string, change func(*models.VoiceSession) (*models.VoiceSession, error)) (*models.VoiceSession, error) {
+	key := redisSessionKey(id)
+	var saved *models.VoiceSession
+	for attempt := 0; attempt < redisTxAttempts; attempt++ {
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			data, err := tx.Get(ctx, key).Bytes()
+			if errors.Is(err, redis.Nil) {
+				return domain_errors.ErrSessionNotFound
+			}
+			if err != nil {
+				return errors.Wrap(err, "Get")
+			}
+			current, err := decodeSession(data)
+			if err != nil {
+				return err
+			}
+			session, err := change(current)
+			if err != nil {
+				return err
+			}
+			data, ttl, err := encodeSession(session)
+			if err != nil {
+				return errors.Wrap(err, "Marshal")
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, data, ttl)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			saved = session
+			return nil
+		}, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil && !errors.Is(err, domain_errors.ErrSessionNotFound) && !errors.Is(err, models.ErrTransition) {
+			return nil, errors.Wrap(err, "voiceSessionRepo.modify")
+		}
+		return saved, err
+	}
+	return nil, errors.Wrap(redis.TxFailedErr, "voiceSessionRepo.modify")
+}
+
+func (r *RedisSessionRepository) DeleteSession(ctx context.Context, id string) error {
+	session, err := r.GetSession(ctx, id)
+	if err != nil && !errors.Is(err, domain_errors.ErrSessionNotFound) {
+		return errors.Wrap(err, "voiceSessionRepo.DeleteSession")
+	}
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, redisSessionKey(id))
+		pipe.SRem(ctx, redisSessionIndex, id)
+		if session != nil {
+			pipe.SRem(ctx, redisUserKey(session.UserID), id)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "voiceSessionRepo.DeleteSession.Exec")
+	}
+	return nil
+}
+
+// DeleteUserSessions deletes the sessions in the user's set; one that
+// already expired is not counted.
+func (r *RedisSessionRepository) DeleteUserSessions(ctx context.Context, userID string) (int, error) {
+	userKey := redisUserKey(userID)
+	ids, err := r.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return 0, errors.Wrap(err, "voiceSessionRepo.DeleteUserSessions.Members")
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, len(ids))
+	members := make([]any, len(ids))
+	for i, id := range ids {
+		keys[i] = redisSessionKey(id)
+		members[i] = id
+	}
+	var deleted *redis.IntCmd
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		deleted = pipe.Del(ctx, keys...)
+		pipe.SRem(ctx, redisSessionIndex, members...)
+		pipe.Del(ctx, userKey)
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "voiceSessionRepo.DeleteUserSessions.Exec")
+	}
+	return int(deleted.Val()), nil
+}
+
+func (r *RedisSessionRepository) ListActiveSessions(ctx context.Context) ([]models.VoiceSession, error) {
+	ids, err := r.client.SMembers(ctx, redisSessionIndex).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "voiceSessionRepo.ListActiveSessions.Members")
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = redisSessionKey(id)
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "voiceSessionRepo.ListActiveSessions.Get")
+	}
+
+	var sessions []models.VoiceSession
+	var expired []any
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			// the key expired, drop it from the index too
+			expired = append(expired, ids[i])
+			continue
+		}
+		session, err := decodeSession([]byte(data))
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(models.ActiveStatuses, session.Status) {
+			sessions = append(sessions, *session)
+		}
+	}
+	if len(expired) > 0 {
+		if err := r.client.SRem(ctx, redisSessionIndex, expired...).Err(); err != nil {
+			return nil, errors.Wrap(err, "voiceSessionRepo.ListActiveSessions.Remove")
+		}
+	}
+	return sessions, nil
+}
+
+// encodeSession returns the stored value of session and how long to keep
+// it: until its grace period after ExpiresAt is over.
+func encodeSession(session *models.VoiceSession) ([]byte, time.Duration, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return nil, 0, err
+	}
+	ttl := time.Until(session.ExpiresAt.Add(redisSessionGrace))
+	if ttl < time.Millisecond {
+		ttl = time.Millisecond
+	}
+	return data, ttl, nil
+}
+
+func decodeSession(data []byte) (*models.VoiceSession, error) {
+	session := new(models.VoiceSession)
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, errors.Wrap(err, "voiceSessionRepo.Unmarshal")
+	}
+	return session, nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"fmt"
+	"swasthAI/config"
+	"swasthAI/internal/voice"
+
+	"github.com/uptrace/bun"
+)
+
+// NewSessionRepository returns the session backend named in
+// cfg.Voice.Store.
+func NewSessionRepository(cfg *config.Config, db *bun.DB) (voice.SessionRepository, error) {
+	switch cfg.Voice.Store {
+	case "", "memory":
+		return NewInMemorySessionRepository(), nil
+	case "postgres":
+		return NewPostgresSessionRepository(db), nil
+	case "redis":
+		return NewRedisSessionRepository(NewRedisClient(cfg.Redis)), nil
+	default:
+		return nil, fmt.Errorf("voice: unknown session store %q", cfg.Voice.Store)
+	}
+}
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/voice"
+	"swasthAI/internal/voice/models"
+	"swasthAI/pkg/domain_errors"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+func testSession(id string) *models.VoiceSession {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	return &models.VoiceSession{
+		SessionID:       id,
+		UserID:          "u1",
+		Language:        "hi",
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(30 * time.Minute),
+		Status:          models.StatusPending,
+		ProtocolVersion: 1,
+		Node:            "node-a",
+	}
+}
+
+// testSessionRepository checks the behaviour every backend shares.
+func testSessionRepository(t *testing.T, repo voice.SessionRepository) {
+	ctx := context.Background()
+	session := testSession("vsn_a")
+	require.NoError(t, repo.CreateSession(ctx, session, 0))
+	// an ID in use is never taken over
+	taken := testSession("vsn_a")
+	taken.UserID = "u2"
+	assert.ErrorIs(t, repo.CreateSession(ctx, taken, 0), models.ErrSessionExists)
+
+	got, err := repo.GetSession(ctx, "vsn_a")
+	require.NoError(t, err)
+	assert.True(t, session.CreatedAt.Equal(got.CreatedAt))
+	got.CreatedAt = session.CreatedAt
+	got.ExpiresAt = session.ExpiresAt
+	assert.Equal(t, session, got)
+
+	// sessions are copies until saved
+	got.Language = "en"
+	again, err := repo.GetSession(ctx, "vsn_a")
+	require.NoError(t, err)
+	assert.Equal(t, "hi", again.Language)
+	require.NoError(t, repo.UpdateSession(ctx, got))
+	again, err = repo.GetSession(ctx, "vsn_a")
+	require.NoError(t, err)
+	assert.Equal(t, "en", again.Language)
+
+	moved, err := repo.Transition(ctx, "vsn_a", models.StatusActive, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusActive, moved.Status)
+	_, err = repo.Transition(ctx, "vsn_a", models.StatusPending, "")
+	assert.ErrorIs(t, err, models.ErrTransition)
+	moved, err = repo.Transition(ctx, "vsn_a", models.StatusEnding, "idle")
+	require.NoError(t, err)
+	assert.Equal(t, "idle", moved.EndReason)
+	// a later end does not overwrite the reason
+	_, err = repo.Transition(ctx, "vsn_a", models.StatusEnding, "closed")
+	assert.ErrorIs(t, err, models.ErrTransition)
+	_, err = repo.Transition(ctx, "missing", models.StatusActive, "")
+	assert.ErrorIs(t, err, domain_errors.ErrSessionNotFound)
+
+	require.NoError(t, repo.CreateSession(ctx, testSession("vsn_b"), 0))
+	// closed sessions are not listed
+	require.NoError(t, repo.CreateSession(ctx, testSession("vsn_e"), 0))
+	_, err = repo.Transition(ctx, "vsn_e", models.StatusEnding, "closed")
+	require.NoError(t, err)
+	_, err = repo.Transition(ctx, "vsn_e", models.StatusClosed, "")
+	require.NoError(t, err)
+	sessions, err := repo.ListActiveSessions(ctx)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, s := range sessions {
+		ids = append(ids, s.SessionID)
+	}
+	assert.ElementsMatch(t, []string{"vsn_a", "vsn_b"}, ids)
+
+	require.NoError(t, repo.DeleteSession(ctx, "vsn_a"))
+	_, err = repo.GetSession(ctx, "vsn_a")
+	assert.ErrorIs(t, err, domain_errors.ErrSessionNotFound)
+	assert.ErrorIs(t, repo.UpdateSession(ctx, session), domain_errors.ErrSessionNotFound)
+	sessions, err = repo.ListActiveSessions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	// only the user's live sessions count towards the limit
+	assert.ErrorIs(t, repo.CreateSession(ctx, testSession("vsn_c"), 1), models.ErrSessionLimit)
+	_, err = repo.GetSession(ctx, "vsn_c")
+	assert.ErrorIs(t, err, domain_errors.ErrSessionNotFound)
+	other := testSession("vsn_d")
+	other.UserID = "u2"
+	require.NoError(t, repo.CreateSession(ctx, other, 1))
+	_, err = repo.Transition(ctx, "vsn_b", models.StatusEnding, "closed")
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateSession(ctx, testSession("vsn_c"), 1))
+
+	// erasing u1 removes vsn_b, vsn_c and the closed vsn_e, not u2's vsn_d
+	deleted, err := repo.DeleteUserSessions(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+	_, err = repo.GetSession(ctx, "vsn_c")
+	assert.ErrorIs(t, err, domain_errors.ErrSessionNotFound)
+	sessions, err = repo.ListActiveSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "vsn_d", sessions[0].SessionID)
+	deleted, err = repo.DeleteUserSessions(ctx, "u1")
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+// testCreateLimitRace checks that concurrent creates for one user cannot
+// get past the limit together.
+func testCreateLimitRace(t *testing.T, repo voice.SessionRepository) {
+	const creates = 8
+	var created atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < creates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.CreateSession(context.Background(), testSession(fmt.Sprintf("vsn_race%d", i)), 1)
+			if err == nil {
+				created.Add(1)
+				return
+			}
+			assert.ErrorIs(t, err, models.ErrSessionLimit)
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, created.Load())
+}
+
+func TestInMemorySessionRepository(t *testing.T) {
+	testSessionRepository(t, NewInMemorySessionRepository())
+	testCreateLimitRace(t, NewInMemorySessionRepository())
+}
+
+var postgresDSN = sync.OnceValues(func() (string, error) {
+	c, err := tcpostgres.Run(context.Background(), "postgres:16-alpine",
+		tcpostgres.WithDatabase("testdb"),
+		tcpostgres.WithUsername("testuser"),
+		tcpostgres.WithPassword("testpass"),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	if err != nil {
+		return "", err
+	}
+	return c.ConnectionString(context.Background(), "sslmode=disable")
+})
+
+// newPostgresRepository returns a repository on an empty voice_sessions
+// table of a Postgres container shared by the package's tests.
+func newPostgresRepository(t *testing.T) *PostgresSessionRepository {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	dsn, err := postgresDSN()
+	require.NoError(t, err)
+
+	db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn))), pgdialect.New())
+	t.Cleanup(func() { db.Close() })
+	ctx := context.Background()
+	_, err = db.NewDropTable().Model((*models.VoiceSession)(nil)).IfExists().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*models.VoiceSession)(nil)).Exec(ctx)
+	require.NoError(t, err)
+	return NewPostgresSessionRepository(db)
+}
+
+func TestPostgresSessionRepository(t *testing.T) {
+	testSessionRepository(t, newPostgresRepository(t))
+	testCreateLimitRace(t, newPostgresRepository(t))
+}
+
+var redisAddr = sync.OnceValues(func() (string, error) {
+	c, err := tcredis.Run(context.Background(), "redis:7-alpine")
+	if err != nil {
+		return "", err
+	}
+	// left for the test binary's exit; testcontainers' reaper removes it
+	return c.ConnectionString(context.Background())
+})
+
+// newRedisRepository returns a repository on an empty database of a Redis
+// container shared by the package's tests.
+func newRedisRepository(t *testing.T) *RedisSessionRepository {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	addr, err := redisAddr()
+	require.NoError(t, err)
+
+	opts, err := redis.ParseURL(addr)
+	require.NoError(t, err)
+	client := NewRedisClient(config.RedisConfig{Addr: opts.Addr, Timeout: 5})
+	t.Cleanup(func() { client.Close() })
+	require.NoError(t, client.FlushDB(context.Background()).Err())
+	return NewRedisSessionRepository(client)
+}
+
+// newMiniredisRepository returns a repository on an in-process Redis, so
+// the shared checks run without Docker. Key expiry is not simulated.
+func newMiniredisRepository(t *testing.T) *RedisSessionRepository {
+	server := miniredis.RunT(t)
+	client := NewRedisClient(config.RedisConfig{Addr: server.Addr(), Timeout: 5})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisSessionRepository(client)
+}
+
+func TestRedisSessionRepository(t *testing.T) {
+	t.Run("miniredis", func(t *testing.T) {
+		testSessionRepository(t, newMiniredisRepository(t))
+		testCreateLimitRace(t, newMiniredisRepository(t))
+	})
+	t.Run("redis", func(t *testing.T) {
+		testSessionRepository(t, newRedisRepository(t))
+		testCreateLimitRace(t, newRedisRepository(t))
+	})
+}
+
+func TestRedisSessionRepository_Expiry(t *testing.T) {
+	repo := newRedisRepository(t)
+	ctx := context.Background()
+	require.NoError(t, repo.CreateSession(ctx, testSession("vsn_a"), 0))
+
+	// kept for a grace period after the session expires
+	ttl, err := repo.client.PTTL(ctx, redisSessionKey("vsn_a")).Result()
+	require.NoError(t, err)
+	assert.InDelta(t, 30*time.Minute+redisSessionGrace, ttl, float64(time.Second))
+
+	// one that is long over is gone at once, and dropped from the index
+	old := testSession("vsn_b")
+	old.ExpiresAt = time.Now().Add(-time.Hour)
+	require.NoError(t, repo.CreateSession(ctx, old, 0))
+	require.Eventually(t, func() bool {
+		n, err := repo.client.Exists(ctx, redisSessionKey("vsn_b")).Result()
+		return err == nil && n == 0
+	}, time.Second, 10*time.Millisecond)
+
+	sessions, err := repo.ListActiveSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "vsn_a", sessions[0].SessionID)
+	ids, err := repo.client.SMembers(ctx, redisSessionIndex).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vsn_a"}, ids)
+}
+
+func TestRedisSessionRepository_TransitionRetriesOnConflict(t *testing.T) {
+	repo := newMiniredisRepository(t)
+	ctx := context.Background()
+	require.NoError(t, repo.CreateSession(ctx, testSession("vsn_a"), 0))
+
+	// two instances race to connect the same session; exactly one wins
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := repo.Transition(ctx, "vsn_a", models.StatusActive, "")
+			errs <- err
+		}()
+	}
+	first, second := <-errs, <-errs
+	if first != nil {
+		first, second = second, first
+	}
+	assert.NoError(t, first)
+	assert.ErrorIs(t, second, models.ErrTransition)
+}
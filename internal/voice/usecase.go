@@ -5,12 +5,16 @@ import (
 
 	"swasthAI/internal/voice/models"
 
-	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
-type VoiceUseCase interface {
-	StartSession(ctx context.Context, req *models.StartSessionRequest, UserID uuid.UUID) error
-	HandleClientWebSocket(conn *websocket.Conn, sessionID uuid.UUID)
-	EndSession(ctx context.Context, sessionID uuid.UUID) error
+type VoiceUsecase interface {
+	StartSession(ctx context.Context, req *models.StartSessionRequest) (*models.StartSessionResponse, error)
+	// AuthorizeSession checks that the session exists and was started by
+	// the caller, before its WebSocket is upgraded.
+	AuthorizeSession(ctx context.Context, sessionID string) error
+	// HandleClientWebSocket serves the session on conn. lastSeq is the last
+	// message a reconnecting client received, 0 on the first connection.
+	HandleClientWebSocket(ctx context.Context, conn *websocket.Conn, sessionID string, lastSeq uint64)
+	EndSession(ctx context.Context, sessionID string) error
 }
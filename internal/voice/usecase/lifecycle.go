@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"swasthAI/internal/voice/models"
+	"swasthAI/internal/voice/protocol"
+	"swasthAI/pkg/domain_errors"
+	appErrors "swasthAI/pkg/errors"
+)
+
+// onLimitTakeover makes a new session end the user's oldest one instead of
+// being rejected.
+const onLimitTakeover = "takeover"
+
+// createLimit is the number of live sessions a user may already hold for
+// a new one to be created. Under takeover the new session is created next
+// to the oldest one, which it only ends once connected, see takeOver.
+func (u *VoiceUsecase) createLimit() int {
+	limit := u.config.Voice.MaxSessionsPerUser
+	if limit > 0 && u.config.Voice.OnLimit == onLimitTakeover {
+		return limit + 1
+	}
+	return limit
+}
+
+// takeOver ends the oldest sessions of the user of a newly connected
+// session until they are back within the per-user limit, if the limit is
+// enforced by takeover.
+func (u *VoiceUsecase) takeOver(ctx context.Context, session *models.VoiceSession) error {
+	limit := u.config.Voice.MaxSessionsPerUser
+	if limit <= 0 || u.config.Voice.OnLimit != onLimitTakeover {
+		return nil
+	}
+
+	sessions, err := u.SessionRepo.ListActiveSessions(ctx)
+	if err != nil {
+		u.logger.Error("failed to list voice sessions (voiceUC.takeOver.SessionRepo.ListActiveSessions)", "error", err)
+		return appErrors.ErrInternal
+	}
+
+	var open []models.VoiceSession
+	for _, s := range sessions {
+		if s.UserID == session.UserID && s.SessionID != session.SessionID && s.Status.Live() {
+			open = append(open, s)
+		}
+	}
+	if len(open) < limit {
+		return nil
+	}
+
+	sort.Slice(open, func(i, j int) bool { return open[i].CreatedAt.Before(open[j].CreatedAt) })
+	for _, s := range open[:len(open)-limit+1] {
+		if err := u.endSession(ctx, s.SessionID, protocol.EndReasonReplaced); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// activate moves a pending session to active and attaches its relay.
+func (u *VoiceUsecase) activate(ctx context.Context, sessionID string, r *sessionRelay) bool {
+	if _, err := u.SessionRepo.Transition(ctx, sessionID, models.StatusActive, ""); err != nil {
+		if !errors.Is(err, models.ErrTransition) && !errors.Is(err, domain_errors.ErrSessionNotFound) {
+			u.logger.Error("failed to activate voice session (voiceUC.activate.SessionRepo.Transition)", "error", err)
+		}
+		return false
+	}
+	// fails if the session was ended in between
+	return u.conns.attach(sessionID, r)
+}
+
+// endSession starts ending a pending or active session. A session served by
+// another instance is left to that instance's reaper.
+func (u *VoiceUsecase) endSession(ctx context.Context, sessionID, reason string) error {
+	session, err := u.SessionRepo.Transition(ctx, sessionID, models.StatusEnding, reason)
+	if errors.Is(err, models.ErrTransition) || errors.Is(err, domain_errors.ErrSessionNotFound) {
+		// already gone or on its way out
+		return nil
+	}
+	if err != nil {
+		u.logger.Error("failed to end voice session (voiceUC.endSession.SessionRepo.Transition)", "error", err)
+		return appErrors.ErrInternal
+	}
+
+	u.logger.Info("ending voice session", "session_id", sessionID, "reason", reason, "node", session.Node)
+	if session.Node == u.conns.node {
+		u.stop(ctx, sessionID, reason)
+	}
+	return nil
+}
+
+// stop closes the connections of an ending session served here. A relayed
+// session tells the client why and is finished by its relay; a pending one
+// is finished here.
+func (u *VoiceUsecase) stop(ctx context.Context, sessionID, reason string) {
+	local, ok := u.conns.get(sessionID)
+	if ok && local.relay != nil {
+		local.relay.end(reason)
+		return
+	}
+	if ok && local.ai != nil {
+		local.ai.Close()
+	}
+	u.finish(ctx, sessionID, reason)
+}
+
+// finish closes a session whose connections are gone and releases it.
+// reason is recorded if the session was still live.
+func (u *VoiceUsecase) finish(ctx context.Context, sessionID, reason string) {
+	u.conns.remove(sessionID)
+	// a live session gets here when the client hung up; either call fails
+	// harmlessly when the status is already past it
+	u.SessionRepo.Transition(ctx, sessionID, models.StatusEnding, reason)
+	u.SessionRepo.Transition(ctx, sessionID, models.StatusClosed, "")
+
+	if err := u.SessionRepo.DeleteSession(ctx, sessionID); err != nil {
+		u.logger.Error("failed to delete voice session (voiceUC.finish.SessionRepo.DeleteSession)", "error", err)
+	}
+}
+
+// Run ends sessions that outlived the maximum duration or went idle, and
+// carries out ends requested through other instances, every reap interval
+// until ctx is cancelled.
+func (u *VoiceUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(u.reapInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			u.reap(ctx, time.Now().UTC())
+		}
+	}
+}
+
+func (u *VoiceUsecase) reapInterval() time.Duration {
+	if interval := time.Duration(u.config.Voice.ReapInterval) * time.Second; interval > 0 {
+		return interval
+	}
+	return 10 * time.Second
+}
+
+func (u *VoiceUsecase) reap(ctx context.Context, now time.Time) {
+	sessions, err := u.SessionRepo.ListActiveSessions(ctx)
+	if err != nil {
+		u.logger.Error("failed to list voice sessions (voiceUC.reap.SessionRepo.ListActiveSessions)", "error", err)
+		return
+	}
+
+	idleTimeout := time.Duration(u.config.Voice.IdleTimeout) * time.Second
+	// the owner of a session ends it by ExpiresAt plus one reap interval, so
+	// one still there well after that belongs to an instance that is gone
+	orphanAfter := 3 * u.reapInterval()
+
+	for _, s := range sessions {
+		if s.Node != u.conns.node {
+			if now.After(s.ExpiresAt.Add(orphanAfter)) {
+				u.finish(ctx, s.SessionID, protocol.EndReasonClosed)
+			}
+			continue
+		}
+
+		local, ok := u.conns.get(s.SessionID)
+		lastActive := s.CreatedAt
+		if ok && local.relay != nil {
+			lastActive = local.relay.lastActive()
+		}
+		switch {
+		case !ok:
+			// left over from before this instance restarted
+			u.finish(ctx, s.SessionID, protocol.EndReasonClosed)
+		case s.Status == models.StatusEnding:
+			// ended through another instance
+			u.stop(ctx, s.SessionID, s.EndReason)
+		case !s.Status.Live():
+		case now.After(s.ExpiresAt):
+			u.endSession(ctx, s.SessionID, protocol.EndReasonExpired)
+		case idleTimeout > 0 && now.Sub(lastActive) > idleTimeout:
+			u.endSession(ctx, s.SessionID, protocol.EndReasonIdle)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/auth"
+	voiceDomain "swasthAI/internal/voice"
+	"swasthAI/internal/voice/models"
+	"swasthAI/internal/voice/protocol"
+	"swasthAI/internal/voice/repository"
+	"swasthAI/pkg/domain_errors"
+	"swasthAI/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLifecycleUsecase(t *testing.T, voice config.Voice) *VoiceUsecase {
+	return newNode(t, "node-a", voice, repository.NewInMemorySessionRepository())
+}
+
+// newNode is one instance of a deployment sharing repo.
+func newNode(t *testing.T, node string, voice config.Voice, repo voiceDomain.SessionRepository) *VoiceUsecase {
+	log, err := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	require.NoError(t, err)
+	voice.QueueSize = 16
+	voice.Overflow = "drop_oldest"
+	voice.PingInterval = 3600
+	voice.PongTimeout = 3600
+	voice.WriteTimeout = 1
+	voice.MaxMessageSize = 1 << 16
+	voice.NodeID = node
+	return NewVoiceUsecase(log, repo, nil, nil, nil, &config.Config{Voice: voice})
+}
+
+// addSession stores a pending session and returns the AI service's end of
+// its connection.
+func addSession(t *testing.T, u *VoiceUsecase, id, userID string, createdAt time.Time) *websocket.Conn {
+	aiConn, ai := pair(t)
+	u.conns.add(id, aiConn)
+	require.NoError(t, u.SessionRepo.CreateSession(context.Background(), &models.VoiceSession{
+		SessionID:       id,
+		UserID:          userID,
+		CreatedAt:       createdAt,
+		ExpiresAt:       createdAt.Add(time.Hour),
+		Status:          models.StatusPending,
+		ProtocolVersion: protocol.Version1,
+		Node:            u.conns.node,
+	}, 0))
+	return ai
+}
+
+// connect relays the session to a new client connection and returns the
+// client's end, and a channel closed when the relay has finished.
+func connect(t *testing.T, u *VoiceUsecase, id string) (*websocket.Conn, chan struct{}) {
+	clientConn, client := pair(t)
+	done := make(chan struct{})
+	go func() {
+		u.HandleClientWebSocket(context.Background(), clientConn, id, 0)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		session, err := u.SessionRepo.GetSession(context.Background(), id)
+		return err == nil && session.Status == models.StatusActive
+	}, time.Second, 5*time.Millisecond)
+	return client, done
+}
+
+func requireEnding(t *testing.T, client *websocket.Conn, reason string) {
+	env := (&relayTest{client: client}).readEvent(t)
+	assert.Equal(t, protocol.TypeSessionEnding, env.Type)
+	var payload protocol.SessionEnding
+	require.NoError(t, env.DecodePayload(&payload))
+	assert.Equal(t, reason, payload.Reason)
+
+	_, _, err := client.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), err)
+}
+
+func TestSessionStatus_CanBecome(t *testing.T) {
+	assert.True(t, models.StatusPending.CanBecome(models.StatusActive))
+	assert.True(t, models.StatusPending.CanBecome(models.StatusEnding))
+	assert.True(t, models.StatusActive.CanBecome(models.StatusEnding))
+	assert.True(t, models.StatusEnding.CanBecome(models.StatusClosed))
+	assert.False(t, models.StatusActive.CanBecome(models.StatusPending))
+	assert.False(t, models.StatusClosed.CanBecome(models.StatusActive))
+	assert.False(t, models.StatusEnding.CanBecome(models.StatusActive))
+}
+
+func TestReap_ExpiredPendingSession(t *testing.T) {
+	u := newLifecycleUsecase(t, config.Voice{IdleTimeout: 3600})
+	ai := addSession(t, u, "vsn_a", "u1", time.Now().UTC())
+
+	u.reap(context.Background(), time.Now().UTC().Add(2*time.Hour))
+
+	_, err := u.SessionRepo.GetSession(context.Background(), "vsn_a")
+	assert.ErrorIs(t, err, domain_errors.ErrSessionNotFound)
+	_, _, err = ai.ReadMessage()
+	assert.Error(t, err)
+}
+
+func TestReap_IdleActiveSession(t *testing.T) {
+	u := newLifecycleUsecase(t, config.Voice{IdleTimeout: 60})
+	ai := addSession(t, u, "vsn_a", "u1", time.Now().UTC())
+	client, done := connect(t, u, "vsn_a")
+
+	// still within the idle timeout
+	u.reap(context.Background(), time.Now().UTC().Add(30*time.Second))
+	require.NoError(t, client.WriteMessage(websocket.BinaryMessage, []byte{1}))
+	_, _, err := ai.ReadMessage()
+	require.NoError(t, err)
+
+	u.reap(context.Background(), time.Now().UTC().Add(2*time.Minute))
+	requireEnding(t, client, protocol.EndReasonIdle)
+	<-done
+
+	_, err = u.SessionRepo.GetSession(context.Background(), "vsn_a")
+	assert.ErrorIs(t, err, domain_errors.ErrSessionNotFound)
+}
+
+func TestEndSession_TellsClient(t *testing.T) {
+	u := newLifecycleUsecase(t, config.Voice{})
+	userID := uuid.New()
+	addSession(t, u, "vsn_a", userID.String(), time.Now().UTC())
+	client, done := connect(t, u, "vsn_a")
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{UserID: userID})
+
+	// a second connection is refused while the first one is open
+	assert.ErrorIs(t, u.AuthorizeSession(ctx, "vsn_a"), domain_errors.ErrVoiceSessionInUse)
+
+	require.NoError(t, u.EndSession(ctx, "vsn_a"))
+	requireEnding(t, client, protocol.EndReasonClosed)
+	<-done
+	assert.ErrorIs(t, u.AuthorizeSession(ctx, "vsn_a"), domain_errors.ErrSessionNotFound)
+}
+
+func TestHandleClientWebSocket_Resume(t *testing.T) {
+	u := newLifecycleUsecase(t, config.Voice{ResumeWindow: 30, ReplayBuffer: 15})
+	userID := uuid.New()
+	ai := addSession(t, u, "vsn_a", userID.String(), time.Now().UTC())
+	client, done := connect(t, u, "vsn_a")
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{UserID: userID})
+
+	client.UnderlyingConn().Close()
+	require.NoError(t, u.AuthorizeSession(ctx, "vsn_a"))
+	clientConn, client := pair(t)
+	resumed := make(chan struct{})
+	go func() {
+		u.HandleClientWebSocket(context.Background(), clientConn, "vsn_a", 0)
+		close(resumed)
+	}()
+
+	env := (&relayTest{client: client}).readEvent(t)
+	assert.Equal(t, protocol.TypeSessionResumed, env.Type)
+	require.NoError(t, client.WriteMessage(websocket.BinaryMessage, []byte{1}))
+	_, _, err := ai.ReadMessage()
+	require.NoError(t, err)
+
+	require.NoError(t, u.EndSession(ctx, "vsn_a"))
+	requireEnding(t, client, protocol.EndReasonClosed)
+	<-resumed
+	<-done
+	assert.ErrorIs(t, u.AuthorizeSession(ctx, "vsn_a"), domain_errors.ErrSessionNotFound)
+}
+
+func TestTakeOver_EndsOldest(t *testing.T) {
+	u := newLifecycleUsecase(t, config.Voice{MaxSessionsPerUser: 2, OnLimit: "takeover"})
+	now := time.Now().UTC()
+	addSession(t, u, "vsn_old", "u1", now.Add(-time.Minute))
+	addSession(t, u, "vsn_mid", "u1", now.Add(-time.Second))
+	addSession(t, u, "vsn_new", "u1", now)
+	client, done := connect(t, u, "vsn_old")
+
+	session, err := u.SessionRepo.GetSession(context.Background(), "vsn_new")
+	require.NoError(t, err)
+	require.NoError(t, u.takeOver(context.Background(), session))
+	requireEnding(t, client, protocol.EndReasonReplaced)
+	<-done
+
+	_, err = u.SessionRepo.GetSession(context.Background(), "vsn_old")
+	assert.ErrorIs(t, err, domain_errors.ErrSessionNotFound)
+	for _, id := range []string{"vsn_mid", "vsn_new"} {
+		_, err = u.SessionRepo.GetSession(context.Background(), id)
+		assert.NoError(t, err, id)
+	}
+}
+
+func TestEndSession_ThroughOtherNode(t *testing.T) {
+	repo := repository.NewInMemorySessionRepository()
+	a := newNode(t, "node-a", config.Voice{}, repo)
+	b := newNode(t, "node-b", config.Voice{}, repo)
+	userID := uuid.New()
+	addSession(t, a, "vsn_a", userID.String(), time.Now().UTC())
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{UserID: userID})
+
+	// the socket has to reach the instance holding the AI connection
+	assert.ErrorIs(t, b.AuthorizeSession(ctx, "vsn_a"), domain_errors.ErrVoiceSessionMisdirected)
+	require.NoError(t, a.AuthorizeSession(ctx, "vsn_a"))
+	client, done := connect(t, a, "vsn_a")
+
+	// node b only marks the session; node a closes it on its next reap
+	require.NoError(t, b.EndSession(ctx, "vsn_a"))
+	session, err := repo.GetSession(context.Background(), "vsn_a")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusEnding, session.Status)
+
+	a.reap(context.Background(), time.Now().UTC())
+	requireEnding(t, client, protocol.EndReasonClosed)
+	<-done
+	_, err = repo.GetSession(context.Background(), "vsn_a")
+	assert.ErrorIs(t, err, domain_errors.ErrSessionNotFound)
+}
+
+func TestReap_OrphanedSession(t *testing.T) {
+	repo := repository.NewInMemorySessionRepository()
+	a := newNode(t, "node-a", config.Voice{ReapInterval: 10}, repo)
+	now := time.Now().UTC()
+	addSession(t, a, "vsn_a", "u1", now)
+
+	// node a crashed: b cleans up after it only well past expiry
+	b := newNode(t, "node-b", config.Voice{ReapInterval: 10}, repo)
+	b.reap(context.Background(), now.Add(time.Hour+10*time.Second))
+	_, err := repo.GetSession(context.Background(), "vsn_a")
+	require.NoError(t, err)
+	b.reap(context.Background(), now.Add(time.Hour+time.Minute))
+	_, err = repo.GetSession(context.Background(), "vsn_a")
+	assert.ErrorIs(t, err, domain_errors.ErrSessionNotFound)
+}
+
+func TestReap_SessionLostInRestart(t *testing.T) {
+	repo := repository.NewInMemorySessionRepository()
+	now := time.Now().UTC()
+	addSession(t, newNode(t, "node-a", config.Voice{}, repo), "vsn_a", "u1", now)
+
+	// node a restarted under the same name, without the connections
+	restarted := newNode(t, "node-a", config.Voice{}, repo)
+	restarted.reap(context.Background(), now)
+	_, err := repo.GetSession(context.Background(), "vsn_a")
+	assert.ErrorIs(t, err, domain_errors.ErrSessionNotFound)
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// connRegistry holds the connections of the sessions this instance serves.
+// Session metadata is shared through the repository, sockets cannot be, so
+// a session stays with the instance that started it, recorded as its Node.
+type connRegistry struct {
+	node string
+
+	mu       sync.Mutex
+	sessions map[string]localSession
+}
+
+type localSession struct {
+	ai    *websocket.Conn // nil while the session is being started
+	relay *sessionRelay   // nil until the client connects
+}
+
+func newConnRegistry(node string) *connRegistry {
+	return &connRegistry{node: node, sessions: make(map[string]localSession)}
+}
+
+// add registers a new session. It fails if the ID is already in use.
+func (c *connRegistry) add(sessionID string, ai *websocket.Conn) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.sessions[sessionID]; ok {
+		return false
+	}
+	c.sessions[sessionID] = localSession{ai: ai}
+	return true
+}
+
+func (c *connRegistry) get(sessionID string) (localSession, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.sessions[sessionID]
+	return s, ok
+}
+
+// connect records the AI connection of a session being started. It fails
+// if the session was removed meanwhile.
+func (c *connRegistry) connect(sessionID string, ai *websocket.Conn) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.sessions[sessionID]
+	if !ok {
+		return false
+	}
+	s.ai = ai
+	c.sessions[sessionID] = s
+	return true
+}
+
+// attach records the relay of a connected session. It fails if the session
+// was removed meanwhile.
+func (c *connRegistry) attach(sessionID string, r *sessionRelay) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.sessions[sessionID]
+	if !ok {
+		return false
+	}
+	s.relay = r
+	c.sessions[sessionID] = s
+	return true
+}
+
+func (c *connRegistry) remove(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sessions, sessionID)
+}
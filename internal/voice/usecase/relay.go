@@ -0,0 +1,334 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"swasthAI/internal/voice/models"
+	"swasthAI/internal/voice/protocol"
+	"swasthAI/internal/voice/relay"
+	"swasthAI/pkg/logger"
+
+	"github.com/gorilla/websocket"
+)
+
+// aiEvents maps the text messages of the AI service to the events relayed
+// to the client.
+var aiEvents = map[protocol.MessageType]func(msg *models.AIMessage) any{
+	protocol.TypePartialTranscript: func(msg *models.AIMessage) any { return protocol.Transcript{Text: msg.Text} },
+	protocol.TypeFinalTranscript:   func(msg *models.AIMessage) any { return protocol.Transcript{Text: msg.Text} },
+	protocol.TypeAIText:            func(msg *models.AIMessage) any { return protocol.AIText{Text: msg.Text} },
+	protocol.TypeEndOfResponse:     func(msg *models.AIMessage) any { return protocol.EndOfResponse{} },
+}
+
+// sessionRelay translates between the client protocol and the AI service.
+// Only its run loop touches the peers' queues, the sequencer's turns and
+// the replay buffer; the peers' pumps do all reading and writing.
+//
+// A resumable relay outlives its client connection: when the client drops,
+// the AI side is kept and server messages are buffered until the client
+// reconnects or the resume window is over.
+type sessionRelay struct {
+	client  *relay.Peer // nil while the client is away
+	ai      *relay.Peer
+	seq     *protocol.Sequencer
+	version int
+	cfg     relay.Config
+	logger  *logger.Logger
+
+	resumeWindow time.Duration
+	replay       *replayBuffer // nil unless resumable
+
+	attaching chan attachment
+	ending    chan string
+	done      chan struct{}
+	// why the session ended; read once run has returned
+	endReason string
+
+	lastActivity atomic.Int64 // unix nanoseconds of the last frame either way
+}
+
+// attachment is a reconnected client and the last message it received.
+type attachment struct {
+	client  *relay.Peer
+	lastSeq uint64
+}
+
+func newSessionRelay(clientConn, aiConn *websocket.Conn, version int, cfg relay.Config, logger *logger.Logger) *sessionRelay {
+	r := &sessionRelay{
+		client:    relay.NewPeer(clientConn, cfg),
+		ai:        relay.NewPeer(aiConn, cfg),
+		seq:       protocol.NewSequencer(version),
+		version:   version,
+		cfg:       cfg,
+		logger:    logger,
+		attaching: make(chan attachment),
+		ending:    make(chan string, 1),
+		done:      make(chan struct{}),
+		endReason: protocol.EndReasonClosed,
+	}
+	r.touch()
+	return r
+}
+
+// resumable keeps the session for window after the client drops, buffering
+// up to frames server messages for it.
+func (r *sessionRelay) resumable(window time.Duration, frames int) {
+	r.resumeWindow = window
+	r.replay = newReplayBuffer(frames)
+}
+
+// attach hands a reconnected client to the run loop, which replays what
+// came after lastSeq. It returns the client's peer, or false once the
+// session is over.
+func (r *sessionRelay) attach(conn *websocket.Conn, lastSeq uint64) (*relay.Peer, bool) {
+	client := relay.NewPeer(conn, r.cfg)
+	select {
+	case r.attaching <- attachment{client: client, lastSeq: lastSeq}:
+		return client, true
+	case <-r.done:
+		return nil, false
+	}
+}
+
+// end asks the run loop to tell the client why the session ends and to
+// close both sides. Calls after the first are ignored.
+func (r *sessionRelay) end(reason string) {
+	select {
+	case r.ending <- reason:
+	default:
+	}
+}
+
+// lastActive is when a frame last went through, in either direction.
+func (r *sessionRelay) lastActive() time.Time {
+	return time.Unix(0, r.lastActivity.Load()).UTC()
+}
+
+func (r *sessionRelay) touch() {
+	r.lastActivity.Store(time.Now().UnixNano())
+}
+
+// run relays until the session ends or ctx is cancelled, and returns once
+// every connection is closed.
+func (r *sessionRelay) run(ctx context.Context) {
+	clients := []*relay.Peer{r.client}
+	r.client.Start(ctx)
+	r.ai.Start(ctx)
+	defer func() {
+		close(r.done)
+		for _, c := range clients {
+			<-c.Done()
+		}
+		<-r.ai.Done()
+	}()
+
+	var away *time.Timer
+	defer func() {
+		if away != nil {
+			away.Stop()
+		}
+	}()
+	var awayC <-chan time.Time
+
+	aiIn := r.ai.Incoming()
+	for {
+		var clientIn <-chan relay.Frame
+		if r.client != nil {
+			clientIn = r.client.Incoming()
+		}
+
+		select {
+		case f, ok := <-clientIn:
+			if !ok {
+				err := r.client.Err()
+				if errors.Is(err, relay.ErrClosed) || r.replay == nil {
+					if !errors.Is(err, relay.ErrClosed) {
+						r.logger.Warn("voice client connection lost (voiceUC.sessionRelay.run)", "error", err)
+					}
+					r.ai.Close(nil)
+					return
+				}
+				// a dropped connection rather than a goodbye: wait for the
+				// client to come back
+				r.logger.Info("voice client connection lost, waiting for it to resume", "error", err)
+				r.client = nil
+				away = time.NewTimer(r.resumeWindow)
+				awayC = away.C
+				continue
+			}
+			r.touch()
+			r.fromClient(f)
+
+		case a := <-r.attaching:
+			if r.client != nil {
+				// the old connection may not have noticed it is dead yet
+				r.client.Close(nil)
+			}
+			if away != nil {
+				away.Stop()
+				away, awayC = nil, nil
+			}
+			clients = append(clients, a.client)
+			r.client = a.client
+			r.client.Start(ctx)
+			r.touch()
+			r.resume(a.lastSeq)
+
+		case <-awayC:
+			r.logger.Info("voice client did not resume in time (voiceUC.sessionRelay.run)")
+			r.endReason = protocol.EndReasonDisconnected
+			r.ai.Close(nil)
+			return
+
+		case f, ok := <-aiIn:
+			if !ok {
+				r.logger.Warn("AI service connection lost (voiceUC.sessionRelay.run)", "error", r.ai.Err())
+				r.endReason = protocol.EndReasonAIUnavailable
+				r.event(protocol.TypeError, protocol.Error{Code: protocol.ErrCodeAIUnavailable, Message: "The AI service disconnected", Fatal: true})
+				r.event(protocol.TypeSessionEnding, protocol.SessionEnding{Reason: protocol.EndReasonAIUnavailable})
+				if r.client != nil {
+					r.client.Close(nil)
+				}
+				return
+			}
+			r.touch()
+			r.fromAI(f)
+
+		case reason := <-r.ending:
+			r.endReason = reason
+			r.event(protocol.TypeSessionEnding, protocol.SessionEnding{Reason: reason})
+			if r.client != nil {
+				r.client.Close(nil)
+			}
+			r.ai.Close(nil)
+			return
+
+		case <-ctx.Done():
+			// the peers close themselves
+			return
+		}
+	}
+}
+
+// resume replays to a reconnected client what it missed after lastSeq.
+// The new connection's queue starts empty; the replay is cut to what fits
+// in it next to session_resumed, so that the queue never overflows and
+// drops or disconnects. Anything cut makes the replay incomplete.
+func (r *sessionRelay) resume(lastSeq uint64) {
+	frames, complete := r.replay.since(lastSeq)
+	r.replay.ack(lastSeq)
+	if room := max(r.cfg.QueueSize-1, 0); len(frames) > room {
+		frames = frames[len(frames)-room:]
+		complete = false
+	}
+
+	dropped := r.client.Dropped()
+	for _, f := range frames {
+		if err := r.client.Send(f); err != nil {
+			complete = false
+			break
+		}
+	}
+	if r.client.Dropped() != dropped {
+		complete = false
+	}
+	r.event(protocol.TypeSessionResumed, protocol.SessionResumed{Replayed: len(frames), Complete: complete})
+}
+
+func (r *sessionRelay) fromClient(f relay.Frame) {
+	if f.Type == websocket.BinaryMessage {
+		r.seq.Input()
+		r.ai.Send(f)
+		return
+	}
+
+	env, err := protocol.DecodeClient(f.Data, r.version)
+	if err != nil {
+		r.rejected(err)
+		return
+	}
+	switch env.Type {
+	case protocol.TypeEndOfInput:
+		r.seq.EndInput()
+		r.toAI(&models.AIMessage{Type: string(protocol.TypeEndOfInput)})
+	case protocol.TypeTextMessage:
+		var input protocol.TextMessage
+		if err := env.DecodePayload(&input); err != nil || input.Content == "" {
+			r.rejected(protocol.ErrBadMessage)
+			return
+		}
+		r.seq.EndInput()
+		r.toAI(&models.AIMessage{Type: string(protocol.TypeTextMessage), Content: input.Content})
+	case protocol.TypeAck:
+		var ack protocol.Ack
+		if err := env.DecodePayload(&ack); err != nil {
+			r.rejected(err)
+			return
+		}
+		if r.replay != nil {
+			r.replay.ack(ack.Seq)
+		}
+	}
+}
+
+func (r *sessionRelay) fromAI(f relay.Frame) {
+	if f.Type == websocket.BinaryMessage {
+		r.toClient(relay.Frame{Type: websocket.BinaryMessage, Data: r.seq.Audio(f.Data)})
+		return
+	}
+
+	var msg models.AIMessage
+	if err := json.Unmarshal(f.Data, &msg); err != nil {
+		r.logger.Warn("malformed message from AI service (voiceUC.sessionRelay.fromAI)", "error", err)
+		return
+	}
+	payload, ok := aiEvents[protocol.MessageType(msg.Type)]
+	if !ok {
+		r.logger.Warn("unknown message type from AI service (voiceUC.sessionRelay.fromAI)", "type", msg.Type)
+		return
+	}
+	r.event(protocol.MessageType(msg.Type), payload(&msg))
+}
+
+func (r *sessionRelay) toAI(msg *models.AIMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	r.ai.Send(relay.Frame{Type: websocket.TextMessage, Data: data})
+}
+
+func (r *sessionRelay) event(t protocol.MessageType, payload any) {
+	frame, err := r.seq.Event(t, payload)
+	if err != nil {
+		r.logger.Error("failed to encode voice event (voiceUC.sessionRelay.event)", "type", t, "error", err)
+		return
+	}
+	r.toClient(relay.Frame{Type: websocket.TextMessage, Data: frame})
+}
+
+// toClient sends a sequenced frame, keeping it for a resume.
+func (r *sessionRelay) toClient(f relay.Frame) {
+	if r.replay != nil {
+		r.replay.add(r.seq.Last(), f)
+	}
+	if r.client != nil {
+		r.client.Send(f)
+	}
+}
+
+// rejected answers a client message the protocol does not accept.
+func (r *sessionRelay) rejected(err error) {
+	code := protocol.ErrCodeBadMessage
+	switch {
+	case errors.Is(err, protocol.ErrUnknownType):
+		code = protocol.ErrCodeUnknownType
+	case errors.Is(err, protocol.ErrVersion):
+		code = protocol.ErrCodeVersion
+	}
+	r.event(protocol.TypeError, protocol.Error{Code: code, Message: err.Error()})
+}
@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+	"swasthAI/internal/voice/protocol"
+	"swasthAI/internal/voice/relay"
+	"swasthAI/pkg/logger"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pair returns the server and far ends of a WebSocket connection.
+func pair(t *testing.T) (server, far *websocket.Conn) {
+	conns := make(chan *websocket.Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		require.NoError(t, err)
+		conns <- conn
+	}))
+	t.Cleanup(srv.Close)
+
+	far, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { far.Close() })
+	return <-conns, far
+}
+
+type relayTest struct {
+	client *websocket.Conn // the app's end
+	ai     *websocket.Conn // the AI service's end
+	relay  *sessionRelay
+	done   chan struct{}
+}
+
+const testQueueSize = 16
+
+func startRelay(t *testing.T) *relayTest {
+	return startResumableRelay(t, 0)
+}
+
+// startResumableRelay starts a relay that waits window for a dropped
+// client; 0 ends it at once.
+func startResumableRelay(t *testing.T, window time.Duration) *relayTest {
+	return startRelayWith(t, relay.DropOldestAudio, window, testQueueSize-1)
+}
+
+// startRelayWith starts a relay whose queues overflow by policy, keeping
+// frames messages for a client that drops within window.
+func startRelayWith(t *testing.T, overflow relay.OverflowPolicy, window time.Duration, frames int) *relayTest {
+	clientConn, client := pair(t)
+	aiConn, ai := pair(t)
+	log, err := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	require.NoError(t, err)
+
+	cfg := relay.Config{QueueSize: testQueueSize, Overflow: overflow, PingInterval: time.Hour, PongTimeout: time.Hour, WriteTimeout: time.Second, MaxMessageSize: 1 << 16}
+	r := newSessionRelay(clientConn, aiConn, protocol.Version1, cfg, log)
+	if window > 0 {
+		r.resumable(window, frames)
+	}
+	rt := &relayTest{client: client, ai: ai, relay: r, done: make(chan struct{})}
+	go func() {
+		r.run(context.Background())
+		close(rt.done)
+	}()
+	return rt
+}
+
+func (rt *relayTest) readEvent(t *testing.T) protocol.Envelope {
+	msgType, data, err := rt.client.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, websocket.TextMessage, msgType)
+	var env protocol.Envelope
+	require.NoError(t, json.Unmarshal(data, &env))
+	return env
+}
+
+func TestSessionRelay_ClientToAI(t *testing.T) {
+	rt := startRelay(t)
+
+	require.NoError(t, rt.client.WriteMessage(websocket.BinaryMessage, []byte{1, 2}))
+	require.NoError(t, rt.client.WriteMessage(websocket.TextMessage, []byte(`{"v":1,"type":"end_of_input","seq":2}`)))
+	require.NoError(t, rt.client.WriteMessage(websocket.TextMessage, []byte(`{"v":1,"type":"text_message","seq":3,"payload":{"content":"Show my blood report"}}`)))
+
+	msgType, data, err := rt.ai.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, msgType)
+	assert.Equal(t, []byte{1, 2}, data)
+	_, data, err = rt.ai.ReadMessage()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"end_of_input"}`, string(data))
+	_, data, err = rt.ai.ReadMessage()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"text_message","content":"Show my blood report"}`, string(data))
+}
+
+func TestSessionRelay_AIToClient(t *testing.T) {
+	rt := startRelay(t)
+
+	// the user speaks, so the answer belongs to turn 1
+	require.NoError(t, rt.client.WriteMessage(websocket.BinaryMessage, []byte{1}))
+	_, _, err := rt.ai.ReadMessage()
+	require.NoError(t, err)
+
+	require.NoError(t, rt.ai.WriteMessage(websocket.TextMessage, []byte(`{"type":"final_transcript","text":"show me my blood report"}`)))
+	require.NoError(t, rt.ai.WriteMessage(websocket.TextMessage, []byte(`{"type":"model_debug","text":"ignored"}`)))
+	require.NoError(t, rt.ai.WriteMessage(websocket.BinaryMessage, []byte{0xAA}))
+	require.NoError(t, rt.ai.WriteMessage(websocket.TextMessage, []byte(`{"type":"end_of_response"}`)))
+
+	env := rt.readEvent(t)
+	assert.Equal(t, protocol.TypeFinalTranscript, env.Type)
+	assert.Equal(t, uint64(1), env.Seq)
+	assert.Equal(t, uint32(1), env.Turn)
+	assert.JSONEq(t, `{"text":"show me my blood report"}`, string(env.Payload))
+
+	msgType, frame, err := rt.client.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, msgType)
+	h, audio, err := protocol.DecodeAudio(frame)
+	require.NoError(t, err)
+	assert.Equal(t, protocol.AudioHeader{Version: protocol.Version1, Seq: 2, Turn: 1}, h)
+	assert.Equal(t, []byte{0xAA}, audio)
+
+	env = rt.readEvent(t)
+	assert.Equal(t, protocol.TypeEndOfResponse, env.Type)
+	assert.Equal(t, uint64(3), env.Seq)
+}
+
+func TestSessionRelay_RejectsUnknownClientMessage(t *testing.T) {
+	rt := startRelay(t)
+
+	require.NoError(t, rt.client.WriteMessage(websocket.TextMessage, []byte(`{"v":1,"type":"dance"}`)))
+
+	env := rt.readEvent(t)
+	assert.Equal(t, protocol.TypeError, env.Type)
+	var payload protocol.Error
+	require.NoError(t, env.DecodePayload(&payload))
+	assert.Equal(t, protocol.ErrCodeUnknownType, payload.Code)
+	assert.False(t, payload.Fatal)
+}
+
+func TestSessionRelay_AIDisconnect(t *testing.T) {
+	rt := startRelay(t)
+
+	rt.ai.Close()
+
+	env := rt.readEvent(t)
+	assert.Equal(t, protocol.TypeError, env.Type)
+	env = rt.readEvent(t)
+	assert.Equal(t, protocol.TypeSessionEnding, env.Type)
+	assert.JSONEq(t, `{"reason":"ai_unavailable"}`, string(env.Payload))
+	_, _, err := rt.client.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), err)
+	<-rt.done
+}
+
+func TestSessionRelay_ClientDisconnect(t *testing.T) {
+	rt := startRelay(t)
+
+	rt.client.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+
+	_, _, err := rt.ai.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), err)
+	<-rt.done
+}
+
+func TestSessionRelay_ResumeReplaysMissedMessages(t *testing.T) {
+	rt := startResumableRelay(t, time.Minute)
+
+	require.NoError(t, rt.ai.WriteMessage(websocket.TextMessage, []byte(`{"type":"partial_transcript","text":"show"}`)))
+	require.NoError(t, rt.ai.WriteMessage(websocket.TextMessage, []byte(`{"type":"final_transcript","text":"show me my blood report"}`)))
+	require.NoError(t, rt.ai.WriteMessage(websocket.TextMessage, []byte(`{"type":"end_of_response"}`)))
+	for seq := uint64(1); seq <= 3; seq++ {
+		require.Equal(t, seq, rt.readEvent(t).Seq)
+	}
+
+	// the network goes away without a close frame, and the client only
+	// got as far as the first message
+	rt.client.UnderlyingConn().Close()
+	rt.reconnect(t, 1)
+
+	env := rt.readEvent(t)
+	assert.Equal(t, protocol.TypeFinalTranscript, env.Type)
+	assert.Equal(t, uint64(2), env.Seq)
+	env = rt.readEvent(t)
+	assert.Equal(t, protocol.TypeEndOfResponse, env.Type)
+	env = rt.readEvent(t)
+	assert.Equal(t, protocol.TypeSessionResumed, env.Type)
+	assert.JSONEq(t, `{"replayed":2,"complete":true}`, string(env.Payload))
+
+	// the new connection is relayed as the old one was
+	require.NoError(t, rt.client.WriteMessage(websocket.BinaryMessage, []byte{1}))
+	_, data, err := rt.ai.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, data)
+}
+
+func TestSessionRelay_ResumeWindowExpires(t *testing.T) {
+	rt := startResumableRelay(t, 50*time.Millisecond)
+
+	rt.client.UnderlyingConn().Close()
+
+	_, _, err := rt.ai.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), err)
+	<-rt.done
+	assert.Equal(t, protocol.EndReasonDisconnected, rt.relay.endReason)
+
+	_, ok := rt.relay.attach(nil, 0)
+	assert.False(t, ok)
+}
+
+func TestSessionRelay_ClientDisconnectIsNotResumed(t *testing.T) {
+	rt := startResumableRelay(t, time.Minute)
+
+	// hanging up on purpose ends the session without waiting
+	rt.client.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+
+	_, _, err := rt.ai.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), err)
+	<-rt.done
+	assert.Equal(t, protocol.EndReasonClosed, rt.relay.endReason)
+}
+
+// reconnect attaches a new client connection that last saw lastSeq.
+func (rt *relayTest) reconnect(t *testing.T, lastSeq uint64) {
+	clientConn, client := pair(t)
+	_, ok := rt.relay.attach(clientConn, lastSeq)
+	require.True(t, ok)
+	rt.client = client
+}
+
+// sendAudio has the AI service send n audio frames, which the client
+// receives before its connection drops.
+func (rt *relayTest) sendAudio(t *testing.T, n int) {
+	for i := 0; i < n; i++ {
+		require.NoError(t, rt.ai.WriteMessage(websocket.BinaryMessage, []byte{byte(i)}))
+		_, _, err := rt.client.ReadMessage()
+		require.NoError(t, err)
+	}
+	rt.client.UnderlyingConn().Close()
+}
+
+// readReplay reads n replayed audio frames and the session_resumed after
+// them, and returns the first frame's seq.
+func (rt *relayTest) readReplay(t *testing.T, n int) (uint64, protocol.SessionResumed) {
+	var first uint64
+	for i := 0; i < n; i++ {
+		msgType, frame, err := rt.client.ReadMessage()
+		require.NoError(t, err)
+		require.Equal(t, websocket.BinaryMessage, msgType, "frame %d", i)
+		h, _, err := protocol.DecodeAudio(frame)
+		require.NoError(t, err)
+		if i == 0 {
+			first = h.Seq
+		}
+	}
+	env := rt.readEvent(t)
+	require.Equal(t, protocol.TypeSessionResumed, env.Type)
+	var resumed protocol.SessionResumed
+	require.NoError(t, env.DecodePayload(&resumed))
+	return first, resumed
+}
+
+func TestSessionRelay_ResumeReplaysFullBuffer(t *testing.T) {
+	for _, overflow := range []relay.OverflowPolicy{relay.DropOldestAudio, relay.Disconnect} {
+		t.Run(string(overflow), func(t *testing.T) {
+			rt := startRelayWith(t, overflow, time.Minute, testQueueSize-1)
+
+			rt.sendAudio(t, testQueueSize-1)
+			rt.reconnect(t, 0)
+
+			first, resumed := rt.readReplay(t, testQueueSize-1)
+			assert.Equal(t, uint64(1), first)
+			assert.Equal(t, protocol.SessionResumed{Replayed: testQueueSize - 1, Complete: true}, resumed)
+
+			// the new connection is still open
+			require.NoError(t, rt.ai.WriteMessage(websocket.BinaryMessage, []byte{0xAA}))
+			_, _, err := rt.client.ReadMessage()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSessionRelay_ResumeCutToQueue(t *testing.T) {
+	// a buffer larger than the queue is refused by ValidateConfig; should
+	// one get through, only the newest frames are replayed
+	rt := startRelayWith(t, relay.Disconnect, time.Minute, 2*testQueueSize)
+
+	rt.sendAudio(t, 20)
+	rt.reconnect(t, 0)
+
+	first, resumed := rt.readReplay(t, testQueueSize-1)
+	assert.Equal(t, uint64(20-testQueueSize+2), first)
+	assert.Equal(t, protocol.SessionResumed{Replayed: testQueueSize - 1, Complete: false}, resumed)
+}
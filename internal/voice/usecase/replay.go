@@ -0,0 +1,50 @@
+package usecase
+
+import "swasthAI/internal/voice/relay"
+
+type sequencedFrame struct {
+	seq   uint64
+	frame relay.Frame
+}
+
+// replayBuffer keeps the latest frames sent to the client, so that a client
+// that reconnects can be sent what it missed. Only the relay's run loop
+// uses it.
+type replayBuffer struct {
+	size    int
+	frames  []sequencedFrame // oldest first
+	evicted uint64           // highest seq pushed out before it was acked
+}
+
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{size: size}
+}
+
+func (b *replayBuffer) add(seq uint64, f relay.Frame) {
+	b.frames = append(b.frames, sequencedFrame{seq: seq, frame: f})
+	if len(b.frames) > b.size {
+		b.evicted = b.frames[0].seq
+		b.frames = b.frames[1:]
+	}
+}
+
+// ack drops the frames the client confirmed up to seq.
+func (b *replayBuffer) ack(seq uint64) {
+	i := 0
+	for i < len(b.frames) && b.frames[i].seq <= seq {
+		i++
+	}
+	b.frames = b.frames[i:]
+}
+
+// since returns the frames after seq, and whether none of them had to be
+// evicted.
+func (b *replayBuffer) since(seq uint64) ([]relay.Frame, bool) {
+	var frames []relay.Frame
+	for _, f := range b.frames {
+		if f.seq > seq {
+			frames = append(frames, f.frame)
+		}
+	}
+	return frames, seq >= b.evicted
+}
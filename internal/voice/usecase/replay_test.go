@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"testing"
+
+	"swasthAI/internal/voice/relay"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func frame(b byte) relay.Frame {
+	return relay.Frame{Data: []byte{b}}
+}
+
+func TestReplayBuffer(t *testing.T) {
+	b := newReplayBuffer(3)
+	for seq := uint64(1); seq <= 3; seq++ {
+		b.add(seq, frame(byte(seq)))
+	}
+
+	frames, complete := b.since(1)
+	assert.Equal(t, []relay.Frame{frame(2), frame(3)}, frames)
+	assert.True(t, complete)
+
+	// acked frames are not kept, so they leave room for new ones
+	b.ack(2)
+	b.add(4, frame(4))
+	b.add(5, frame(5))
+	frames, complete = b.since(2)
+	assert.Equal(t, []relay.Frame{frame(3), frame(4), frame(5)}, frames)
+	assert.True(t, complete)
+
+	// 3 is pushed out before the client confirmed it
+	b.add(6, frame(6))
+	frames, complete = b.since(2)
+	assert.Equal(t, []relay.Frame{frame(4), frame(5), frame(6)}, frames)
+	assert.False(t, complete)
+}
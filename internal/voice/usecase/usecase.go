@@ -2,150 +2,289 @@ package usecase
 
 import (
 	"context"
-	"encoding/json"
-	"net/http"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"swasthAI/config"
+	"swasthAI/internal/auth"
+	"swasthAI/internal/consent"
+	consentModels "swasthAI/internal/consent/models"
+	"swasthAI/internal/health"
+	"swasthAI/internal/patient"
+	patientModels "swasthAI/internal/patient/models"
+	"swasthAI/internal/voice"
 	"swasthAI/internal/voice/models"
-	"swasthAI/internal/voice/repository"
+	"swasthAI/internal/voice/protocol"
+	"swasthAI/internal/voice/relay"
 	"swasthAI/pkg/domain_errors"
 	appErrors "swasthAI/pkg/errors"
 	"swasthAI/pkg/logger"
-	"swasthAI/pkg/utils"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
 type VoiceUsecase struct {
-	SessionRepo *repository.InMemorySessionRepository
-	aiWSURL     string
+	SessionRepo voice.SessionRepository
+	consentUC   consent.ConsentUsecase
+	healthUC    health.HealthUsecase
 	logger      *logger.Logger
 	upgrader    websocket.Upgrader
-	httpClient  *http.Client
+	aiClient    voice.AIClient
 	config      *config.Config
+	conns       *connRegistry
 }
 
-func NewVoiceUsecase(logger *logger.Logger, SessionRepo *repository.InMemorySessionRepository, aiWSURL string, httpClient *http.Client) *VoiceUsecase {
-	return &VoiceUsecase{SessionRepo: SessionRepo, logger: logger, aiWSURL: aiWSURL, httpClient: httpClient}
+func NewVoiceUsecase(logger *logger.Logger, SessionRepo voice.SessionRepository, consentUC consent.ConsentUsecase, healthUC health.HealthUsecase, aiClient voice.AIClient, cfg *config.Config) *VoiceUsecase {
+	return &VoiceUsecase{SessionRepo: SessionRepo, consentUC: consentUC, healthUC: healthUC, logger: logger, aiClient: aiClient, config: cfg, conns: newConnRegistry(nodeID(cfg.Voice))}
 }
 
-func (u *VoiceUsecase) StartSession(ctx context.Context, req *models.StartSessionRequest, UserID uuid.UUID) (*models.StartSessionResponse, error) {
-	claims, ok := ctx.Value("claims").(*utils.JWTClaims)
+// nodeID names this instance in the shared session store.
+func nodeID(cfg config.Voice) string {
+	if cfg.NodeID != "" {
+		return cfg.NodeID
+	}
+	if host, err := os.Hostname(); err == nil {
+		return host
+	}
+	return uuid.NewString()
+}
+
+// ValidateConfig rejects voice settings that sessions cannot run with, so
+// that they fail at startup rather than on the first connection.
+func ValidateConfig(cfg config.Voice) error {
+	if err := relay.NewConfig(cfg).Validate(); err != nil {
+		return err
+	}
+	// a replay has to fit in the new connection's queue, with room for
+	// session_resumed
+	if cfg.ResumeWindow > 0 && cfg.ReplayBuffer >= cfg.QueueSize {
+		return fmt.Errorf("voice.replaybuffer (%d) must be smaller than voice.queuesize (%d)", cfg.ReplayBuffer, cfg.QueueSize)
+	}
+	return nil
+}
+
+func (u *VoiceUsecase) StartSession(ctx context.Context, req *models.StartSessionRequest) (*models.StartSessionResponse, error) {
+	version, ok := protocol.Negotiate(req.ProtocolVersions)
+	if !ok {
+		return nil, domain_errors.ErrUnsupportedProtocol
+	}
+
+	identity, ok := auth.FromContext(ctx)
 	if !ok {
 		return nil, appErrors.ErrUnauthorized
 	}
-	userID := claims.ID
+	userID := identity.UserID
 
-	sessionUUID := uuid.New()
-	shortID := "vsn_" + sessionUUID.String()[:6]
-	aiConn, _, err := websocket.DefaultDialer.Dial(u.aiWSURL+"?session_id="+shortID, nil)
+	// sessions started on behalf of a patient are tagged with them
+	var patientID string
+	subjectID := userID
+	if actingFor, ok := patient.ActingForFromContext(ctx); ok {
+		if !actingFor.Allows(patientModels.DelegateVoiceUse) {
+			return nil, domain_errors.ErrNotDelegated
+		}
+		patientID = actingFor.PatientID.String()
+		subjectID = actingFor.PatientID
+	}
+	// audio goes to the AI service, which needs the speaker's consent
+	if err := u.consentUC.Require(ctx, subjectID, consentModels.PurposeVoiceAI); err != nil {
+		return nil, err
+	}
+	// nil when the speaker has not filled in a health profile
+	healthContext, err := u.healthUC.AIContext(ctx, subjectID)
 	if err != nil {
-		return nil, domain_errors.ErrAIConnectionFailed
+		return nil, err
 	}
 
+	now := time.Now().UTC()
 	session := &models.VoiceSession{
-		SessionID: shortID,
 		UserID:    userID.String(),
+		PatientID: patientID,
 		Language:  req.Language,
 		Model:     req.Model,
-		CreatedAt: time.Now().UTC(),
-		ExpiresAt: time.Now().UTC().Add(10 * 60),
-		AiWSConn:  aiConn,
-		Status:    "active",
-	}
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Duration(u.config.Voice.MaxDuration) * time.Second),
+		Status:    models.StatusPending,
 
-	err = u.SessionRepo.CreateSession(ctx, session)
+		ProtocolVersion: version,
+		Node:            u.conns.node,
+	}
+	err = u.reserve(ctx, session)
+	if errors.Is(err, models.ErrSessionLimit) {
+		return nil, domain_errors.ErrVoiceSessionLimit
+	}
 	if err != nil {
+		u.logger.Error("failed to create voice session (voiceUC.StartSession.reserve)", "error", err)
 		return nil, appErrors.ErrInternal
 	}
+	shortID := session.SessionID
+
+	aiConn, err := u.aiClient.Connect(ctx, &models.AISessionRequest{SessionID: shortID, Language: req.Language, Model: req.Model})
+	if err != nil {
+		u.logger.Warn("failed to connect to AI service (voiceUC.StartSession.aiClient.Connect)", "error", err)
+		u.finish(ctx, shortID, protocol.EndReasonAIUnavailable)
+		return nil, domain_errors.ErrAIConnectionFailed
+	}
+	if healthContext != nil {
+		if err := aiConn.WriteJSON(map[string]any{"type": "session_context", "health_profile": healthContext}); err != nil {
+			aiConn.Close()
+			u.finish(ctx, shortID, protocol.EndReasonAIUnavailable)
+			return nil, domain_errors.ErrAIConnectionFailed
+		}
+	}
+	if !u.conns.connect(shortID, aiConn) {
+		// ended while connecting, by a takeover or the reaper
+		aiConn.Close()
+		return nil, appErrors.ErrConflict
+	}
+	if err := u.takeOver(ctx, session); err != nil {
+		u.endSession(ctx, shortID, protocol.EndReasonClosed)
+		return nil, err
+	}
 
 	return &models.StartSessionResponse{
 		SessionID: shortID,
-		WSURL:     u.aiWSURL + "/api/v1/voice/session/" + shortID + "/ws",
+		WSURL:     strings.TrimSuffix(u.config.Voice.PublicWSURL, "/") + "/voice/session/" + shortID + "/ws",
+
+		ProtocolVersion: version,
 	}, nil
 }
 
-func (u *VoiceUsecase) HandleClientWebSocket(ctx context.Context, clientConn *websocket.Conn, sessionID string) {
-	session, err := u.SessionRepo.GetSession(ctx, sessionID)
-	if err != nil {
-		return
-	}
-	toAI := make(chan []byte, 100)
-	fromAI := make(chan []byte, 100)
-	done := make(chan struct{})
+// sessionIDAttempts is how many IDs a new session is tried under.
+const sessionIDAttempts = 3
 
-	go u.relayFromAI(session.AiWSConn, clientConn, fromAI, done)
+// newSessionID returns a random session ID.
+var newSessionID = func() string {
+	return "vsn_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+}
 
-	for {
-		msgType, data, err := clientConn.ReadMessage()
-		if err != nil {
-			break
+// reserve saves a new session under a fresh ID, drawing another while the
+// one drawn is taken, here or in the store.
+func (u *VoiceUsecase) reserve(ctx context.Context, session *models.VoiceSession) error {
+	for attempt := 0; attempt < sessionIDAttempts; attempt++ {
+		session.SessionID = newSessionID()
+		// registered first, so the reaper never sees this node's session
+		// without its registry entry; the AI connection is added once up
+		if !u.conns.add(session.SessionID, nil) {
+			continue
+		}
+		err := u.SessionRepo.CreateSession(ctx, session, u.createLimit())
+		if err == nil {
+			return nil
 		}
+		u.conns.remove(session.SessionID)
+		if !errors.Is(err, models.ErrSessionExists) {
+			return err
+		}
+	}
+	return models.ErrSessionExists
+}
 
-		switch msgType {
-		case websocket.BinaryMessage:
-			select {
-			case toAI <- data:
-			case <-ctx.Done():
-				return
-			}
-
-		case websocket.TextMessage:
-			var msg *models.WSMessage
-			if json.Unmarshal(data, &msg) != nil {
-				continue
-			}
-
-			switch msg.Type {
-			case "end_of_input":
-				session.AiWSConn.WriteJSON(map[string]any{"type": "end_of_input"})
-			case "text_message":
-				var input *models.TextMessageInput
-				if json.Unmarshal(data, &input) == nil {
-					session.AiWSConn.WriteJSON(map[string]any{
-						"type": "text_message", "content": input.Content,
-					})
-				}
-			}
+// AuthorizeSession reports a session started by someone else as not found,
+// so that session IDs cannot be probed. Only a pending session can be
+// connected to, on the instance that started it, or with resume enabled an
+// active one whose client reconnects.
+func (u *VoiceUsecase) AuthorizeSession(ctx context.Context, sessionID string) error {
+	session, err := u.ownSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
 
+	switch session.Status {
+	case models.StatusPending:
+		if session.Node != u.conns.node {
+			return domain_errors.ErrVoiceSessionMisdirected
+		}
+		return nil
+	case models.StatusActive:
+		if u.config.Voice.ResumeWindow <= 0 {
+			return domain_errors.ErrVoiceSessionInUse
+		}
+		// a client coming back after a drop replaces its old connection
+		if session.Node != u.conns.node {
+			return domain_errors.ErrVoiceSessionMisdirected
 		}
+		return nil
+	}
+	return domain_errors.ErrSessionNotFound
+}
 
+func (u *VoiceUsecase) EndSession(ctx context.Context, sessionID string) error {
+	if _, err := u.ownSession(ctx, sessionID); err != nil {
+		return err
 	}
-	close(done)
-	clientConn.Close()
-	session.AiWSConn.Close()
-	u.SessionRepo.DeleteSession(ctx, sessionID)
+	return u.endSession(ctx, sessionID, protocol.EndReasonClosed)
 }
 
-func (u *VoiceUsecase) relayFromAI(aiConn, clientConn *websocket.Conn, fromAI chan []byte, done chan struct{}) {
-	defer close(fromAI)
-	for {
-		msgType, data, err := aiConn.ReadMessage()
-		if err != nil {
-			break
-		}
+// ownSession returns the session if the caller started it.
+func (u *VoiceUsecase) ownSession(ctx context.Context, sessionID string) (*models.VoiceSession, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, appErrors.ErrUnauthorized
+	}
+	session, err := u.SessionRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, domain_errors.ErrSessionNotFound
+	}
+	if session.UserID != identity.UserID.String() {
+		return nil, domain_errors.ErrSessionNotFound
+	}
+	return session, nil
+}
 
-		switch msgType {
-		case websocket.BinaryMessage:
-			// ai_audio
-			clientConn.WriteMessage(websocket.BinaryMessage, data)
-		case websocket.TextMessage:
-			var msg models.WSMessage
-			if json.Unmarshal(data, &msg) == nil {
-				switch msg.Type {
-				case "partial_transcript", "final_transcript", "ai_text":
-					clientConn.WriteJSON(map[string]any{"type": msg.Type, "text": "..."})
-				case "end_of_response":
-					clientConn.WriteJSON(map[string]any{"type": "end_of_response"})
-				}
-			}
-		}
+// HandleClientWebSocket relays between the client and the AI service until
+// either side hangs up, then ends the session. A client reconnecting to a
+// session it dropped is handed to the running relay, which replays what
+// came after lastSeq.
+func (u *VoiceUsecase) HandleClientWebSocket(ctx context.Context, clientConn *websocket.Conn, sessionID string, lastSeq uint64) {
+	session, err := u.SessionRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		clientConn.Close()
+		return
+	}
+	local, ok := u.conns.get(sessionID)
+	if !ok {
+		clientConn.Close()
+		return
+	}
+	if local.relay != nil {
+		u.resume(local.relay, clientConn, lastSeq)
+		return
+	}
+
+	r := newSessionRelay(clientConn, local.ai, session.ProtocolVersion, relay.NewConfig(u.config.Voice), u.logger)
+	if window := time.Duration(u.config.Voice.ResumeWindow) * time.Second; window > 0 {
+		r.resumable(window, u.config.Voice.ReplayBuffer)
 	}
-	select {
-	case <-done:
-	default:
-		close(done)
+	// a second connection, or one racing the reaper, loses here
+	if !u.activate(ctx, sessionID, r) {
+		clientConn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "session is not pending"), time.Now().Add(time.Second))
+		clientConn.Close()
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	r.run(ctx)
+	u.finish(context.WithoutCancel(ctx), sessionID, r.endReason)
+}
+
+// resume attaches a reconnected client to its session's relay and waits
+// until that connection is closed.
+func (u *VoiceUsecase) resume(r *sessionRelay, clientConn *websocket.Conn, lastSeq uint64) {
+	if r.replay == nil {
+		clientConn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "session is already connected"), time.Now().Add(time.Second))
+		clientConn.Close()
+		return
+	}
+	client, ok := r.attach(clientConn, lastSeq)
+	if !ok {
+		clientConn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "session has ended"), time.Now().Add(time.Second))
+		clientConn.Close()
+		return
 	}
+	<-client.Done()
 }
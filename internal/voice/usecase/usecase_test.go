@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"swasthAI/config"
+	"swasthAI/internal/auth"
+	consentMocks "swasthAI/internal/consent/mocks"
+	consentModels "swasthAI/internal/consent/models"
+	healthMocks "swasthAI/internal/health/mocks"
+	mocks "swasthAI/internal/voice/mocks"
+	"swasthAI/internal/voice/models"
+	"swasthAI/internal/voice/repository"
+	"swasthAI/pkg/domain_errors"
+	"swasthAI/pkg/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStartTest(t *testing.T, voice config.Voice) (*VoiceUsecase, *mocks.MockAIClient, context.Context, uuid.UUID) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	userID := uuid.New()
+
+	consentUC := consentMocks.NewMockConsentUsecase(ctrl)
+	consentUC.EXPECT().Require(gomock.Any(), userID, consentModels.PurposeVoiceAI).Return(nil).AnyTimes()
+	healthUC := healthMocks.NewMockHealthUsecase(ctrl)
+	healthUC.EXPECT().AIContext(gomock.Any(), userID).Return(nil, nil).AnyTimes()
+	aiClient := mocks.NewMockAIClient(ctrl)
+
+	log, err := logger.NewLogger(&config.Config{LoggerMode: config.LoggerMode{Development: true}})
+	require.NoError(t, err)
+	voice.PublicWSURL = "wss://api.example.com/api/v1/"
+	voice.MaxDuration = 1800
+	voice.NodeID = "node-a"
+	cfg := &config.Config{Voice: voice}
+	u := NewVoiceUsecase(log, repository.NewInMemorySessionRepository(), consentUC, healthUC, aiClient, cfg)
+	return u, aiClient, auth.WithIdentity(context.Background(), &auth.Identity{UserID: userID}), userID
+}
+
+func TestStartSession(t *testing.T) {
+	u, aiClient, ctx, userID := setupStartTest(t, config.Voice{})
+	aiConn, ai := pair(t)
+	aiClient.EXPECT().Connect(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req *models.AISessionRequest) (*websocket.Conn, error) {
+		assert.Equal(t, "hi", req.Language)
+		assert.Equal(t, "mistral-7b", req.Model)
+		assert.NotEmpty(t, req.SessionID)
+		return aiConn, nil
+	})
+
+	res, err := u.StartSession(ctx, &models.StartSessionRequest{Language: "hi", Model: "mistral-7b"})
+	require.NoError(t, err)
+	assert.Equal(t, "wss://api.example.com/api/v1/voice/session/"+res.SessionID+"/ws", res.WSURL)
+
+	session, err := u.SessionRepo.GetSession(context.Background(), res.SessionID)
+	require.NoError(t, err)
+	assert.Equal(t, userID.String(), session.UserID)
+	assert.Equal(t, models.StatusPending, session.Status)
+	assert.Equal(t, "node-a", session.Node)
+	local, ok := u.conns.get(res.SessionID)
+	require.True(t, ok)
+	assert.Same(t, aiConn, local.ai)
+
+	// ending it before a client connects closes the AI side
+	require.NoError(t, u.EndSession(ctx, res.SessionID))
+	_, _, err = ai.ReadMessage()
+	assert.Error(t, err)
+	_, ok = u.conns.get(res.SessionID)
+	assert.False(t, ok)
+}
+
+func TestStartSession_AIUnavailable(t *testing.T) {
+	u, aiClient, ctx, _ := setupStartTest(t, config.Voice{})
+	aiClient.EXPECT().Connect(gomock.Any(), gomock.Any()).Return(nil, errors.New("no AI service endpoint available"))
+
+	_, err := u.StartSession(ctx, &models.StartSessionRequest{Language: "hi"})
+	assert.ErrorIs(t, err, domain_errors.ErrAIConnectionFailed)
+
+	sessions, err := u.SessionRepo.ListActiveSessions(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestStartSession_RetriesTakenID(t *testing.T) {
+	ids := []string{"vsn_a", "vsn_b", "vsn_b", "vsn_c"}
+	defer func(orig func() string) { newSessionID = orig }(newSessionID)
+	newSessionID = func() string {
+		id := ids[0]
+		ids = ids[1:]
+		return id
+	}
+	u, aiClient, ctx, _ := setupStartTest(t, config.Voice{})
+	aiClient.EXPECT().Connect(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, *models.AISessionRequest) (*websocket.Conn, error) {
+		aiConn, _ := pair(t)
+		return aiConn, nil
+	}).Times(2)
+	// started by another instance
+	other := &models.VoiceSession{SessionID: "vsn_a", UserID: "u2", Status: models.StatusPending, Node: "node-b"}
+	require.NoError(t, u.SessionRepo.CreateSession(context.Background(), other, 0))
+
+	res, err := u.StartSession(ctx, &models.StartSessionRequest{Language: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "vsn_b", res.SessionID)
+	res, err = u.StartSession(ctx, &models.StartSessionRequest{Language: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "vsn_c", res.SessionID)
+
+	session, err := u.SessionRepo.GetSession(context.Background(), "vsn_a")
+	require.NoError(t, err)
+	assert.Equal(t, "u2", session.UserID)
+}
+
+func TestStartSession_LimitIsAtomic(t *testing.T) {
+	u, aiClient, ctx, _ := setupStartTest(t, config.Voice{MaxSessionsPerUser: 1, OnLimit: "reject"})
+	aiClient.EXPECT().Connect(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, *models.AISessionRequest) (*websocket.Conn, error) {
+		aiConn, _ := pair(t)
+		return aiConn, nil
+	}).Times(1)
+
+	const starts = 8
+	errs := make(chan error, starts)
+	var wg sync.WaitGroup
+	for i := 0; i < starts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := u.StartSession(ctx, &models.StartSessionRequest{Language: "hi"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	started := 0
+	for err := range errs {
+		if err == nil {
+			started++
+			continue
+		}
+		assert.ErrorIs(t, err, domain_errors.ErrVoiceSessionLimit)
+	}
+	assert.Equal(t, 1, started)
+}
+
+func TestStartSession_TakeoverAfterAIConnects(t *testing.T) {
+	u, aiClient, ctx, _ := setupStartTest(t, config.Voice{MaxSessionsPerUser: 1, OnLimit: "takeover"})
+	oldConn, oldAI := pair(t)
+	newConn, _ := pair(t)
+	gomock.InOrder(
+		aiClient.EXPECT().Connect(gomock.Any(), gomock.Any()).Return(oldConn, nil),
+		aiClient.EXPECT().Connect(gomock.Any(), gomock.Any()).Return(nil, errors.New("no AI service endpoint available")),
+		aiClient.EXPECT().Connect(gomock.Any(), gomock.Any()).Return(newConn, nil),
+	)
+	old, err := u.StartSession(ctx, &models.StartSessionRequest{Language: "hi"})
+	require.NoError(t, err)
+
+	// a replacement that fails to connect leaves the old session alone
+	_, err = u.StartSession(ctx, &models.StartSessionRequest{Language: "hi"})
+	assert.ErrorIs(t, err, domain_errors.ErrAIConnectionFailed)
+	session, err := u.SessionRepo.GetSession(context.Background(), old.SessionID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, session.Status)
+
+	res, err := u.StartSession(ctx, &models.StartSessionRequest{Language: "hi"})
+	require.NoError(t, err)
+	_, _, err = oldAI.ReadMessage()
+	assert.Error(t, err)
+	_, err = u.SessionRepo.GetSession(context.Background(), old.SessionID)
+	assert.ErrorIs(t, err, domain_errors.ErrSessionNotFound)
+	_, err = u.SessionRepo.GetSession(context.Background(), res.SessionID)
+	assert.NoError(t, err)
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := config.Voice{QueueSize: 256, Overflow: "drop_oldest", PingInterval: 20, PongTimeout: 45, WriteTimeout: 10, MaxMessageSize: 1 << 20, ResumeWindow: 30, ReplayBuffer: 255}
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.ReplayBuffer = 512
+	assert.Error(t, ValidateConfig(cfg))
+	// the buffer is unused without resuming
+	cfg.ResumeWindow = 0
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.PingInterval = 0
+	assert.Error(t, ValidateConfig(cfg))
+}
@@ -0,0 +1,24 @@
+-- otps created before codes were hashed keep the plaintext code in "otp".
+-- Those codes are short-lived, so they are dropped rather than hashed: the
+-- empty code_hash of an old row matches no code.
+ALTER TABLE IF EXISTS otps ADD COLUMN IF NOT EXISTS code_hash VARCHAR NOT NULL DEFAULT '';
+
+--bun:split
+
+ALTER TABLE IF EXISTS otps ALTER COLUMN code_hash DROP DEFAULT;
+
+--bun:split
+
+ALTER TABLE IF EXISTS otps ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+--bun:split
+
+ALTER TABLE IF EXISTS otps ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp;
+
+--bun:split
+
+ALTER TABLE IF EXISTS otps ADD COLUMN IF NOT EXISTS channel VARCHAR;
+
+--bun:split
+
+ALTER TABLE IF EXISTS otps DROP COLUMN IF EXISTS otp;
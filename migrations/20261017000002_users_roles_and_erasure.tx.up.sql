@@ -0,0 +1,26 @@
+-- users created before roles, transliterated names and account deletion
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS roles VARCHAR[] NOT NULL DEFAULT '{patient}';
+
+--bun:split
+
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS permissions VARCHAR[] NOT NULL DEFAULT '{}';
+
+--bun:split
+
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS first_name_latin VARCHAR;
+
+--bun:split
+
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS last_name_latin VARCHAR;
+
+--bun:split
+
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS full_name_latin VARCHAR;
+
+--bun:split
+
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMPTZ;
+
+--bun:split
+
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS purge_at TIMESTAMPTZ;
@@ -0,0 +1,12 @@
+-- OTP messages queued before bodies were masked hold the code in plaintext.
+-- Their bodies are cleared and the unsent ones are not resent.
+DO $$
+BEGIN
+    IF to_regclass('sms_outbox') IS NOT NULL THEN
+        UPDATE sms_outbox
+        SET body = '',
+            status = CASE WHEN status IN ('pending', 'sending') THEN 'expired' ELSE status END
+        WHERE kind = 'otp' AND body <> '' AND body NOT LIKE '%******%';
+    END IF;
+END
+$$;
@@ -0,0 +1,7 @@
+-- codes verified before registration required one have no verified_at, so
+-- they cannot be used to register
+ALTER TABLE IF EXISTS otps ADD COLUMN IF NOT EXISTS verified_at TIMESTAMPTZ;
+
+--bun:split
+
+ALTER TABLE IF EXISTS otps ADD COLUMN IF NOT EXISTS consumed_at TIMESTAMPTZ;
@@ -0,0 +1,53 @@
+// Package migrations upgrades tables that already exist in a database. New
+// tables are still created from the models on startup; a migration is only
+// needed when a table created by an earlier version changes shape, or when
+// stored rows have to be rewritten once.
+package migrations
+
+import (
+	"context"
+	"embed"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+//go:embed *.sql
+var sqlMigrations embed.FS
+
+// Migrations holds the SQL files of this package, applied in the order of
+// their numeric prefix.
+var Migrations = migrate.NewMigrations()
+
+func init() {
+	if err := Migrations.Discover(sqlMigrations); err != nil {
+		panic(err)
+	}
+}
+
+// Run applies the migrations not yet recorded in the bun_migrations table.
+// Instances starting together wait on a session advisory lock, so each
+// migration runs once; the lock goes away with the connection if the
+// process dies, unlike bun's lock table.
+func Run(ctx context.Context, db *bun.DB) (*migrate.MigrationGroup, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "migrations.Run.Conn")
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtextextended(?, 0))", "migrations"); err != nil {
+		return nil, errors.Wrap(err, "migrations.Run.Lock")
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtextextended(?, 0))", "migrations")
+
+	migrator := migrate.NewMigrator(db, Migrations, migrate.WithMarkAppliedOnSuccess(true))
+	if err := migrator.Init(ctx); err != nil {
+		return nil, errors.Wrap(err, "migrations.Run.Init")
+	}
+	group, err := migrator.Migrate(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "migrations.Run.Migrate")
+	}
+	return group, nil
+}
@@ -1,21 +1,23 @@
 package domain_errors
 
 import (
+	stdErrors "errors"
 	"net/http"
-	"strings"
 	"swasthAI/pkg/errors"
+	"swasthAI/pkg/phone"
 )
 
 // User Domain Errors
 var (
-	ErrInvalidPhoneFormat = errors.New("USER_INVALID_PHONE", "Invalid phone number format. Use +91xxxxxxxxxx", http.StatusBadRequest, nil)
+	ErrInvalidPhoneFormat = errors.New("USER_INVALID_PHONE", "Invalid mobile number", http.StatusBadRequest, nil)
+	ErrPhoneRegionBlocked = errors.New("USER_PHONE_REGION_NOT_ALLOWED", "Mobile numbers from this country are not supported", http.StatusUnprocessableEntity, nil)
 	ErrUserAlreadyExists  = errors.New("USER_AlREADY_EXISTS", "Phone number already registered", http.StatusConflict, nil)
 	ErrUserNotFound       = errors.New("USER_NOT_FOUND", "User not found", http.StatusNotFound, nil)
 	ErrInvalidLanguage    = errors.New("USER_INVALID_LANGUAGE", "Unsupported language. Use: hi,en,ta,te,bn,mr", http.StatusUnprocessableEntity, nil)
 
 	// Validation errors for User struct
-	ErrInvalidFirstName = errors.New("USER_INVALID_FIRST_NAME", "First name must be 2-50 alphabetic characters", http.StatusBadRequest, nil)
-	ErrInvalidLastName  = errors.New("USER_INVALID_LAST_NAME", "Last name must be 2-50 alphabetic characters", http.StatusBadRequest, nil)
+	ErrInvalidFirstName = errors.New("USER_INVALID_FIRST_NAME", "First name must be 2-50 letters", http.StatusBadRequest, nil)
+	ErrInvalidLastName  = errors.New("USER_INVALID_LAST_NAME", "Last name must be 2-50 letters", http.StatusBadRequest, nil)
 )
 
 // Auth Domain Errors
@@ -23,8 +25,76 @@ var (
 	ErrInvalidOTP          = errors.New("AUTH_INVALID_OTP", "Invalid or expired OTP", http.StatusBadRequest, nil)
 	ErrOTPAttemptsExceeded = errors.New("AUTH_OTP_ATTEMPTS_EXCEEDED", "Too many failed OTP attempts", http.StatusTooManyRequests, nil)
 	ErrOTPExpired          = errors.New("AUTH_OTP_EXPIRED", "OTP expired. Please request new OTP", http.StatusBadRequest, nil)
-	ErrResendCooldown      = errors.New("AUTH_RESEND_COOLDOWN", "Please wait 60 seconds before requesting new OTP", http.StatusTooManyRequests, nil)
+	ErrResendCooldown      = errors.New("AUTH_RESEND_COOLDOWN", "Please wait before requesting a new OTP", http.StatusTooManyRequests, nil)
 	ErrFailedToSendOTP     = errors.New("AUTH_OTP_FAILED", "Failed to send OTP", http.StatusInternalServerError, nil)
+	ErrOTPNotFound         = errors.New("AUTH_OTP_NOT_FOUND", "No OTP was requested for this phone", http.StatusBadRequest, nil)
+	ErrPhoneNotVerified    = errors.New("AUTH_PHONE_NOT_VERIFIED", "Phone not verified with OTP", http.StatusBadRequest, nil)
+
+	ErrInvalidOTPChannel     = errors.New("AUTH_OTP_INVALID_CHANNEL", "Unknown OTP channel. Use: sms,voice,whatsapp", http.StatusUnprocessableEntity, nil)
+	ErrOTPChannelUnavailable = errors.New("AUTH_OTP_CHANNEL_UNAVAILABLE", "This OTP channel is not available", http.StatusUnprocessableEntity, nil)
+
+	ErrRefreshTokenNotFound = errors.New("AUTH_REFRESH_TOKEN_NOT_FOUND", "Refresh token not recognised", http.StatusUnauthorized, nil)
+	ErrRefreshTokenRevoked  = errors.New("AUTH_REFRESH_TOKEN_REVOKED", "Session has been logged out. Please login again", http.StatusUnauthorized, nil)
+	ErrRefreshTokenReused   = errors.New("AUTH_REFRESH_TOKEN_REUSED", "Refresh token already used. All sessions for this login were revoked", http.StatusUnauthorized, nil)
+	ErrLoginSessionNotFound = errors.New("AUTH_SESSION_NOT_FOUND", "Login session not found", http.StatusNotFound, nil)
+
+	ErrInvalidRole       = errors.New("AUTH_INVALID_ROLE", "Unknown role. Use: patient,asha_worker,doctor,admin", http.StatusUnprocessableEntity, nil)
+	ErrInvalidPermission = errors.New("AUTH_INVALID_PERMISSION", "Unknown permission", http.StatusUnprocessableEntity, nil)
+	ErrSelfRoleChange    = errors.New("AUTH_SELF_ROLE_CHANGE", "Admins cannot change their own roles", http.StatusForbidden, nil)
+
+	ErrAccountPendingDeletion = errors.New("AUTH_ACCOUNT_PENDING_DELETION", "Account is scheduled for deletion. Restore it to log in", http.StatusConflict, nil)
+	ErrAccountNotDeleted      = errors.New("AUTH_ACCOUNT_NOT_DELETED", "Account is not scheduled for deletion", http.StatusConflict, nil)
+	ErrRestoreWindowExpired   = errors.New("AUTH_RESTORE_WINDOW_EXPIRED", "The account can no longer be restored", http.StatusGone, nil)
+)
+
+// Patient Domain Errors
+var (
+	ErrPatientNotFound            = errors.New("PATIENT_NOT_FOUND", "Patient profile not found", http.StatusNotFound, nil)
+	ErrActingForRequired          = errors.New("PATIENT_ACTING_FOR_REQUIRED", "X-Acting-For header with a patient id is required", http.StatusBadRequest, nil)
+	ErrNotDelegated               = errors.New("PATIENT_NOT_DELEGATED", "You are not allowed to act for this patient", http.StatusForbidden, nil)
+	ErrDelegationNotFound         = errors.New("PATIENT_DELEGATION_NOT_FOUND", "Delegation not found", http.StatusNotFound, nil)
+	ErrDelegationExists           = errors.New("PATIENT_DELEGATION_EXISTS", "This user already manages the patient", http.StatusConflict, nil)
+	ErrLastManager                = errors.New("PATIENT_LAST_MANAGER", "A patient must keep at least one account that can manage delegations", http.StatusConflict, nil)
+	ErrInvalidRelation            = errors.New("PATIENT_INVALID_RELATION", "Unknown relation. Use: family,caregiver,asha_worker,doctor", http.StatusUnprocessableEntity, nil)
+	ErrInvalidDelegatedPermission = errors.New("PATIENT_INVALID_PERMISSION", "Unknown delegated permission", http.StatusUnprocessableEntity, nil)
+)
+
+// Consent Domain Errors
+var (
+	ErrConsentRequired        = errors.New("CONSENT_REQUIRED", "Consent is required for this action", http.StatusForbidden, nil)
+	ErrInvalidConsentPurpose  = errors.New("CONSENT_INVALID_PURPOSE", "Unknown consent purpose. Use: ai_voice,ai_images,doctor_sharing", http.StatusUnprocessableEntity, nil)
+	ErrInvalidConsentVersion  = errors.New("CONSENT_INVALID_VERSION", "Unknown consent text version", http.StatusUnprocessableEntity, nil)
+	ErrConsentVersionOutdated = errors.New("CONSENT_VERSION_OUTDATED", "A newer consent text was published. Show it and ask again", http.StatusConflict, nil)
+)
+
+// Health Profile Domain Errors
+var (
+	ErrHealthProfileNotFound  = errors.New("HEALTH_PROFILE_NOT_FOUND", "No health profile recorded yet", http.StatusNotFound, nil)
+	ErrInvalidDateOfBirth     = errors.New("HEALTH_INVALID_DATE_OF_BIRTH", "Date of birth must be a past date within the last 130 years", http.StatusUnprocessableEntity, nil)
+	ErrInvalidSex             = errors.New("HEALTH_INVALID_SEX", "Unknown sex. Use: female,male,other", http.StatusUnprocessableEntity, nil)
+	ErrInvalidBloodGroup      = errors.New("HEALTH_INVALID_BLOOD_GROUP", "Unknown blood group. Use: A+,A-,B+,B-,AB+,AB-,O+,O-", http.StatusUnprocessableEntity, nil)
+	ErrInvalidPregnancyStatus = errors.New("HEALTH_INVALID_PREGNANCY_STATUS", "Unknown pregnancy status. Use: not_pregnant,pregnant,unknown", http.StatusUnprocessableEntity, nil)
+	ErrPregnancyNotApplicable = errors.New("HEALTH_PREGNANCY_NOT_APPLICABLE", "Pregnancy status cannot be pregnant for sex male", http.StatusUnprocessableEntity, nil)
+)
+
+// Abuse Domain Errors
+var (
+	ErrAbuseBlocked          = errors.New("ABUSE_BLOCKED", "Too many OTP requests from this network or device. Try again later", http.StatusTooManyRequests, nil)
+	ErrChallengeRequired     = errors.New("ABUSE_CHALLENGE_REQUIRED", "Solve the challenge and retry with X-Challenge-Token and X-Challenge-Nonce", http.StatusTooManyRequests, nil)
+	ErrAbuseBlockNotFound    = errors.New("ABUSE_BLOCK_NOT_FOUND", "Block not found or already expired", http.StatusNotFound, nil)
+	ErrInvalidAbuseDimension = errors.New("ABUSE_INVALID_DIMENSION", "Unknown dimension. Use: ip,device,prefix,global", http.StatusUnprocessableEntity, nil)
+)
+
+// Audit Domain Errors
+var (
+	ErrInvalidAuditOutcome = errors.New("AUDIT_INVALID_OUTCOME", "Unknown outcome. Use: success,failure,denied", http.StatusUnprocessableEntity, nil)
+)
+
+// SMS Domain Errors
+var (
+	ErrSMSMessageNotFound    = errors.New("SMS_MESSAGE_NOT_FOUND", "SMS message not found", http.StatusNotFound, nil)
+	ErrInvalidCallbackToken  = errors.New("SMS_INVALID_CALLBACK_TOKEN", "Invalid delivery report token", http.StatusUnauthorized, nil)
+	ErrUnknownDeliveryStatus = errors.New("SMS_UNKNOWN_DELIVERY_STATUS", "Unknown delivery status", http.StatusUnprocessableEntity, nil)
 )
 
 // Voice Domain Errors
@@ -34,6 +104,13 @@ var (
 	ErrUnsupportedLanguage = errors.New("VOICE_UNSUPPORTED_LANGUAGE", "Language not supported for voice analysis", http.StatusUnprocessableEntity, nil)
 	ErrTranscriptionFailed = errors.New("VOICE_TRANSCRIPTION_FAILED", "Failed to transcribe audio", http.StatusInternalServerError, nil)
 	ErrAIConnectionFailed  = errors.New("AI_CONNECTION_FAILED", "AI connection failed", http.StatusInternalServerError, nil)
+
+	ErrUnsupportedProtocol = errors.New("VOICE_UNSUPPORTED_PROTOCOL", "None of the offered voice protocol versions is supported", http.StatusUnprocessableEntity, nil)
+	ErrVoiceSessionLimit   = errors.New("VOICE_SESSION_LIMIT", "You already have an open voice session", http.StatusTooManyRequests, nil)
+	ErrVoiceSessionInUse   = errors.New("VOICE_SESSION_IN_USE", "This voice session is already connected", http.StatusConflict, nil)
+
+	// sockets are served by the instance that started the session
+	ErrVoiceSessionMisdirected = errors.New("VOICE_SESSION_MISDIRECTED", "This voice session is served by another instance", http.StatusMisdirectedRequest, nil)
 )
 
 var (
@@ -67,11 +144,17 @@ var (
 )
 
 // Helper functions for User validation
-func ValidateUserPhone(phone string) error {
-	if !strings.HasPrefix(phone, "+91") || len(phone) != 13 {
-		return ErrInvalidPhoneFormat
+
+// NormalizeUserPhone returns a phone number typed by a user in E.164.
+func NormalizeUserPhone(n *phone.Normalizer, input string) (string, error) {
+	normalized, err := n.Normalize(input)
+	if stdErrors.Is(err, phone.ErrRegionNotAllowed) {
+		return "", ErrPhoneRegionBlocked
 	}
-	return nil
+	if err != nil {
+		return "", ErrInvalidPhoneFormat
+	}
+	return normalized, nil
 }
 
 func ValidateUserLanguage(lang string) error {
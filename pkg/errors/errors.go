@@ -4,14 +4,17 @@ package errors
 import (
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // AppError is the base application error
 type AppError struct {
-	Code    string
-	Message string
-	Cause   error
-	Status  int
+	Code       string
+	Message    string
+	Cause      error
+	Status     int
+	RetryAfter int            // seconds until the client may retry, 0 if unknown
+	Details    map[string]any // extra fields sent to the client with the error
 }
 
 // Error implements the error interface
@@ -27,6 +30,38 @@ func (e *AppError) Unwrap() error {
 	return e.Cause
 }
 
+// Is matches any AppError with the same code, so copies made by WithCause
+// still satisfy errors.Is against the sentinel they came from.
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+// WithCause returns a copy of the error with cause attached
+func (e *AppError) WithCause(cause error) *AppError {
+	c := *e
+	c.Cause = cause
+	return &c
+}
+
+// WithRetryAfter returns a copy of the error telling the client how long to
+// wait, rounded up to whole seconds
+func (e *AppError) WithRetryAfter(d time.Duration) *AppError {
+	c := *e
+	c.RetryAfter = int((d + time.Second - 1) / time.Second)
+	if c.RetryAfter < 1 {
+		c.RetryAfter = 1
+	}
+	return &c
+}
+
+// WithDetails returns a copy of the error carrying details for the client
+func (e *AppError) WithDetails(details map[string]any) *AppError {
+	c := *e
+	c.Details = details
+	return &c
+}
+
 // New creates a new AppError
 func New(code, message string, status int, cause error) *AppError {
 	return &AppError{
@@ -47,6 +82,9 @@ var (
 	ErrUnauthorized = New("ERR_UNAUTHORIZED", "Unauthorized", http.StatusUnauthorized, nil)
 	ErrInvalidToken = New("ERR_INVALID_TOKEN", "Invalid or expired token", http.StatusUnauthorized, nil)
 
+	// 403 Forbidden
+	ErrForbidden = New("ERR_FORBIDDEN", "You do not have permission to perform this action", http.StatusForbidden, nil)
+
 	// 404 Not Found
 	ErrNotFound = New("ERR_NOT_FOUND", "Resource not found", http.StatusNotFound, nil)
 
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 
@@ -23,6 +24,14 @@ type ErrorResponse struct {
 // Send sends standardized error response
 func Send(c echo.Context, appErr *appErrors.AppError) error {
 	details := map[string]interface{}{}
+	for k, v := range appErr.Details {
+		details[k] = v
+	}
+
+	retryAfter := appErr.RetryAfter
+	if retryAfter == 0 && appErr.Status == http.StatusTooManyRequests {
+		retryAfter = 60
+	}
 
 	// Add specific details based on error code
 	switch appErr.Code {
@@ -40,17 +49,14 @@ func Send(c echo.Context, appErr *appErrors.AppError) error {
 		details["max_size"] = "5MB"
 	case "VISION_PDF_TOO_LARGE":
 		details["max_size"] = "10MB"
-	case "AUTH_INVALID_OTP":
-		details["retry_attempts"] = 3
 	case "AUTH_RESEND_COOLDOWN":
-		details["retry_after"] = 60
+		details["retry_after"] = retryAfter
 	case "ERR_RATE_LIMITED":
-		details["retry_after"] = 60
+		details["retry_after"] = retryAfter
 	}
 
-	retryAfter := 0
-	if appErr.Status == http.StatusTooManyRequests {
-		retryAfter = 60
+	if retryAfter > 0 {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
 	}
 
 	resp := ErrorResponse{
@@ -96,13 +102,6 @@ func ValidateUserRequest(c echo.Context, user *models.User) error {
 	}
 
 	// Custom domain validation
-	if err := domain_errors.ValidateUserPhone(user.Phone); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, map[string]interface{}{
-			"error": err.Error(),
-			"code":  err.(*appErrors.AppError).Code,
-		})
-	}
-
 	if err := domain_errors.ValidateUserLanguage(user.Language); err != nil {
 		return echo.NewHTTPError(http.StatusUnprocessableEntity, map[string]interface{}{
 			"error":     err.Error(),
@@ -0,0 +1,179 @@
+// Package jwtkeys holds the keys used to sign and verify JWTs and publishes
+// their public halves as a JWKS document.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"swasthAI/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+// Key is one signing key identified by its kid.
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	private crypto.Signer    // nil for retired, verify-only keys
+	public  crypto.PublicKey // *rsa.PublicKey or ed25519.PublicKey
+}
+
+// CanSign reports whether the private half of the key was loaded.
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+// KeySet signs tokens with the active key and verifies tokens signed by any
+// loaded key. With no keys configured it signs and verifies HS256 with the
+// shared secret.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	secret  []byte
+	legacy  bool
+}
+
+// New loads the keys listed in cfg.
+func New(cfg config.JWT) (*KeySet, error) {
+	ks := &KeySet{
+		keys:   make(map[string]*Key, len(cfg.Keys)),
+		secret: []byte(cfg.Secret),
+		legacy: cfg.LegacyHS256 || len(cfg.Keys) == 0,
+	}
+
+	for _, kc := range cfg.Keys {
+		if kc.ID == "" {
+			return nil, fmt.Errorf("jwt key %q: missing id", kc.Path)
+		}
+		if _, ok := ks.keys[kc.ID]; ok {
+			return nil, fmt.Errorf("jwt key %q: duplicate id", kc.ID)
+		}
+		data, err := os.ReadFile(kc.Path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "jwt key %q", kc.ID)
+		}
+		key, err := ParsePEM(kc.ID, data)
+		if err != nil {
+			return nil, err
+		}
+		ks.keys[kc.ID] = key
+	}
+
+	if len(ks.keys) > 0 {
+		signingID := cfg.SigningKeyID
+		if signingID == "" {
+			for _, kc := range cfg.Keys {
+				if ks.keys[kc.ID].CanSign() {
+					signingID = kc.ID
+					break
+				}
+			}
+		}
+		key, ok := ks.keys[signingID]
+		if !ok || !key.CanSign() {
+			return nil, fmt.Errorf("jwt signing key %q: no private key loaded", signingID)
+		}
+		ks.signing = key
+	}
+
+	if ks.legacy && len(ks.secret) == 0 {
+		return nil, errors.New("jwt secret is required for HS256")
+	}
+	return ks, nil
+}
+
+// ParsePEM reads an RSA or Ed25519 key, private (PKCS#8 or PKCS#1) or public
+// (PKIX).
+func ParsePEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt key %q: no PEM block found", id)
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("jwt key %q: unsupported PEM block %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "jwt key %q", id)
+	}
+
+	key := &Key{ID: id}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("jwt key %q: unsupported key type %T", id, parsed)
+	}
+	return key, nil
+}
+
+// Sign signs claims with the active key and sets its kid header.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if ks.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.secret)
+	}
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.private)
+}
+
+// Parse verifies a token against the key named by its kid, or the shared
+// secret for legacy HS256 tokens.
+func (ks *KeySet) Parse(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	opts = append(opts, jwt.WithValidMethods(ks.methods()))
+	return jwt.ParseWithClaims(tokenString, claims, ks.keyfunc, opts...)
+}
+
+func (ks *KeySet) keyfunc(token *jwt.Token) (any, error) {
+	if token.Method == jwt.SigningMethodHS256 {
+		return ks.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown jwt key %q", kid)
+	}
+	if key.Method.Alg() != token.Method.Alg() {
+		return nil, fmt.Errorf("jwt key %q does not sign %s", kid, token.Method.Alg())
+	}
+	return key.public, nil
+}
+
+func (ks *KeySet) methods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	if ks.legacy {
+		seen[jwt.SigningMethodHS256.Alg()] = true
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	for _, key := range ks.keys {
+		if alg := key.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
@@ -0,0 +1,183 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"swasthAI/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func rsaKeyFile(t *testing.T) (string, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return writePEM(t, "rsa.pem", "PRIVATE KEY", der), key
+}
+
+func ed25519KeyFiles(t *testing.T) (private, public string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	return writePEM(t, "ed.pem", "PRIVATE KEY", privDER), writePEM(t, "ed.pub.pem", "PUBLIC KEY", pubDER)
+}
+
+func testClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   "user",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+func TestKeySet_SignsWithKidAndVerifies(t *testing.T) {
+	rsaPath, _ := rsaKeyFile(t)
+	edPath, _ := ed25519KeyFiles(t)
+
+	for _, tc := range []struct {
+		name, kid, path, alg string
+	}{
+		{"rs256", "rsa-1", rsaPath, "RS256"},
+		{"eddsa", "ed-1", edPath, "EdDSA"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ks, err := New(config.JWT{SigningKeyID: tc.kid, Keys: []config.JWTKey{{ID: tc.kid, Path: tc.path}}})
+			require.NoError(t, err)
+
+			signed, err := ks.Sign(testClaims())
+			require.NoError(t, err)
+
+			token, err := ks.Parse(signed, &jwt.RegisteredClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.kid, token.Header["kid"])
+			assert.Equal(t, tc.alg, token.Method.Alg())
+		})
+	}
+}
+
+func TestKeySet_RotatedKeyStillVerifies(t *testing.T) {
+	rsaPath, _ := rsaKeyFile(t)
+	edPriv, edPub := ed25519KeyFiles(t)
+
+	old, err := New(config.JWT{Keys: []config.JWTKey{{ID: "2024-07", Path: edPriv}}})
+	require.NoError(t, err)
+	signed, err := old.Sign(testClaims())
+	require.NoError(t, err)
+
+	// new signing key, old key kept as public-only
+	ks, err := New(config.JWT{
+		SigningKeyID: "2025-01",
+		Keys: []config.JWTKey{
+			{ID: "2025-01", Path: rsaPath},
+			{ID: "2024-07", Path: edPub},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = ks.Parse(signed, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+
+	fresh, err := ks.Sign(testClaims())
+	require.NoError(t, err)
+	token, err := ks.Parse(fresh, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01", token.Header["kid"])
+}
+
+func TestKeySet_PublicKeyCannotSign(t *testing.T) {
+	_, edPub := ed25519KeyFiles(t)
+
+	_, err := New(config.JWT{SigningKeyID: "old", Keys: []config.JWTKey{{ID: "old", Path: edPub}}})
+	assert.Error(t, err)
+}
+
+func TestKeySet_LegacyHS256(t *testing.T) {
+	rsaPath, _ := rsaKeyFile(t)
+	legacy, err := New(config.JWT{Secret: "shared"})
+	require.NoError(t, err)
+	signed, err := legacy.Sign(testClaims())
+	require.NoError(t, err)
+
+	keys := []config.JWTKey{{ID: "rsa-1", Path: rsaPath}}
+
+	withLegacy, err := New(config.JWT{Secret: "shared", Keys: keys, LegacyHS256: true})
+	require.NoError(t, err)
+	_, err = withLegacy.Parse(signed, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+
+	withoutLegacy, err := New(config.JWT{Secret: "shared", Keys: keys})
+	require.NoError(t, err)
+	_, err = withoutLegacy.Parse(signed, &jwt.RegisteredClaims{})
+	assert.Error(t, err)
+}
+
+func TestKeySet_RejectsUnknownKid(t *testing.T) {
+	pathA, _ := rsaKeyFile(t)
+	pathB, _ := rsaKeyFile(t)
+
+	a, err := New(config.JWT{Keys: []config.JWTKey{{ID: "a", Path: pathA}}})
+	require.NoError(t, err)
+	b, err := New(config.JWT{Keys: []config.JWTKey{{ID: "b", Path: pathB}}})
+	require.NoError(t, err)
+
+	signed, err := a.Sign(testClaims())
+	require.NoError(t, err)
+	_, err = b.Parse(signed, &jwt.RegisteredClaims{})
+	assert.Error(t, err)
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	rsaPath, rsaKey := rsaKeyFile(t)
+	_, edPub := ed25519KeyFiles(t)
+
+	ks, err := New(config.JWT{
+		Secret: "never-published",
+		Keys: []config.JWTKey{
+			{ID: "b-ed", Path: edPub},
+			{ID: "a-rsa", Path: rsaPath},
+		},
+		LegacyHS256: true,
+	})
+	require.NoError(t, err)
+
+	set := ks.JWKS()
+	require.Len(t, set.Keys, 2)
+
+	assert.Equal(t, JWK{Kty: "RSA", Kid: "a-rsa", Use: "sig", Alg: "RS256", N: set.Keys[0].N, E: "AQAB"}, set.Keys[0])
+	assert.Equal(t, rsaKey.N.Bytes(), mustDecode(t, set.Keys[0].N))
+
+	assert.Equal(t, "OKP", set.Keys[1].Kty)
+	assert.Equal(t, "Ed25519", set.Keys[1].Crv)
+	assert.Len(t, mustDecode(t, set.Keys[1].X), ed25519.PublicKeySize)
+}
+
+func mustDecode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,140 @@
+// Package names validates person names in any script and derives the Latin
+// forms used to show and search them.
+package names
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	zwnj = '\u200c' // zero width non-joiner, used inside Indic words
+	zwj  = '\u200d' // zero width joiner
+)
+
+// IsValid reports whether s looks like a person's name: letters of any
+// script with their combining marks, separated by spaces, hyphens,
+// apostrophes or dots. Digits and other symbols are rejected.
+func IsValid(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false
+	}
+	hasLetter := false
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsMark(r), r == zwnj, r == zwj:
+		case r == ' ', r == '-', r == '\'', r == '.':
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
+
+// IsLatin reports whether s is a valid name written only in ASCII letters.
+func IsLatin(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return IsValid(s)
+}
+
+// Latin returns s in Latin letters, title-cased. Names already in Latin
+// script are returned as typed; Indic scripts are transliterated. ok is
+// false when s holds letters that cannot be romanised (e.g. Urdu), in
+// which case the Latin form has to be supplied by the user.
+func Latin(s string) (latin string, ok bool) {
+	s = strings.TrimSpace(s)
+	if IsLatin(s) {
+		return s, true
+	}
+	latin = transliterate(s)
+	if !IsLatin(latin) {
+		return "", false
+	}
+	return titleCase(latin), true
+}
+
+// LatinForm returns the Latin form to store for native: supplied when the
+// user gave one, otherwise the transliteration, or "" if there is none.
+func LatinForm(native, supplied string) string {
+	if supplied = strings.TrimSpace(supplied); supplied != "" {
+		return supplied
+	}
+	latin, _ := Latin(native)
+	return latin
+}
+
+// SearchKey folds a Latin name into a loose phonetic key, so that common
+// spellings of the same name ("Murugan", "Murukan", "Muruggan") compare
+// equal. Keys are only meant to be compared with each other.
+func SearchKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r)
+		case r == ' ', r == '-':
+			b.WriteByte(' ')
+		}
+	}
+
+	words := strings.Fields(b.String())
+	for i, w := range words {
+		words[i] = foldWord(w)
+	}
+	return strings.Join(words, " ")
+}
+
+var folds = strings.NewReplacer(
+	// long vowels
+	"aa", "a", "ee", "i", "ii", "i", "oo", "u", "uu", "u",
+	// spellings of the same sound
+	"sh", "s", "ph", "f", "ck", "k", "q", "k", "w", "v", "z", "j", "x", "ks",
+)
+
+// unvoiced maps voiced stops to their unvoiced pair; Tamil writes both
+// with the same letter, so transliterations differ.
+var unvoiced = map[byte]byte{'g': 'k', 'd': 't', 'b': 'p'}
+
+func foldWord(w string) string {
+	w = folds.Replace(w)
+
+	out := make([]byte, 0, len(w))
+	for i := 0; i < len(w); i++ {
+		c := w[i]
+		// aspiration: kh, gh, th, dh, bh, jh, ch
+		if c == 'h' && i > 0 && !isVowel(w[i-1]) {
+			continue
+		}
+		if u, ok := unvoiced[c]; ok {
+			c = u
+		}
+		if n := len(out); n > 0 && out[n-1] == c {
+			continue
+		}
+		out = append(out, c)
+	}
+	// a final schwa is written by some and dropped by others
+	if n := len(out); n > 1 && out[n-1] == 'a' {
+		out = out[:n-1]
+	}
+	return string(out)
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
+
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
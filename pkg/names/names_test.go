@@ -0,0 +1,64 @@
+package names
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValid(t *testing.T) {
+	for _, name := range []string{"Ramesh", "रमेश", "முருகன்", "অমিত", "ਗੁਰਪ੍ਰੀਤ", "ശ്രീ", "D'Souza", "Mary-Ann", "Jr.", "ആൻ"} {
+		assert.True(t, IsValid(name), name)
+	}
+	for _, name := range []string{"", "  ", "R2D2", "ram@", "--", "रमेश1"} {
+		assert.False(t, IsValid(name), name)
+	}
+}
+
+func TestLatin(t *testing.T) {
+	tests := map[string]string{
+		"Ramesh":     "Ramesh",
+		"रमेश कुमार": "Ramesh Kumar",
+		"संजय":       "Sanjay",
+		"प्रिया":     "Priya",
+		"முருகன்":    "Murukan",
+		"லக்ஷ்மி":    "Lakshmi",
+		"অমিত":       "Amit",
+		"ગીતા":       "Geeta",
+		"ಕಮಲಾ":       "Kamala",
+		"రాము":       "Ramu",
+		"അവൻ":        "Avan",
+		"ਗੁਰਪ੍ਰੀਤ":   "Gurpreet",
+		"श्री राम":   "Shri Ram",
+		"ரமேஷ்":      "Ramesh",
+		"ലക്ഷ്മി":    "Lakshmi",
+		"कमला":       "Kamla",
+		"नरेन्द्र":   "Narendra",
+	}
+	for native, want := range tests {
+		got, ok := Latin(native)
+		if assert.True(t, ok, native) {
+			assert.Equal(t, want, got, native)
+		}
+	}
+
+	_, ok := Latin("عائشہ")
+	assert.False(t, ok)
+}
+
+func TestSearchKey(t *testing.T) {
+	same := [][]string{
+		{"Murugan", "Murukan", "Muruggan"},
+		{"Priya", "Priyaa", "priya"},
+		{"Lakshmi", "Laxmi", "Lakshmee"},
+		{"Ramesh", "Rameshh", "Ramesha"},
+		{"Gurpreet", "Gurprit"},
+	}
+	for _, group := range same {
+		for _, name := range group[1:] {
+			assert.Equal(t, SearchKey(group[0]), SearchKey(name), name)
+		}
+	}
+	assert.NotEqual(t, SearchKey("Ramesh"), SearchKey("Suresh"))
+	assert.Equal(t, "rames kumar", SearchKey("Ramesh-Kumar"))
+}
@@ -0,0 +1,208 @@
+package names
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The Indic blocks from Devanagari (U+0900) to Malayalam (U+0D7F) share one
+// layout: each is 0x80 long and a letter sits at the same offset in every
+// script, so one table keyed by offset romanises all of them.
+const (
+	indicFirst = 0x0900
+	indicLast  = 0x0D7F
+	blockMask  = 0x7F
+)
+
+// block starts, for the scripts that need special handling
+const (
+	devanagari = 0x0900
+	bengali    = 0x0980
+	gurmukhi   = 0x0A00
+	gujarati   = 0x0A80
+	oriya      = 0x0B00
+)
+
+type letterKind int
+
+const (
+	other letterKind = iota
+	vowel
+	consonant
+	vowelSign
+	virama
+	chillu // consonant written without a vowel
+	nasal  // chandrabindu, anusvara
+	visarga
+	skip // nukta, avagraha, danda, stress marks
+)
+
+type letter struct {
+	kind  letterKind
+	latin string
+}
+
+var offsets = map[rune]letter{
+	0x01: {nasal, "n"}, 0x02: {nasal, "n"}, 0x03: {visarga, "h"},
+
+	0x05: {vowel, "a"}, 0x06: {vowel, "a"}, 0x07: {vowel, "i"}, 0x08: {vowel, "ee"},
+	0x09: {vowel, "u"}, 0x0A: {vowel, "oo"}, 0x0B: {vowel, "ri"}, 0x0C: {vowel, "li"},
+	0x0D: {vowel, "e"}, 0x0E: {vowel, "e"}, 0x0F: {vowel, "e"}, 0x10: {vowel, "ai"},
+	0x11: {vowel, "o"}, 0x12: {vowel, "o"}, 0x13: {vowel, "o"}, 0x14: {vowel, "au"},
+	0x60: {vowel, "ri"}, 0x61: {vowel, "li"},
+
+	0x15: {consonant, "k"}, 0x16: {consonant, "kh"}, 0x17: {consonant, "g"}, 0x18: {consonant, "gh"},
+	0x19: {consonant, "n"}, 0x1A: {consonant, "ch"}, 0x1B: {consonant, "chh"}, 0x1C: {consonant, "j"},
+	0x1D: {consonant, "jh"}, 0x1E: {consonant, "n"}, 0x1F: {consonant, "t"}, 0x20: {consonant, "th"},
+	0x21: {consonant, "d"}, 0x22: {consonant, "dh"}, 0x23: {consonant, "n"}, 0x24: {consonant, "t"},
+	0x25: {consonant, "th"}, 0x26: {consonant, "d"}, 0x27: {consonant, "dh"}, 0x28: {consonant, "n"},
+	0x29: {consonant, "n"}, 0x2A: {consonant, "p"}, 0x2B: {consonant, "ph"}, 0x2C: {consonant, "b"},
+	0x2D: {consonant, "bh"}, 0x2E: {consonant, "m"}, 0x2F: {consonant, "y"}, 0x30: {consonant, "r"},
+	0x31: {consonant, "r"}, 0x32: {consonant, "l"}, 0x33: {consonant, "l"}, 0x34: {consonant, "zh"},
+	0x35: {consonant, "v"}, 0x36: {consonant, "sh"}, 0x37: {consonant, "sh"}, 0x38: {consonant, "s"},
+	0x39: {consonant, "h"},
+	0x58: {consonant, "q"}, 0x59: {consonant, "kh"}, 0x5A: {consonant, "gh"}, 0x5B: {consonant, "z"},
+	0x5C: {consonant, "r"}, 0x5D: {consonant, "rh"}, 0x5E: {consonant, "f"}, 0x5F: {consonant, "y"},
+
+	0x3E: {vowelSign, "a"}, 0x3F: {vowelSign, "i"}, 0x40: {vowelSign, "ee"}, 0x41: {vowelSign, "u"},
+	0x42: {vowelSign, "oo"}, 0x43: {vowelSign, "ri"}, 0x44: {vowelSign, "ri"}, 0x45: {vowelSign, "e"},
+	0x46: {vowelSign, "e"}, 0x47: {vowelSign, "e"}, 0x48: {vowelSign, "ai"}, 0x49: {vowelSign, "o"},
+	0x4A: {vowelSign, "o"}, 0x4B: {vowelSign, "o"}, 0x4C: {vowelSign, "au"}, 0x62: {vowelSign, "li"},
+	0x63: {vowelSign, "li"},
+
+	0x4D: {virama, ""},
+	0x3C: {skip, ""}, 0x3D: {skip, ""}, 0x51: {skip, ""}, 0x52: {skip, ""}, 0x64: {skip, ""}, 0x65: {skip, ""},
+}
+
+// overrides are letters that do not follow the shared layout.
+var overrides = map[rune]letter{
+	0x0A70: {nasal, "n"}, // Gurmukhi tippi
+	0x0A71: {skip, ""},   // Gurmukhi addak, doubles the next consonant
+	0x0B71: {consonant, "w"},
+	0x0BD7: {skip, ""}, // Tamil au length mark
+	// Malayalam chillu letters
+	0x0D7A: {chillu, "n"}, 0x0D7B: {chillu, "n"}, 0x0D7C: {chillu, "r"},
+	0x0D7D: {chillu, "l"}, 0x0D7E: {chillu, "l"}, 0x0D7F: {chillu, "k"},
+}
+
+func lookup(r rune) letter {
+	if l, ok := overrides[r]; ok {
+		return l
+	}
+	if r < indicFirst || r > indicLast {
+		return letter{}
+	}
+	return offsets[r&blockMask]
+}
+
+// dropsSchwa reports whether the script's languages leave some inherent
+// vowels unspoken ("रमेश" is Ramesh, not Ramesha), unlike the Dravidian ones.
+func dropsSchwa(r rune) bool {
+	switch r &^ blockMask {
+	case devanagari, bengali, gurmukhi, gujarati, oriya:
+		return true
+	}
+	return false
+}
+
+// unit is one romanised letter of a word.
+type unit struct {
+	latin     string
+	consonant bool
+	schwa     bool // consonant still carrying its inherent vowel
+	cluster   bool // consonant closed by a virama
+	vowel     bool // ends in a written vowel
+	dropSchwa bool
+}
+
+// transliterate romanises the Indic letters of s in lower case and keeps
+// everything else as it is.
+func transliterate(s string) string {
+	var (
+		b    strings.Builder
+		word []unit
+	)
+	flush := func() {
+		resolveSchwa(word)
+		for i, u := range word {
+			if i == len(word)-1 {
+				// a long final vowel reads as a short one: Lakshmi, Shri
+				u.latin = finalVowels.Replace(u.latin)
+			}
+			b.WriteString(u.latin)
+			if u.schwa {
+				b.WriteByte('a')
+			}
+		}
+		word = word[:0]
+	}
+
+	for _, r := range s {
+		l := lookup(r)
+		var last *unit
+		if n := len(word); n > 0 && word[n-1].consonant {
+			last = &word[n-1]
+		}
+
+		switch l.kind {
+		case consonant:
+			word = append(word, unit{latin: l.latin, consonant: true, schwa: true, dropSchwa: dropsSchwa(r)})
+		case vowelSign:
+			if last == nil {
+				word = append(word, unit{latin: l.latin, vowel: true})
+				continue
+			}
+			last.latin += l.latin
+			last.schwa, last.vowel = false, true
+		case virama:
+			if last != nil {
+				last.schwa, last.cluster = false, true
+			}
+		case vowel:
+			word = append(word, unit{latin: l.latin, vowel: true})
+		case nasal, visarga, chillu:
+			word = append(word, unit{latin: l.latin})
+		case skip:
+		default:
+			if r == zwnj || r == zwj {
+				continue
+			}
+			flush()
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	flush()
+	return b.String()
+}
+
+var finalVowels = strings.NewReplacer("ee", "i", "oo", "u")
+
+// resolveSchwa drops the inherent vowels a Hindi-style reading leaves out:
+// the one of the last consonant unless it ends a cluster (Ramesh but
+// Narendra), and one between a vowel and a consonant that is itself
+// followed by a vowel (Kamla for कमला, Gurpreet). Words are read from the
+// end so that later deletions are known.
+func resolveSchwa(word []unit) {
+	last := len(word) - 1
+	for i := last; i > 0; i-- {
+		u := &word[i]
+		if !u.schwa || !u.dropSchwa {
+			continue
+		}
+		if i == last {
+			u.schwa = word[i-1].cluster
+			continue
+		}
+		prev := word[i-1]
+		if !(prev.vowel || prev.schwa) || prev.cluster {
+			continue
+		}
+		j := i + 1
+		for j < last && word[j].cluster {
+			j++
+		}
+		if word[i+1].consonant && (word[j].vowel || word[j].schwa) {
+			u.schwa = false
+		}
+	}
+}
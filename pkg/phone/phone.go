@@ -0,0 +1,147 @@
+// Package phone turns mobile numbers as users type them into E.164.
+package phone
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"swasthAI/config"
+)
+
+var (
+	// ErrInvalid is returned for input that is not a mobile number.
+	ErrInvalid = errors.New("phone: not a valid mobile number")
+	// ErrRegionNotAllowed is returned for valid numbers from a country this
+	// deployment does not serve.
+	ErrRegionNotAllowed = errors.New("phone: country not allowed")
+)
+
+// Normalizer canonicalises phone numbers for the configured regions.
+type Normalizer struct {
+	defaultRegion Region
+	allowed       []Region
+}
+
+// New builds a Normalizer from config. Numbers without a country code are
+// read as the default region, which is always allowed.
+func New(cfg config.Phone) (*Normalizer, error) {
+	code := strings.ToUpper(cfg.DefaultRegion)
+	if code == "" {
+		code = "IN"
+	}
+	def, ok := regions[code]
+	if !ok {
+		return nil, errors.Errorf("phone: unknown default region %q", cfg.DefaultRegion)
+	}
+
+	n := &Normalizer{defaultRegion: def, allowed: []Region{def}}
+	for _, c := range cfg.AllowedRegions {
+		r, ok := regions[strings.ToUpper(c)]
+		if !ok {
+			return nil, errors.Errorf("phone: unknown region %q", c)
+		}
+		if r.Code != def.Code {
+			n.allowed = append(n.allowed, r)
+		}
+	}
+	return n, nil
+}
+
+// Normalize returns the number in E.164, e.g. "+919876543210". It accepts
+// spaces, hyphens, dots and brackets, an international "+" or "00" prefix,
+// and national numbers with or without the trunk "0".
+func (n *Normalizer) Normalize(input string) (string, error) {
+	international, digits, err := clean(input)
+	if err != nil {
+		return "", err
+	}
+
+	if international {
+		return n.international(digits)
+	}
+
+	r := n.defaultRegion
+	switch {
+	case len(digits) == r.MobileLength:
+	case r.TrunkPrefix != "" && len(digits) == len(r.TrunkPrefix)+r.MobileLength && strings.HasPrefix(digits, r.TrunkPrefix):
+		digits = digits[len(r.TrunkPrefix):]
+	case len(digits) == len(r.CallingCode)+r.MobileLength && strings.HasPrefix(digits, r.CallingCode):
+		// calling code typed without the "+"
+		digits = digits[len(r.CallingCode):]
+	default:
+		return "", ErrInvalid
+	}
+	return format(r, digits)
+}
+
+func (n *Normalizer) international(digits string) (string, error) {
+	for _, r := range n.allowed {
+		if strings.HasPrefix(digits, r.CallingCode) {
+			return format(r, digits[len(r.CallingCode):])
+		}
+	}
+	for _, r := range regions {
+		if strings.HasPrefix(digits, r.CallingCode) {
+			if _, err := format(r, digits[len(r.CallingCode):]); err == nil {
+				return "", ErrRegionNotAllowed
+			}
+		}
+	}
+	// a calling code we do not know at all
+	if len(digits) >= 8 && len(digits) <= 15 {
+		return "", ErrRegionNotAllowed
+	}
+	return "", ErrInvalid
+}
+
+// clean strips formatting and reports whether the number was written with
+// an international prefix.
+func clean(input string) (bool, string, error) {
+	s := strings.TrimSpace(input)
+	international := false
+	switch {
+	case strings.HasPrefix(s, "+"):
+		international = true
+		s = s[1:]
+	case strings.HasPrefix(s, "00"):
+		international = true
+		s = s[2:]
+	}
+
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			b.WriteRune(c)
+		case c == ' ' || c == '-' || c == '.' || c == '(' || c == ')':
+		default:
+			return false, "", ErrInvalid
+		}
+	}
+	if b.Len() == 0 {
+		return false, "", ErrInvalid
+	}
+	return international, b.String(), nil
+}
+
+func format(r Region, national string) (string, error) {
+	// "+91 0 98765 43210" is a common way of writing it
+	if r.TrunkPrefix != "" && len(national) == len(r.TrunkPrefix)+r.MobileLength {
+		national = strings.TrimPrefix(national, r.TrunkPrefix)
+	}
+	if len(national) != r.MobileLength || !strings.ContainsRune(r.MobileLeading, rune(national[0])) {
+		return "", ErrInvalid
+	}
+	return "+" + r.CallingCode + national, nil
+}
+
+// Mask hides all but the first five and last two characters of a number,
+// e.g. "+919876543210" becomes "+9198******10", for records kept after the
+// account itself is erased.
+func Mask(number string) string {
+	if len(number) <= 7 {
+		return strings.Repeat("*", len(number))
+	}
+	return number[:5] + strings.Repeat("*", len(number)-7) + number[len(number)-2:]
+}
@@ -0,0 +1,86 @@
+package phone
+
+import (
+	"testing"
+
+	"swasthAI/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize_India(t *testing.T) {
+	n, err := New(config.Phone{DefaultRegion: "IN"})
+	require.NoError(t, err)
+
+	for _, input := range []string{
+		"+919876543210",
+		"9876543210",
+		"09876543210",
+		"98765 43210",
+		"+91-98765-43210",
+		"+91 (0) 98765 43210",
+		"919876543210",
+		"0091 98765 43210",
+		" 98765-43210 ",
+	} {
+		got, err := n.Normalize(input)
+		if assert.NoError(t, err, input) {
+			assert.Equal(t, "+919876543210", got, input)
+		}
+	}
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	n, err := New(config.Phone{DefaultRegion: "IN"})
+	require.NoError(t, err)
+
+	for _, input := range []string{
+		"",
+		"+91",
+		"5876543210",    // landline and service numbers start below 6
+		"987654321",     // too short
+		"98765432101",   // too long
+		"+9198765432",   // too short
+		"98765x43210",   // letters
+		"+91 98765 432", // truncated
+	} {
+		_, err := n.Normalize(input)
+		assert.ErrorIs(t, err, ErrInvalid, input)
+	}
+}
+
+func TestNormalize_Regions(t *testing.T) {
+	indiaOnly, err := New(config.Phone{DefaultRegion: "IN"})
+	require.NoError(t, err)
+	_, err = indiaOnly.Normalize("+977 981-2345678")
+	assert.ErrorIs(t, err, ErrRegionNotAllowed)
+	_, err = indiaOnly.Normalize("+14155550123")
+	assert.ErrorIs(t, err, ErrRegionNotAllowed)
+
+	withNepal, err := New(config.Phone{DefaultRegion: "IN", AllowedRegions: []string{"np", "BD"}})
+	require.NoError(t, err)
+	got, err := withNepal.Normalize("+977 981-2345678")
+	require.NoError(t, err)
+	assert.Equal(t, "+9779812345678", got)
+	got, err = withNepal.Normalize("+880 01712-345678")
+	require.NoError(t, err)
+	assert.Equal(t, "+8801712345678", got)
+	// national numbers are still read as Indian
+	got, err = withNepal.Normalize("09876543210")
+	require.NoError(t, err)
+	assert.Equal(t, "+919876543210", got)
+}
+
+func TestNew_UnknownRegion(t *testing.T) {
+	_, err := New(config.Phone{DefaultRegion: "XX"})
+	assert.Error(t, err)
+	_, err = New(config.Phone{DefaultRegion: "IN", AllowedRegions: []string{"ZZ"}})
+	assert.Error(t, err)
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "+9198******10", Mask("+919876543210"))
+	assert.Equal(t, "+9779*******78", Mask("+9779812345678"))
+	assert.Equal(t, "*****", Mask("12345"))
+}
@@ -0,0 +1,21 @@
+package phone
+
+// Region holds the numbering rules for mobile numbers in one country.
+type Region struct {
+	Code          string // ISO 3166-1 alpha-2, e.g. "IN"
+	CallingCode   string // without "+", e.g. "91"
+	TrunkPrefix   string // dialled before national numbers, e.g. "0"
+	MobileLength  int    // digits in a mobile number after the calling code
+	MobileLeading string // digits a mobile number may start with
+}
+
+// regions lists the countries we can accept numbers from. Only mobile
+// numbers are listed as every account needs to receive an SMS.
+var regions = map[string]Region{
+	"IN": {Code: "IN", CallingCode: "91", TrunkPrefix: "0", MobileLength: 10, MobileLeading: "6789"},
+	"NP": {Code: "NP", CallingCode: "977", TrunkPrefix: "0", MobileLength: 10, MobileLeading: "9"},
+	"BD": {Code: "BD", CallingCode: "880", TrunkPrefix: "0", MobileLength: 10, MobileLeading: "1"},
+	"LK": {Code: "LK", CallingCode: "94", TrunkPrefix: "0", MobileLength: 9, MobileLeading: "7"},
+	"PK": {Code: "PK", CallingCode: "92", TrunkPrefix: "0", MobileLength: 10, MobileLeading: "3"},
+	"BT": {Code: "BT", CallingCode: "975", MobileLength: 8, MobileLeading: "17"},
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "context"
+
+// ClientInfo describes the device and connection a request came from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	RequestID string
+	DeviceID  string
+
+	DeviceName string
+	Platform   string
+	AppVersion string
+}
+
+type clientInfoKey struct{}
+
+// WithClientInfo returns a copy of ctx carrying info.
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext returns the client info stored in ctx, or the zero
+// value if there is none.
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
@@ -1,55 +1,118 @@
 package utils
 
 import (
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
 	"swasthAI/config"
-	"swasthAI/internal/auth/models"
 	appErrors "swasthAI/pkg/errors"
+	"swasthAI/pkg/jwtkeys"
 	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// TokenType tells access tokens and refresh tokens apart, so neither can be
+// used in place of the other.
+type TokenType string
+
+const (
+	AccessToken  TokenType = "access"
+	RefreshToken TokenType = "refresh"
 )
 
-// func GenerateJWTToken(user *models.User, jwtConfig config.JWT) (string, string, error) {
-// 	return "access-token", "refresh-token", nil
-// }
+type JWTClaims struct {
+	ID          uuid.UUID `json:"id"`
+	TokenType   TokenType `json:"typ"`
+	FamilyID    uuid.UUID `json:"fid"`             // refresh token family the login belongs to
+	Roles       []string  `json:"roles,omitempty"` // access tokens only
+	Permissions []string  `json:"perms,omitempty"` // access tokens only
+	jwt.RegisteredClaims
+}
+
+// TokenSubject is the user a token pair is issued to, with the roles and
+// effective permissions carried in the access token.
+type TokenSubject struct {
+	UserID      uuid.UUID
+	Roles       []string
+	Permissions []string
+}
+
+// TokenPair is a freshly signed access and refresh token.
+type TokenPair struct {
+	AccessToken      string
+	RefreshToken     string
+	RefreshID        uuid.UUID // jti of the refresh token
+	RefreshExpiresAt time.Time
+	ExpiresIn        int // access token lifetime in seconds
+}
 
-// GenerateJWTToken generates access and refresh tokens for a user
-func GenerateJWTToken(user *models.User, jwtConfig config.JWT) (string, string, error) {
+// GenerateTokenPair signs an access and a refresh token for the user. Both
+// carry the token family so the login can be revoked as a whole.
+func GenerateTokenPair(subject TokenSubject, familyID uuid.UUID, keys *jwtkeys.KeySet, jwtConfig config.JWT) (*TokenPair, error) {
 	now := time.Now()
+	userID := subject.UserID
 
-	// Access token
 	accessClaims := JWTClaims{
-		ID: user.ID,
+		ID:          userID,
+		TokenType:   AccessToken,
+		FamilyID:    familyID,
+		Roles:       subject.Roles,
+		Permissions: subject.Permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(jwtConfig.ExpiresIn) * time.Second)),
 		},
 	}
-	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).SignedString([]byte(jwtConfig.Secret))
+	accessToken, err := keys.Sign(accessClaims)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	// Refresh token (longer expiry, e.g., 7 days)
+	refreshID := uuid.New()
+	refreshExpiresAt := now.Add(time.Duration(jwtConfig.RefreshExpiresIn) * time.Second)
 	refreshClaims := JWTClaims{
-		ID: user.ID,
+		ID:        userID,
+		TokenType: RefreshToken,
+		FamilyID:  familyID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        refreshID.String(),
 			IssuedAt:  jwt.NewNumericDate(now),
-			ExpiresAt: jwt.NewNumericDate(now.Add(7 * 24 * time.Hour)), // 7 days
+			ExpiresAt: jwt.NewNumericDate(refreshExpiresAt),
 		},
 	}
-	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString([]byte(jwtConfig.Secret))
+	refreshToken, err := keys.Sign(refreshClaims)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	return accessToken, refreshToken, nil
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		RefreshID:        refreshID,
+		RefreshExpiresAt: refreshExpiresAt,
+		ExpiresIn:        jwtConfig.ExpiresIn,
+	}, nil
 }
 
-func ValidateRefreshToken(refreshToken string, jwtConfig config.JWT) (*models.User, error) {
-	token, err := jwt.ParseWithClaims(refreshToken, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(jwtConfig.Secret), nil
-	})
+// ValidateAccessToken parses an access token. Refresh tokens are rejected.
+func ValidateAccessToken(accessToken string, keys *jwtkeys.KeySet) (*JWTClaims, error) {
+	return validateToken(accessToken, AccessToken, keys)
+}
+
+// ValidateRefreshToken parses a refresh token. Access tokens are rejected.
+func ValidateRefreshToken(refreshToken string, keys *jwtkeys.KeySet) (*JWTClaims, error) {
+	claims, err := validateToken(refreshToken, RefreshToken, keys)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(claims.RegisteredClaims.ID); err != nil {
+		return nil, appErrors.ErrJWTInvalidClaims
+	}
+	return claims, nil
+}
+
+func validateToken(tokenString string, typ TokenType, keys *jwtkeys.KeySet) (*JWTClaims, error) {
+	token, err := keys.Parse(tokenString, &JWTClaims{}, jwt.WithExpirationRequired())
 	if err != nil {
 		return nil, appErrors.ErrInvalidJWTToken
 	}
@@ -58,21 +121,12 @@ func ValidateRefreshToken(refreshToken string, jwtConfig config.JWT) (*models.Us
 	if !ok || !token.Valid {
 		return nil, appErrors.ErrInvalidJWTToken
 	}
-
-	// Optionally check expiry explicitly
-	if claims.ExpiresAt.Time.Before(time.Now()) {
-		return nil, appErrors.ErrJWTExpired
+	if claims.TokenType != typ {
+		return nil, appErrors.ErrInvalidJWTToken
+	}
+	if claims.ID == uuid.Nil {
+		return nil, appErrors.ErrJWTInvalidClaims
 	}
 
-	user := &models.User{ID: claims.ID}
-	return user, nil
-}
-
-func ValidateToken(token string, jwtConfig config.JWT) (*models.User, error) {
-	return nil, nil
-}
-
-type JWTClaims struct {
-	ID uuid.UUID `json:"id"`
-	jwt.RegisteredClaims
+	return claims, nil
 }
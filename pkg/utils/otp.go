@@ -1,5 +1,36 @@
 package utils
 
-func GenerateOTP() string {
-	return "1234"
+import (
+	"crypto/rand"
+	"math/big"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// GenerateOTP returns a numeric code of the given length read from crypto/rand.
+func GenerateOTP(length int) (string, error) {
+	code := make([]byte, length)
+	ten := big.NewInt(10)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, ten)
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+	return string(code), nil
+}
+
+// HashOTP returns a bcrypt hash of the code suitable for storage.
+func HashOTP(code string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CompareOTP reports whether code matches the stored hash.
+func CompareOTP(hash, code string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(code)) == nil
 }
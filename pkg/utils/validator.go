@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/go-playground/validator/v10"
+
+	"swasthAI/pkg/names"
 )
 
 // Use a single instance of Validate, it caches struct info
@@ -11,6 +13,13 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+	// person names in any script, and their Latin forms
+	validate.RegisterValidation("name", func(fl validator.FieldLevel) bool {
+		return names.IsValid(fl.Field().String())
+	})
+	validate.RegisterValidation("latinname", func(fl validator.FieldLevel) bool {
+		return names.IsLatin(fl.Field().String())
+	})
 }
 
 // Validate struct fields